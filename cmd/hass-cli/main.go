@@ -2,7 +2,6 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/dorinclisu/hass-cli/internal/cli"
@@ -14,7 +13,7 @@ var Version = "dev"
 func main() {
 	cli.SetVersion(Version)
 	if err := cli.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		cli.ReportError(err)
+		os.Exit(cli.ExitCode(err))
 	}
 }
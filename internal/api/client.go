@@ -2,10 +2,15 @@ package api
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
 	"time"
 )
@@ -15,27 +20,95 @@ type Client struct {
 	baseURL    string
 	token      string
 	httpClient *http.Client
+	maxRetries int
+	retryDelay time.Duration
+	headers    http.Header
+}
+
+// ClientOption configures optional Client behavior.
+type ClientOption func(*Client)
+
+// WithRetry makes the client retry GET requests that fail with a 502, 503 or
+// 504 response or a network timeout, up to maxRetries times. The delay before
+// retry n is baseDelay * 2^(n-1). Other methods are never retried, so a
+// service call is not made twice.
+func WithRetry(maxRetries int, baseDelay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryDelay = baseDelay
+	}
+}
+
+// WithHeaders adds headers to every request. They are sent on top of the
+// Authorization header, which they cannot replace.
+func WithHeaders(headers http.Header) ClientOption {
+	return func(c *Client) {
+		c.headers = headers
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used for https:// URLs.
+func WithTLSConfig(tlsConfig *tls.Config) ClientOption {
+	return func(c *Client) {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		c.httpClient.Transport = transport
+	}
 }
 
 // NewClient creates a new Home Assistant API client.
 func NewClient(baseURL, token string, timeout time.Duration) *Client {
-	return &Client{
+	return NewClientWithOptions(baseURL, token, timeout)
+}
+
+// NewClientWithOptions creates a new Home Assistant API client with options.
+func NewClientWithOptions(baseURL, token string, timeout time.Duration, opts ...ClientOption) *Client {
+	c := &Client{
 		baseURL: strings.TrimSuffix(baseURL, "/"),
 		token:   token,
 		httpClient: &http.Client{
 			Timeout: timeout,
 		},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
-// doRequest performs an HTTP request and returns the response.
+// doRequest performs an HTTP request and returns the response, retrying
+// transient failures of GET requests according to the retry policy.
 func (c *Client) doRequest(method, path string, body interface{}) (*http.Response, error) {
-	var bodyReader io.Reader
+	var jsonData []byte
 	if body != nil {
-		jsonData, err := json.Marshal(body)
+		var err error
+		jsonData, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
+	}
+
+	retries := 0
+	if method == "GET" {
+		retries = c.maxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(method, path, jsonData)
+		if attempt >= retries || !isRetryable(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		time.Sleep(c.retryDelay << attempt)
+	}
+}
+
+// send performs a single HTTP request.
+func (c *Client) send(method, path string, jsonData []byte) (*http.Response, error) {
+	var bodyReader io.Reader
+	if jsonData != nil {
 		bodyReader = bytes.NewReader(jsonData)
 	}
 
@@ -45,8 +118,11 @@ func (c *Client) doRequest(method, path string, body interface{}) (*http.Respons
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.token)
 	req.Header.Set("Content-Type", "application/json")
+	for key, values := range c.headers {
+		req.Header[key] = values
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -56,6 +132,22 @@ func (c *Client) doRequest(method, path string, body interface{}) (*http.Respons
 	return resp, nil
 }
 
+// isRetryable reports whether a request failed in a way that is worth
+// retrying: a gateway error from a proxy in front of Home Assistant, or a
+// network timeout.
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout()
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
 // CheckConnection verifies that the API is accessible and the token is valid.
 func (c *Client) CheckConnection() error {
 	resp, err := c.doRequest("GET", "/api/", nil)
@@ -278,6 +370,240 @@ func (c *Client) SetState(entityID string, state string, attributes map[string]i
 	return &resultState, nil
 }
 
+// DeleteState removes an entity's state object. It returns ErrNotFound when
+// the entity has no state.
+func (c *Client) DeleteState(entityID string) error {
+	resp, err := c.doRequest("DELETE", "/api/states/"+entityID, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 401 {
+		return ErrUnauthorized
+	}
+
+	if resp.StatusCode == 404 {
+		return ErrNotFound
+	}
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    string(body),
+		}
+	}
+
+	return nil
+}
+
+// GetHistory returns the state history of the given entities between start
+// and end, as one list of states per entity in chronological order.
+//
+// The request uses minimal_response, so only the first state of each list
+// carries attributes; later states have just State and LastChanged set.
+// EntityID is filled in on every state. Entities that are unknown or have
+// no history in the period are simply absent from the result: Home
+// Assistant returns an empty list for them rather than an error.
+func (c *Client) GetHistory(entityIDs []string, start, end time.Time) ([][]State, error) {
+	query := url.Values{}
+	query.Set("filter_entity_id", strings.Join(entityIDs, ","))
+	query.Set("end_time", end.UTC().Format(time.RFC3339))
+	query.Set("minimal_response", "")
+
+	path := "/api/history/period/" + url.PathEscape(start.UTC().Format(time.RFC3339)) + "?" + query.Encode()
+	resp, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 401 {
+		return nil, ErrUnauthorized
+	}
+
+	if resp.StatusCode == 404 {
+		return nil, ErrNotFound
+	}
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    string(body),
+		}
+	}
+
+	var history [][]State
+	if err := json.NewDecoder(resp.Body).Decode(&history); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// Drop empty lists and fill in the entity ID minimal responses omit
+	result := make([][]State, 0, len(history))
+	for _, states := range history {
+		if len(states) == 0 {
+			continue
+		}
+		for i := range states {
+			if states[i].EntityID == "" {
+				states[i].EntityID = states[0].EntityID
+			}
+		}
+		result = append(result, states)
+	}
+
+	return result, nil
+}
+
+// LogbookEntry is a single entry of the logbook.
+type LogbookEntry struct {
+	When          string `json:"when"`
+	Name          string `json:"name"`
+	Message       string `json:"message,omitempty"`
+	EntityID      string `json:"entity_id,omitempty"`
+	Domain        string `json:"domain,omitempty"`
+	ContextUserID string `json:"context_user_id,omitempty"`
+}
+
+// GetLogbook returns the logbook entries between start and end, optionally
+// limited to a single entity (pass "" for all entities).
+func (c *Client) GetLogbook(start, end time.Time, entityID string) ([]LogbookEntry, error) {
+	query := url.Values{}
+	query.Set("end_time", end.UTC().Format(time.RFC3339))
+	if entityID != "" {
+		query.Set("entity", entityID)
+	}
+
+	path := "/api/logbook/" + url.PathEscape(start.UTC().Format(time.RFC3339)) + "?" + query.Encode()
+	resp, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 401 {
+		return nil, ErrUnauthorized
+	}
+
+	if resp.StatusCode == 404 {
+		return nil, ErrNotFound
+	}
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    string(body),
+		}
+	}
+
+	var entries []LogbookEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Calendar is a calendar entity.
+type Calendar struct {
+	EntityID string `json:"entity_id"`
+	Name     string `json:"name"`
+}
+
+// CalendarEvent is an event of a calendar.
+type CalendarEvent struct {
+	Summary     string            `json:"summary"`
+	Start       CalendarEventTime `json:"start"`
+	End         CalendarEventTime `json:"end"`
+	Description string            `json:"description,omitempty"`
+	Location    string            `json:"location,omitempty"`
+}
+
+// CalendarEventTime is the start or end of a calendar event. All-day events
+// have a Date, others a DateTime.
+type CalendarEventTime struct {
+	DateTime string `json:"dateTime,omitempty"`
+	Date     string `json:"date,omitempty"`
+}
+
+// AllDay reports whether the time is a date without a time of day.
+func (t CalendarEventTime) AllDay() bool {
+	return t.DateTime == "" && t.Date != ""
+}
+
+// GetCalendars returns all calendar entities.
+func (c *Client) GetCalendars() ([]Calendar, error) {
+	resp, err := c.doRequest("GET", "/api/calendars", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 401 {
+		return nil, ErrUnauthorized
+	}
+
+	if resp.StatusCode == 404 {
+		return nil, ErrNotFound
+	}
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    string(body),
+		}
+	}
+
+	var calendars []Calendar
+	if err := json.NewDecoder(resp.Body).Decode(&calendars); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return calendars, nil
+}
+
+// GetCalendarEvents returns the events of a calendar entity between start
+// and end.
+func (c *Client) GetCalendarEvents(entityID string, start, end time.Time) ([]CalendarEvent, error) {
+	query := url.Values{}
+	query.Set("start", start.UTC().Format(time.RFC3339))
+	query.Set("end", end.UTC().Format(time.RFC3339))
+
+	path := "/api/calendars/" + url.PathEscape(entityID) + "?" + query.Encode()
+	resp, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 401 {
+		return nil, ErrUnauthorized
+	}
+
+	if resp.StatusCode == 404 {
+		return nil, ErrNotFound
+	}
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    string(body),
+		}
+	}
+
+	var events []CalendarEvent
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return events, nil
+}
+
 // Service represents a service domain with its services.
 type Service struct {
 	Domain   string                 `json:"domain"`
@@ -293,13 +619,16 @@ type ServiceInfo struct {
 	Response    *ServiceResponseInfo      `json:"response,omitempty"`
 }
 
-// ServiceField represents a field in a service.
+// ServiceField represents a field in a service. A section, such as
+// advanced_fields, groups further fields under Fields instead; those are
+// still sent at the top level of the service data.
 type ServiceField struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	Required    bool        `json:"required"`
-	Example     interface{} `json:"example"`
-	Selector    interface{} `json:"selector"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	Required    bool                    `json:"required"`
+	Example     interface{}             `json:"example"`
+	Selector    interface{}             `json:"selector"`
+	Fields      map[string]ServiceField `json:"fields,omitempty"`
 }
 
 // ServiceTarget represents the target configuration for a service.
@@ -401,12 +730,190 @@ func (c *Client) CallService(domain, service string, data map[string]interface{}
 	return changedStates, nil
 }
 
+// ReloadableDomains lists the domains ReloadDomain accepts. Each exposes a
+// "<domain>.reload" service that re-reads its configuration without a
+// restart.
+var ReloadableDomains = []string{
+	"automation",
+	"script",
+	"scene",
+	"input_boolean",
+	"input_button",
+	"input_datetime",
+	"input_number",
+	"input_select",
+	"input_text",
+}
+
+// ReloadDomain reloads the configuration of one of the ReloadableDomains so
+// that created, edited or deleted items take effect.
+func (c *Client) ReloadDomain(domain string) error {
+	if !slices.Contains(ReloadableDomains, domain) {
+		return fmt.Errorf("domain %s cannot be reloaded", domain)
+	}
+
+	_, err := c.CallService(domain, "reload", nil)
+	return err
+}
+
+// RenderTemplate renders a Jinja template on the server and returns the
+// result. Template errors are returned as an APIError carrying the message
+// Home Assistant reports for them.
+func (c *Client) RenderTemplate(template string) (string, error) {
+	resp, err := c.doRequest("POST", "/api/template", map[string]string{"template": template})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 401 {
+		return "", ErrUnauthorized
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		// Errors come back as {"message": "..."}
+		message := string(body)
+		var errBody struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &errBody) == nil && errBody.Message != "" {
+			message = errBody.Message
+		}
+		return "", &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    message,
+		}
+	}
+
+	return string(body), nil
+}
+
+// GetErrorLog returns the Home Assistant error log as plain text.
+func (c *Client) GetErrorLog() (string, error) {
+	resp, err := c.doRequest("GET", "/api/error_log", nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 401 {
+		return "", ErrUnauthorized
+	}
+
+	if resp.StatusCode == 404 {
+		return "", ErrNotFound
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		return "", &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    string(body),
+		}
+	}
+
+	return string(body), nil
+}
+
+// ConversationResult is the reply of the conversation (Assist) API.
+type ConversationResult struct {
+	Response             ConversationResponse `json:"response"`
+	ConversationID       string               `json:"conversation_id,omitempty"`
+	ContinueConversation bool                 `json:"continue_conversation,omitempty"`
+}
+
+// ConversationResponse is what the assistant answered.
+type ConversationResponse struct {
+	ResponseType string                        `json:"response_type"` // action_done, query_answer or error
+	Language     string                        `json:"language"`
+	Speech       map[string]ConversationSpeech `json:"speech"`
+	Data         ConversationData              `json:"data"`
+}
+
+// ConversationSpeech is one rendering of the response text, keyed by
+// format ("plain" or "ssml") in ConversationResponse.Speech.
+type ConversationSpeech struct {
+	Speech string `json:"speech"`
+}
+
+// ConversationData lists what an intent acted on, or the error code of a
+// failed one.
+type ConversationData struct {
+	Code    string               `json:"code,omitempty"`
+	Targets []ConversationTarget `json:"targets,omitempty"`
+	Success []ConversationTarget `json:"success,omitempty"`
+	Failed  []ConversationTarget `json:"failed,omitempty"`
+}
+
+// ConversationTarget is an entity, area or domain an intent acted on.
+type ConversationTarget struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// PlainSpeech returns the plain text of the response.
+func (r ConversationResponse) PlainSpeech() string {
+	return r.Speech["plain"].Speech
+}
+
+// ProcessConversation sends text to the conversation agent, as if spoken to
+// Assist. language and conversationID may be empty to use the defaults and
+// start a new conversation.
+func (c *Client) ProcessConversation(text, language, conversationID string) (*ConversationResult, error) {
+	body := map[string]string{"text": text}
+	if language != "" {
+		body["language"] = language
+	}
+	if conversationID != "" {
+		body["conversation_id"] = conversationID
+	}
+
+	resp, err := c.doRequest("POST", "/api/conversation/process", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 401 {
+		return nil, ErrUnauthorized
+	}
+
+	if resp.StatusCode == 404 {
+		return nil, ErrNotFound
+	}
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    string(body),
+		}
+	}
+
+	var result ConversationResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &result, nil
+}
+
 // SceneConfig represents a scene configuration.
 type SceneConfig struct {
-	ID       string                            `json:"id"`
-	Name     string                            `json:"name"`
-	Entities map[string]map[string]interface{} `json:"entities"`
-	Icon     string                            `json:"icon,omitempty"`
+	ID       string                            `json:"id" yaml:"id"`
+	Name     string                            `json:"name" yaml:"name"`
+	Entities map[string]map[string]interface{} `json:"entities" yaml:"entities"`
+	Icon     string                            `json:"icon,omitempty" yaml:"icon,omitempty"`
 }
 
 // GetSceneConfig retrieves the configuration for a specific scene.
@@ -598,49 +1105,79 @@ func (c *Client) DeleteScript(scriptID string) error {
 
 // AutomationConfig represents an automation configuration.
 type AutomationConfig struct {
-	ID          string                   `json:"id,omitempty"`
-	Alias       string                   `json:"alias"`
-	Description string                   `json:"description,omitempty"`
-	Triggers    []map[string]interface{} `json:"triggers,omitempty"`
-	Conditions  []map[string]interface{} `json:"conditions,omitempty"`
-	Actions     []map[string]interface{} `json:"actions,omitempty"`
-	Mode        string                   `json:"mode,omitempty"`
+	ID          string                   `json:"id,omitempty" yaml:"id,omitempty"`
+	Alias       string                   `json:"alias" yaml:"alias"`
+	Description string                   `json:"description,omitempty" yaml:"description,omitempty"`
+	Triggers    []map[string]interface{} `json:"triggers,omitempty" yaml:"triggers,omitempty"`
+	Conditions  []map[string]interface{} `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	Actions     []map[string]interface{} `json:"actions,omitempty" yaml:"actions,omitempty"`
+	Mode        string                   `json:"mode,omitempty" yaml:"mode,omitempty"`
 }
 
 // GetAutomationConfig retrieves the configuration for a specific automation.
 func (c *Client) GetAutomationConfig(automationID string) (*AutomationConfig, error) {
+	var config AutomationConfig
+	if err := c.getAutomationConfig(automationID, &config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// GetAutomationConfigRaw retrieves the configuration for a specific automation
+// exactly as Home Assistant stores it, including keys AutomationConfig does
+// not model, such as variables or the legacy singular trigger/action keys.
+func (c *Client) GetAutomationConfigRaw(automationID string) (map[string]interface{}, error) {
+	var config map[string]interface{}
+	if err := c.getAutomationConfig(automationID, &config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func (c *Client) getAutomationConfig(automationID string, config interface{}) error {
 	resp, err := c.doRequest("GET", "/api/config/automation/config/"+automationID, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 401 {
-		return nil, ErrUnauthorized
+		return ErrUnauthorized
 	}
 
 	if resp.StatusCode == 404 {
-		return nil, ErrNotFound
+		return ErrNotFound
 	}
 
 	if resp.StatusCode != 200 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, &APIError{
+		return &APIError{
 			StatusCode: resp.StatusCode,
 			Message:    string(body),
 		}
 	}
 
-	var config AutomationConfig
-	if err := json.NewDecoder(resp.Body).Decode(&config); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(config); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return &config, nil
+	return nil
 }
 
 // CreateAutomation creates a new automation.
 func (c *Client) CreateAutomation(automationID string, config *AutomationConfig) error {
+	return c.saveAutomation(automationID, config)
+}
+
+// CreateAutomationRaw creates a new automation from a raw configuration, as
+// returned by GetAutomationConfigRaw.
+func (c *Client) CreateAutomationRaw(automationID string, config map[string]interface{}) error {
+	return c.saveAutomation(automationID, config)
+}
+
+func (c *Client) saveAutomation(automationID string, config interface{}) error {
 	resp, err := c.doRequest("POST", "/api/config/automation/config/"+automationID, config)
 	if err != nil {
 		return err
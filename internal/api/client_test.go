@@ -1,9 +1,16 @@
 package api
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"io"
+	"log"
 	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -199,6 +206,229 @@ func TestGetState(t *testing.T) {
 	})
 }
 
+func TestDeleteState(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mock := testutil.NewRESTMock(t, testToken)
+		mock.Handle("DELETE", "/api/states/sensor.custom", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(200)
+			w.Write([]byte(`{"message": "Entity removed."}`))
+		})
+
+		client := NewClient(mock.URL(), testToken, 5*time.Second)
+		if err := client.DeleteState("sensor.custom"); err != nil {
+			t.Errorf("DeleteState() error = %v", err)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock := testutil.NewRESTMock(t, testToken)
+		mock.Handle("DELETE", "/api/states/sensor.missing", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(404)
+		})
+
+		client := NewClient(mock.URL(), testToken, 5*time.Second)
+		err := client.DeleteState("sensor.missing")
+		if !IsNotFound(err) {
+			t.Errorf("DeleteState() error = %v, want not found", err)
+		}
+	})
+}
+
+func TestGetHistory(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	t.Run("success", func(t *testing.T) {
+		mock := testutil.NewRESTMock(t, testToken)
+		mock.Handle("GET", "/api/history/period/*", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/history/period/2024-01-15T10:00:00Z" {
+				t.Errorf("path = %q", r.URL.Path)
+			}
+			q := r.URL.Query()
+			if got := q.Get("filter_entity_id"); got != "light.kitchen,sensor.unknown" {
+				t.Errorf("filter_entity_id = %q", got)
+			}
+			if got := q.Get("end_time"); got != "2024-01-16T10:00:00Z" {
+				t.Errorf("end_time = %q", got)
+			}
+			if _, ok := q["minimal_response"]; !ok {
+				t.Error("minimal_response not set")
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[[
+				{"entity_id":"light.kitchen","state":"off","attributes":{"friendly_name":"Kitchen"},"last_changed":"2024-01-15T10:00:00+00:00"},
+				{"state":"on","last_changed":"2024-01-15T18:30:00+00:00"}
+			],[]]`))
+		})
+
+		client := NewClient(mock.URL(), testToken, 5*time.Second)
+		history, err := client.GetHistory([]string{"light.kitchen", "sensor.unknown"}, start, end)
+		if err != nil {
+			t.Fatalf("GetHistory() error = %v", err)
+		}
+		if len(history) != 1 {
+			t.Fatalf("GetHistory() returned %d entities, want 1", len(history))
+		}
+		if len(history[0]) != 2 {
+			t.Fatalf("history[0] has %d states, want 2", len(history[0]))
+		}
+		if history[0][1].EntityID != "light.kitchen" {
+			t.Errorf("history[0][1].EntityID = %q, want %q", history[0][1].EntityID, "light.kitchen")
+		}
+		if history[0][1].State != "on" {
+			t.Errorf("history[0][1].State = %q, want %q", history[0][1].State, "on")
+		}
+	})
+
+	t.Run("no history", func(t *testing.T) {
+		mock := testutil.NewRESTMock(t, testToken)
+		mock.HandleJSON("GET", "/api/history/period/*", 200, []interface{}{})
+
+		client := NewClient(mock.URL(), testToken, 5*time.Second)
+		history, err := client.GetHistory([]string{"sensor.unknown"}, start, end)
+		if err != nil {
+			t.Fatalf("GetHistory() error = %v", err)
+		}
+		if len(history) != 0 {
+			t.Errorf("GetHistory() returned %d entities, want 0", len(history))
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock := testutil.NewRESTMock(t, testToken)
+
+		client := NewClient(mock.URL(), testToken, 5*time.Second)
+		_, err := client.GetHistory([]string{"light.kitchen"}, start, end)
+		if !IsNotFound(err) {
+			t.Errorf("GetHistory() error = %v, want not found", err)
+		}
+	})
+}
+
+func TestGetLogbook(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	t.Run("success", func(t *testing.T) {
+		mock := testutil.NewRESTMock(t, testToken)
+		mock.Handle("GET", "/api/logbook/*", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/logbook/2024-01-15T10:00:00Z" {
+				t.Errorf("path = %q", r.URL.Path)
+			}
+			if got := r.URL.Query().Get("entity"); got != "light.kitchen" {
+				t.Errorf("entity = %q, want %q", got, "light.kitchen")
+			}
+			if got := r.URL.Query().Get("end_time"); got != "2024-01-16T10:00:00Z" {
+				t.Errorf("end_time = %q", got)
+			}
+			json.NewEncoder(w).Encode([]LogbookEntry{
+				{When: "2024-01-15T18:30:00+00:00", Name: "Kitchen", Message: "turned on", EntityID: "light.kitchen", Domain: "light", ContextUserID: "user1"},
+			})
+		})
+
+		client := NewClient(mock.URL(), testToken, 5*time.Second)
+		entries, err := client.GetLogbook(start, end, "light.kitchen")
+		if err != nil {
+			t.Fatalf("GetLogbook() error = %v", err)
+		}
+		if len(entries) != 1 {
+			t.Fatalf("GetLogbook() returned %d entries, want 1", len(entries))
+		}
+		if entries[0].Message != "turned on" {
+			t.Errorf("Message = %q, want %q", entries[0].Message, "turned on")
+		}
+		if entries[0].ContextUserID != "user1" {
+			t.Errorf("ContextUserID = %q, want %q", entries[0].ContextUserID, "user1")
+		}
+	})
+
+	t.Run("all entities", func(t *testing.T) {
+		mock := testutil.NewRESTMock(t, testToken)
+		mock.Handle("GET", "/api/logbook/*", func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := r.URL.Query()["entity"]; ok {
+				t.Error("entity filter set, want none")
+			}
+			w.Write([]byte("[]"))
+		})
+
+		client := NewClient(mock.URL(), testToken, 5*time.Second)
+		entries, err := client.GetLogbook(start, end, "")
+		if err != nil {
+			t.Fatalf("GetLogbook() error = %v", err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("GetLogbook() returned %d entries, want 0", len(entries))
+		}
+	})
+}
+
+func TestGetCalendars(t *testing.T) {
+	mock := testutil.NewRESTMock(t, testToken)
+	mock.Handle("GET", "/api/calendars", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"entity_id":"calendar.family","name":"Family"}]`))
+	})
+
+	client := NewClient(mock.URL(), testToken, 5*time.Second)
+	calendars, err := client.GetCalendars()
+	if err != nil {
+		t.Fatalf("GetCalendars() error = %v", err)
+	}
+	if len(calendars) != 1 || calendars[0].EntityID != "calendar.family" || calendars[0].Name != "Family" {
+		t.Errorf("GetCalendars() = %+v", calendars)
+	}
+}
+
+func TestGetCalendarEvents(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	end := start.Add(7 * 24 * time.Hour)
+
+	t.Run("success", func(t *testing.T) {
+		mock := testutil.NewRESTMock(t, testToken)
+		mock.Handle("GET", "/api/calendars/*", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/calendars/calendar.family" {
+				t.Errorf("path = %q", r.URL.Path)
+			}
+			if got := r.URL.Query().Get("start"); got != "2024-01-15T10:00:00Z" {
+				t.Errorf("start = %q", got)
+			}
+			if got := r.URL.Query().Get("end"); got != "2024-01-22T10:00:00Z" {
+				t.Errorf("end = %q", got)
+			}
+			w.Write([]byte(`[
+				{"summary":"Dentist","start":{"dateTime":"2024-01-16T09:00:00+01:00"},"end":{"dateTime":"2024-01-16T10:00:00+01:00"},"description":"Bring card"},
+				{"summary":"Holiday","start":{"date":"2024-01-18"},"end":{"date":"2024-01-19"}}
+			]`))
+		})
+
+		client := NewClient(mock.URL(), testToken, 5*time.Second)
+		events, err := client.GetCalendarEvents("calendar.family", start, end)
+		if err != nil {
+			t.Fatalf("GetCalendarEvents() error = %v", err)
+		}
+		if len(events) != 2 {
+			t.Fatalf("GetCalendarEvents() returned %d events, want 2", len(events))
+		}
+		if events[0].Summary != "Dentist" || events[0].Start.DateTime != "2024-01-16T09:00:00+01:00" || events[0].Description != "Bring card" {
+			t.Errorf("events[0] = %+v", events[0])
+		}
+		if events[0].Start.AllDay() || !events[1].Start.AllDay() {
+			t.Errorf("AllDay() = %v, %v, want false, true", events[0].Start.AllDay(), events[1].Start.AllDay())
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock := testutil.NewRESTMock(t, testToken)
+		mock.Handle("GET", "/api/calendars/*", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		client := NewClient(mock.URL(), testToken, 5*time.Second)
+		if _, err := client.GetCalendarEvents("calendar.missing", start, end); !IsNotFound(err) {
+			t.Errorf("GetCalendarEvents() error = %v, want not found", err)
+		}
+	})
+}
+
 func TestSetState(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mock := testutil.NewRESTMock(t, testToken)
@@ -323,6 +553,166 @@ func TestCallService(t *testing.T) {
 	})
 }
 
+func TestCallInputSelectSetOptions(t *testing.T) {
+	mock := testutil.NewRESTMock(t, testToken)
+	mock.Handle("POST", "/api/services/input_select/set_options", func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			EntityID string   `json:"entity_id"`
+			Options  []string `json:"options"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		if payload.EntityID != "input_select.mode" {
+			t.Errorf("entity_id = %q, want input_select.mode", payload.EntityID)
+		}
+		if strings.Join(payload.Options, ",") != "home,away" {
+			t.Errorf("options = %v, want [home away]", payload.Options)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+
+	client := NewClient(mock.URL(), testToken, 5*time.Second)
+	if err := client.CallInputSelectSetOptions("input_select.mode", []string{"home", "away"}); err != nil {
+		t.Fatalf("CallInputSelectSetOptions() error = %v", err)
+	}
+}
+
+func TestReloadDomain(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mock := testutil.NewRESTMock(t, testToken)
+		mock.Handle("POST", "/api/services/input_select/reload", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[]`))
+		})
+
+		client := NewClient(mock.URL(), testToken, 5*time.Second)
+		if err := client.ReloadDomain("input_select"); err != nil {
+			t.Errorf("ReloadDomain() error = %v", err)
+		}
+	})
+
+	t.Run("unsupported domain", func(t *testing.T) {
+		client := NewClient("http://localhost:1", testToken, 5*time.Second)
+		if err := client.ReloadDomain("light"); err == nil {
+			t.Error("ReloadDomain() expected error for light")
+		}
+	})
+}
+
+func TestGetErrorLog(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mock := testutil.NewRESTMock(t, testToken)
+		mock.Handle("GET", "/api/error_log", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("2024-01-15 10:00:00 ERROR (MainThread) [homeassistant.setup] Setup failed\n"))
+		})
+
+		client := NewClient(mock.URL(), testToken, 5*time.Second)
+		got, err := client.GetErrorLog()
+		if err != nil {
+			t.Fatalf("GetErrorLog() error = %v", err)
+		}
+		if got != "2024-01-15 10:00:00 ERROR (MainThread) [homeassistant.setup] Setup failed\n" {
+			t.Errorf("GetErrorLog() = %q", got)
+		}
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		mock := testutil.NewRESTMock(t, testToken)
+
+		client := NewClient(mock.URL(), "bad-token", 5*time.Second)
+		_, err := client.GetErrorLog()
+		if !IsUnauthorized(err) {
+			t.Errorf("GetErrorLog() error = %v, want unauthorized", err)
+		}
+	})
+}
+
+func TestProcessConversation(t *testing.T) {
+	mock := testutil.NewRESTMock(t, testToken)
+	mock.Handle("POST", "/api/conversation/process", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["text"] != "turn off the kitchen lights" || body["language"] != "en" {
+			t.Errorf("body = %v", body)
+		}
+		if _, ok := body["conversation_id"]; ok {
+			t.Error("conversation_id set, want none")
+		}
+		w.Write([]byte(`{
+			"response": {
+				"response_type": "action_done",
+				"language": "en",
+				"speech": {"plain": {"speech": "Turned off the lights", "extra_data": null}},
+				"data": {"targets": [], "success": [{"name": "Kitchen", "type": "area", "id": "kitchen"}], "failed": []}
+			},
+			"conversation_id": "01HX"
+		}`))
+	})
+
+	client := NewClient(mock.URL(), testToken, 5*time.Second)
+	result, err := client.ProcessConversation("turn off the kitchen lights", "en", "")
+	if err != nil {
+		t.Fatalf("ProcessConversation() error = %v", err)
+	}
+	if got := result.Response.PlainSpeech(); got != "Turned off the lights" {
+		t.Errorf("PlainSpeech() = %q", got)
+	}
+	if result.ConversationID != "01HX" || result.Response.ResponseType != "action_done" {
+		t.Errorf("result = %+v", result)
+	}
+	if len(result.Response.Data.Success) != 1 || result.Response.Data.Success[0].ID != "kitchen" {
+		t.Errorf("success = %+v", result.Response.Data.Success)
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mock := testutil.NewRESTMock(t, testToken)
+		mock.Handle("POST", "/api/template", func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["template"] != "{{ states('sun.sun') }}" {
+				t.Errorf("template = %q", body["template"])
+			}
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("above_horizon"))
+		})
+
+		client := NewClient(mock.URL(), testToken, 5*time.Second)
+		got, err := client.RenderTemplate("{{ states('sun.sun') }}")
+		if err != nil {
+			t.Fatalf("RenderTemplate() error = %v", err)
+		}
+		if got != "above_horizon" {
+			t.Errorf("RenderTemplate() = %q, want %q", got, "above_horizon")
+		}
+	})
+
+	t.Run("template error", func(t *testing.T) {
+		mock := testutil.NewRESTMock(t, testToken)
+		mock.HandleJSON("POST", "/api/template", 400, map[string]string{
+			"message": "Error rendering template: TemplateSyntaxError: unexpected '}'",
+		})
+
+		client := NewClient(mock.URL(), testToken, 5*time.Second)
+		_, err := client.RenderTemplate("{{ states('sun.sun') }")
+		if err == nil {
+			t.Fatal("RenderTemplate() expected error")
+		}
+		apiErr, ok := err.(*APIError)
+		if !ok {
+			t.Fatalf("RenderTemplate() error type = %T, want *APIError", err)
+		}
+		if apiErr.Message != "Error rendering template: TemplateSyntaxError: unexpected '}'" {
+			t.Errorf("Message = %q", apiErr.Message)
+		}
+	})
+}
+
 func TestGetSceneConfig(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mock := testutil.NewRESTMock(t, testToken)
@@ -772,3 +1162,162 @@ func isAPIError(err error, target **APIError) bool {
 	_ = apiErr
 	return false
 }
+
+func TestRetry(t *testing.T) {
+	t.Run("GET succeeds after transient errors", func(t *testing.T) {
+		mock := testutil.NewRESTMock(t, testToken)
+		calls := 0
+		mock.Handle("GET", "/api/states", func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls <= 2 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			json.NewEncoder(w).Encode([]State{{EntityID: "light.kitchen", State: "on"}})
+		})
+
+		client := NewClientWithOptions(mock.URL(), testToken, 5*time.Second, WithRetry(3, time.Millisecond))
+		states, err := client.GetStates()
+		if err != nil {
+			t.Fatalf("GetStates() error = %v", err)
+		}
+		if len(states) != 1 || states[0].EntityID != "light.kitchen" {
+			t.Errorf("GetStates() = %v, want [light.kitchen]", states)
+		}
+		if calls != 3 {
+			t.Errorf("calls = %d, want 3", calls)
+		}
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		mock := testutil.NewRESTMock(t, testToken)
+		calls := 0
+		mock.Handle("GET", "/api/states", func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+
+		client := NewClientWithOptions(mock.URL(), testToken, 5*time.Second, WithRetry(2, time.Millisecond))
+		_, err := client.GetStates()
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("GetStates() error = %v, want HTTP 503", err)
+		}
+		if calls != 3 {
+			t.Errorf("calls = %d, want 3", calls)
+		}
+	})
+
+	t.Run("GET is retried after a timeout", func(t *testing.T) {
+		mock := testutil.NewRESTMock(t, testToken)
+		// The timed-out handler is still running when the retry arrives
+		var calls atomic.Int32
+		mock.Handle("GET", "/api/", func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				time.Sleep(200 * time.Millisecond)
+			}
+			w.Write([]byte(`{"message": "API running."}`))
+		})
+
+		client := NewClientWithOptions(mock.URL(), testToken, 50*time.Millisecond, WithRetry(1, time.Millisecond))
+		if err := client.CheckConnection(); err != nil {
+			t.Fatalf("CheckConnection() error = %v", err)
+		}
+		if got := calls.Load(); got != 2 {
+			t.Errorf("calls = %d, want 2", got)
+		}
+	})
+
+	t.Run("POST is not retried", func(t *testing.T) {
+		mock := testutil.NewRESTMock(t, testToken)
+		calls := 0
+		mock.Handle("POST", "/api/services/light/turn_on", func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusBadGateway)
+		})
+
+		client := NewClientWithOptions(mock.URL(), testToken, 5*time.Second, WithRetry(3, time.Millisecond))
+		if _, err := client.CallService("light", "turn_on", nil); err == nil {
+			t.Error("CallService() expected error")
+		}
+		if calls != 1 {
+			t.Errorf("calls = %d, want 1", calls)
+		}
+	})
+
+	t.Run("client errors are not retried", func(t *testing.T) {
+		mock := testutil.NewRESTMock(t, testToken)
+		calls := 0
+		mock.Handle("GET", "/api/states/light.missing", func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		client := NewClientWithOptions(mock.URL(), testToken, 5*time.Second, WithRetry(3, time.Millisecond))
+		if _, err := client.GetState("light.missing"); !IsNotFound(err) {
+			t.Errorf("GetState() error = %v, want not found", err)
+		}
+		if calls != 1 {
+			t.Errorf("calls = %d, want 1", calls)
+		}
+	})
+}
+
+func TestWithHeaders(t *testing.T) {
+	mock := testutil.NewRESTMock(t, testToken)
+	var got http.Header
+	mock.Handle("GET", "/api/states", func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		json.NewEncoder(w).Encode([]State{})
+	})
+
+	headers := http.Header{}
+	headers.Set("X-Forwarded-For", "10.0.0.5")
+	headers.Add("X-Trace", "a")
+	headers.Add("X-Trace", "b")
+	headers.Set("Authorization", "Bearer other")
+
+	client := NewClientWithOptions(mock.URL(), testToken, 5*time.Second, WithHeaders(headers))
+	if _, err := client.GetStates(); err != nil {
+		t.Fatalf("GetStates() error = %v", err)
+	}
+
+	if v := got.Get("X-Forwarded-For"); v != "10.0.0.5" {
+		t.Errorf("X-Forwarded-For = %q, want %q", v, "10.0.0.5")
+	}
+	if v := got.Values("X-Trace"); len(v) != 2 {
+		t.Errorf("X-Trace = %v, want [a b]", v)
+	}
+	if v := got.Get("Authorization"); v != "Bearer "+testToken {
+		t.Errorf("Authorization = %q, want the client token", v)
+	}
+}
+
+func TestWithTLSConfig(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"message": "API running."}`))
+	}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	t.Run("allowed version", func(t *testing.T) {
+		client := NewClientWithOptions(server.URL, testToken, 5*time.Second,
+			WithTLSConfig(&tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}))
+		if err := client.CheckConnection(); err != nil {
+			t.Errorf("CheckConnection() error = %v", err)
+		}
+	})
+
+	t.Run("server below minimum version", func(t *testing.T) {
+		client := NewClientWithOptions(server.URL, testToken, 5*time.Second,
+			WithTLSConfig(&tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS13}))
+		if err := client.CheckConnection(); err == nil {
+			t.Error("CheckConnection() expected a TLS handshake error")
+		}
+	})
+}
@@ -2,11 +2,9 @@ package cli
 
 import (
 	"fmt"
-	"os"
 	"sort"
+	"strconv"
 	"strings"
-	"text/tabwriter"
-	"time"
 
 	"github.com/dorinclisu/hass-cli/internal/websocket"
 	"github.com/spf13/cobra"
@@ -57,13 +55,14 @@ type AreaWithCounts struct {
 
 // AreaDetail includes full area info with devices and entities.
 type AreaDetail struct {
-	AreaID   string           `json:"area_id"`
-	Name     string           `json:"name"`
-	FloorID  *string          `json:"floor_id"`
-	Icon     *string          `json:"icon"`
-	Aliases  []string         `json:"aliases"`
-	Devices  []DeviceSummary  `json:"devices"`
-	Entities []EntitySummary  `json:"entities"`
+	AreaID    string          `json:"area_id"`
+	Name      string          `json:"name"`
+	FloorID   *string         `json:"floor_id"`
+	FloorName *string         `json:"floor_name"`
+	Icon      *string         `json:"icon"`
+	Aliases   []string        `json:"aliases"`
+	Devices   []DeviceSummary `json:"devices"`
+	Entities  []EntitySummary `json:"entities"`
 }
 
 // DeviceSummary is a brief device representation.
@@ -87,112 +86,97 @@ func runAreas(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	printInfo("Connecting to Home Assistant...")
-	client, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
-	if err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
-	}
-	defer client.Close()
-
-	printInfo("Fetching areas...")
-	areas, err := client.GetAreas()
-	if err != nil {
-		return fmt.Errorf("failed to get areas: %w", err)
-	}
-
-	// Get devices and entities for counts
-	devices, err := client.GetDevices()
-	if err != nil {
-		printInfo("Warning: could not fetch devices: %v", err)
-		devices = []websocket.Device{}
-	}
+	return withWSClient(cfg, func(client *websocket.Client) error {
+		printInfo("Fetching areas...")
+		registries := client.GetRegistries()
+		if registries.AreasErr != nil {
+			return fmt.Errorf("failed to get areas: %w", registries.AreasErr)
+		}
+		areas := registries.Areas
 
-	entities, err := client.GetEntities()
-	if err != nil {
-		printInfo("Warning: could not fetch entities: %v", err)
-		entities = []websocket.Entity{}
-	}
+		// Devices and entities are only used for counts
+		devices := registries.Devices
+		if registries.DevicesErr != nil {
+			printInfo("Warning: could not fetch devices: %v", registries.DevicesErr)
+			devices = []websocket.Device{}
+		}
+		entities := registries.Entities
+		if registries.EntitiesErr != nil {
+			printInfo("Warning: could not fetch entities: %v", registries.EntitiesErr)
+			entities = []websocket.Entity{}
+		}
 
-	// Build device area map
-	deviceAreaMap := make(map[string]string)
-	for _, device := range devices {
-		if device.AreaID != nil {
-			deviceAreaMap[device.ID] = *device.AreaID
+		// Build device area map
+		deviceAreaMap := make(map[string]string)
+		for _, device := range devices {
+			if device.AreaID != nil {
+				deviceAreaMap[device.ID] = *device.AreaID
+			}
 		}
-	}
 
-	// Count devices and entities per area
-	deviceCounts := make(map[string]int)
-	entityCounts := make(map[string]int)
+		// Count devices and entities per area
+		deviceCounts := make(map[string]int)
+		entityCounts := make(map[string]int)
 
-	for _, device := range devices {
-		if device.AreaID != nil {
-			deviceCounts[*device.AreaID]++
+		for _, device := range devices {
+			if device.AreaID != nil {
+				deviceCounts[*device.AreaID]++
+			}
 		}
-	}
 
-	for _, entity := range entities {
-		areaID := entity.AreaID
-		// Inherit area from device if not set
-		if areaID == nil && entity.DeviceID != nil {
-			if deviceArea, ok := deviceAreaMap[*entity.DeviceID]; ok {
-				areaID = &deviceArea
+		for _, entity := range entities {
+			areaID := entity.AreaID
+			// Inherit area from device if not set
+			if areaID == nil && entity.DeviceID != nil {
+				if deviceArea, ok := deviceAreaMap[*entity.DeviceID]; ok {
+					areaID = &deviceArea
+				}
+			}
+			if areaID != nil {
+				entityCounts[*areaID]++
 			}
 		}
-		if areaID != nil {
-			entityCounts[*areaID]++
+
+		// Build result
+		var result []AreaWithCounts
+		for _, area := range areas {
+			result = append(result, AreaWithCounts{
+				AreaID:      area.AreaID,
+				Name:        area.Name,
+				FloorID:     area.FloorID,
+				Icon:        area.Icon,
+				Aliases:     area.Aliases,
+				DeviceCount: deviceCounts[area.AreaID],
+				EntityCount: entityCounts[area.AreaID],
+			})
 		}
-	}
 
-	// Build result
-	var result []AreaWithCounts
-	for _, area := range areas {
-		result = append(result, AreaWithCounts{
-			AreaID:      area.AreaID,
-			Name:        area.Name,
-			FloorID:     area.FloorID,
-			Icon:        area.Icon,
-			Aliases:     area.Aliases,
-			DeviceCount: deviceCounts[area.AreaID],
-			EntityCount: entityCounts[area.AreaID],
+		// Sort by name
+		sort.Slice(result, func(i, j int) bool {
+			return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
 		})
-	}
 
-	// Sort by name
-	sort.Slice(result, func(i, j int) bool {
-		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+		return outputData(cmd.OutOrStdout(), result, areasTable(result))
 	})
-
-	if jsonOutput {
-		return outputJSON(result)
-	}
-
-	return outputAreasTable(result)
 }
 
-func outputAreasTable(areas []AreaWithCounts) error {
-	if len(areas) == 0 {
-		fmt.Println("No areas found")
-		return nil
+func areasTable(areas []AreaWithCounts) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "AREA ID"}, {Header: "NAME"}, {Header: "DEVICES"}, {Header: "ENTITIES"}},
+		Noun:    "areas",
+		Empty:   "No areas found",
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "AREA ID\tNAME\tDEVICES\tENTITIES")
-	fmt.Fprintln(w, "-------\t----\t-------\t--------")
-
 	for _, a := range areas {
-		fmt.Fprintf(w, "%s\t%s\t%d\t%d\n",
+		t.addRow(
 			a.AreaID,
 			a.Name,
-			a.DeviceCount,
-			a.EntityCount,
+			strconv.Itoa(a.DeviceCount),
+			strconv.Itoa(a.EntityCount),
 		)
 	}
 
-	w.Flush()
-	fmt.Printf("\nTotal: %d areas\n", len(areas))
-
-	return nil
+	return t
 }
 
 func runAreasInspect(cmd *cobra.Command, args []string) error {
@@ -203,99 +187,146 @@ func runAreasInspect(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	printInfo("Connecting to Home Assistant...")
-	client, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
-	if err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
-	}
-	defer client.Close()
-
-	// Get areas
-	areas, err := client.GetAreas()
-	if err != nil {
-		return fmt.Errorf("failed to get areas: %w", err)
-	}
+	return withWSClient(cfg, func(client *websocket.Client) error {
+		registries := client.GetRegistries()
+		if registries.AreasErr != nil {
+			return fmt.Errorf("failed to get areas: %w", registries.AreasErr)
+		}
+		areas := registries.Areas
 
-	// Find the area
-	var targetArea *websocket.Area
-	for i := range areas {
-		if areas[i].AreaID == areaID || strings.EqualFold(areas[i].Name, areaID) {
-			targetArea = &areas[i]
-			break
+		targetArea := findArea(areas, areaID)
+		if targetArea == nil {
+			return fmt.Errorf("area not found: %s", areaID)
 		}
-	}
 
-	if targetArea == nil {
-		return fmt.Errorf("area not found: %s", areaID)
-	}
+		if registries.DevicesErr != nil {
+			return fmt.Errorf("failed to get devices: %w", registries.DevicesErr)
+		}
+		devices := registries.Devices
 
-	// Get devices and entities
-	devices, err := client.GetDevices()
-	if err != nil {
-		return fmt.Errorf("failed to get devices: %w", err)
-	}
+		if registries.EntitiesErr != nil {
+			return fmt.Errorf("failed to get entities: %w", registries.EntitiesErr)
+		}
+		entities := registries.Entities
 
-	entities, err := client.GetEntities()
-	if err != nil {
-		return fmt.Errorf("failed to get entities: %w", err)
-	}
+		// Build device area map
+		deviceAreaMap := make(map[string]string)
+		for _, device := range devices {
+			if device.AreaID != nil {
+				deviceAreaMap[device.ID] = *device.AreaID
+			}
+		}
 
-	// Build device area map
-	deviceAreaMap := make(map[string]string)
-	for _, device := range devices {
-		if device.AreaID != nil {
-			deviceAreaMap[device.ID] = *device.AreaID
+		// Filter devices in this area
+		var areaDevices []DeviceSummary
+		for _, device := range devices {
+			if device.AreaID != nil && *device.AreaID == targetArea.AreaID {
+				areaDevices = append(areaDevices, DeviceSummary{
+					ID:           device.ID,
+					Name:         device.DisplayName(),
+					Manufacturer: device.Manufacturer,
+					Model:        device.Model,
+				})
+			}
 		}
-	}
 
-	// Filter devices in this area
-	var areaDevices []DeviceSummary
-	for _, device := range devices {
-		if device.AreaID != nil && *device.AreaID == targetArea.AreaID {
-			areaDevices = append(areaDevices, DeviceSummary{
-				ID:           device.ID,
-				Name:         device.DisplayName(),
-				Manufacturer: device.Manufacturer,
-				Model:        device.Model,
-			})
+		// Filter entities in this area (direct or inherited from device)
+		var areaEntities []EntitySummary
+		for _, entity := range entities {
+			entityAreaID := entity.AreaID
+			if entityAreaID == nil && entity.DeviceID != nil {
+				if deviceArea, ok := deviceAreaMap[*entity.DeviceID]; ok {
+					entityAreaID = &deviceArea
+				}
+			}
+			if entityAreaID != nil && *entityAreaID == targetArea.AreaID {
+				areaEntities = append(areaEntities, EntitySummary{
+					EntityID: entity.EntityID,
+					Name:     entity.Name,
+					Platform: entity.Platform,
+				})
+			}
 		}
-	}
 
-	// Filter entities in this area (direct or inherited from device)
-	var areaEntities []EntitySummary
-	for _, entity := range entities {
-		entityAreaID := entity.AreaID
-		if entityAreaID == nil && entity.DeviceID != nil {
-			if deviceArea, ok := deviceAreaMap[*entity.DeviceID]; ok {
-				entityAreaID = &deviceArea
+		// Sort
+		sort.Slice(areaDevices, func(i, j int) bool {
+			return areaDevices[i].Name < areaDevices[j].Name
+		})
+		sort.Slice(areaEntities, func(i, j int) bool {
+			return areaEntities[i].EntityID < areaEntities[j].EntityID
+		})
+
+		// Floors are optional, so an old server without them still works
+		var floorName *string
+		if targetArea.FloorID != nil {
+			printInfo("Fetching floors...")
+			floors, err := client.GetFloors()
+			if err != nil {
+				printInfo("Warning: could not fetch floors: %v", err)
+			} else {
+				floorName = findFloorName(floors, *targetArea.FloorID)
 			}
 		}
-		if entityAreaID != nil && *entityAreaID == targetArea.AreaID {
-			areaEntities = append(areaEntities, EntitySummary{
-				EntityID: entity.EntityID,
-				Name:     entity.Name,
-				Platform: entity.Platform,
-			})
+
+		detail := AreaDetail{
+			AreaID:    targetArea.AreaID,
+			Name:      targetArea.Name,
+			FloorID:   targetArea.FloorID,
+			FloorName: floorName,
+			Icon:      targetArea.Icon,
+			Aliases:   targetArea.Aliases,
+			Devices:   areaDevices,
+			Entities:  areaEntities,
 		}
-	}
 
-	// Sort
-	sort.Slice(areaDevices, func(i, j int) bool {
-		return areaDevices[i].Name < areaDevices[j].Name
-	})
-	sort.Slice(areaEntities, func(i, j int) bool {
-		return areaEntities[i].EntityID < areaEntities[j].EntityID
+		return outputJSON(cmd.OutOrStdout(), detail)
 	})
+}
+
+// findFloorName returns the name of the floor with floorID, or nil when no
+// such floor exists.
+func findFloorName(floors []websocket.Floor, floorID string) *string {
+	for i := range floors {
+		if floors[i].FloorID == floorID {
+			return &floors[i].Name
+		}
+	}
+	return nil
+}
+
+// findArea returns the area whose ID or name (case-insensitive) is idOrName.
+func findArea(areas []websocket.Area, idOrName string) *websocket.Area {
+	for i := range areas {
+		if areas[i].AreaID == idOrName || strings.EqualFold(areas[i].Name, idOrName) {
+			return &areas[i]
+		}
+	}
+	return nil
+}
+
+// resolveArea looks up an area by ID or name. With create, a missing area
+// is created using idOrName as its name. When create is requested it
+// reports whether the area was created or already existed.
+func resolveArea(client *websocket.Client, idOrName string, create bool) (*websocket.Area, error) {
+	areas, err := client.GetAreas()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get areas: %w", err)
+	}
 
-	detail := AreaDetail{
-		AreaID:   targetArea.AreaID,
-		Name:     targetArea.Name,
-		FloorID:  targetArea.FloorID,
-		Icon:     targetArea.Icon,
-		Aliases:  targetArea.Aliases,
-		Devices:  areaDevices,
-		Entities: areaEntities,
+	if area := findArea(areas, idOrName); area != nil {
+		if create {
+			printSuccess("Area already exists: %s (%s)", area.Name, area.AreaID)
+		}
+		return area, nil
+	}
+	if !create {
+		return nil, fmt.Errorf("area not found: %s (use --create to create it)", idOrName)
 	}
 
-	return outputJSON(detail)
+	area, err := client.CreateArea(idOrName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create area: %w", err)
+	}
+	printSuccess("Created area: %s (%s)", area.Name, area.AreaID)
+	return area, nil
 }
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+)
+
+func TestFindArea(t *testing.T) {
+	areas := []websocket.Area{
+		{AreaID: "living_room", Name: "Living Room"},
+		{AreaID: "kitchen", Name: "Kitchen"},
+	}
+
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"kitchen", "kitchen"},
+		{"Living Room", "living_room"},
+		{"living room", "living_room"},
+		{"Garden Shed", ""},
+	}
+
+	for _, tt := range tests {
+		got := findArea(areas, tt.arg)
+		switch {
+		case tt.want == "" && got != nil:
+			t.Errorf("findArea(%q) = %s, want nil", tt.arg, got.AreaID)
+		case tt.want != "" && (got == nil || got.AreaID != tt.want):
+			t.Errorf("findArea(%q) = %v, want %s", tt.arg, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var (
+	assistLanguage       string
+	assistConversationID string
+)
+
+var assistCmd = &cobra.Command{
+	Use:   "assist <text>...",
+	Short: "Send a natural-language command to Assist",
+	Long: `Send text to the Home Assistant conversation agent (Assist) and print its
+answer, as if the command had been spoken to a voice assistant.
+
+Assist works out what to do with its own intent engine, so commands can name
+areas and devices the way they are called in Home Assistant. If it does not
+understand the command or fails to carry it out, the answer is printed and
+the exit status is 1.
+
+Pass the conversation ID shown with --verbose to --conversation-id to answer
+a follow-up question.
+
+Examples:
+  hass-cli assist "turn off the kitchen lights"
+  hass-cli assist what is the temperature in the living room
+  hass-cli assist "allume la cuisine" --language fr
+  hass-cli assist "turn on the fan" --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runAssist,
+}
+
+func init() {
+	rootCmd.AddCommand(assistCmd)
+
+	assistCmd.Flags().StringVar(&assistLanguage, "language", "", "Language of the text (default: the Home Assistant language)")
+	assistCmd.Flags().StringVar(&assistConversationID, "conversation-id", "", "Continue an earlier conversation")
+}
+
+func runAssist(cmd *cobra.Command, args []string) error {
+	text := strings.TrimSpace(strings.Join(args, " "))
+	if text == "" {
+		return fmt.Errorf("text must not be empty")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	printInfo("Sending to Assist...")
+	result, err := client.ProcessConversation(text, assistLanguage, assistConversationID)
+	if err != nil {
+		if api.IsNotFound(err) {
+			return fmt.Errorf("the conversation API is not available (is the conversation integration enabled?)")
+		}
+		return fmt.Errorf("failed to process text: %w", err)
+	}
+
+	if jsonOutput {
+		return outputJSON(cmd.OutOrStdout(), result)
+	}
+
+	speech := result.Response.PlainSpeech()
+	if speech == "" {
+		speech = "(no response)"
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), speech)
+
+	if result.ConversationID != "" {
+		printInfo("Conversation ID: %s", result.ConversationID)
+	}
+
+	if result.Response.ResponseType == "error" {
+		return &ExitError{Code: 1}
+	}
+
+	return nil
+}
@@ -3,15 +3,12 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"sort"
 	"strconv"
 	"strings"
-	"text/tabwriter"
 	"time"
 
 	"github.com/dorinclisu/hass-cli/internal/api"
-	"github.com/dorinclisu/hass-cli/internal/websocket"
 	"github.com/spf13/cobra"
 )
 
@@ -26,11 +23,16 @@ Use 'hass-cli automations trigger <automation_id>' to manually run an automation
 Examples:
   hass-cli automations                           # List all automations
   hass-cli automations --json                    # Output as JSON
+  hass-cli automations --fields name,state,mode  # Pick table columns
   hass-cli automations inspect <automation_id>   # Show automation configuration
+  hass-cli automations audit                     # Find references to missing entities
   hass-cli automations create <name>             # Create a new automation
+  hass-cli automations add-action <id> <json>    # Append an action
   hass-cli automations trigger <automation_id>   # Manually trigger an automation
   hass-cli automations debug <automation_id>     # Show execution traces
-  hass-cli automations delete <automation_id>    # Delete an automation`,
+  hass-cli automations delete <automation_id>    # Delete an automation
+  hass-cli automations export -o backup.yaml     # Export all automations
+  hass-cli automations import backup.yaml        # Recreate automations from a file`,
 	RunE: runAutomations,
 }
 
@@ -75,7 +77,9 @@ Use flags to update specific properties, or provide complete triggers/conditions
 Examples:
   hass-cli automations edit 1761025981191 --alias "Updated Name"
   hass-cli automations edit 1761025981191 --description "New description"
-  hass-cli automations edit 1761025981191 --actions '[{"action":"light.turn_off"}]'`,
+  hass-cli automations edit 1761025981191 --actions '[{"action":"light.turn_off"}]'
+  hass-cli automations edit 1761025981191 --triggers '[...]' --diff     # Review, then confirm
+  hass-cli automations edit 1761025981191 --triggers '[...]' --dry-run  # Only show the changes`,
 	Args: cobra.ExactArgs(1),
 	RunE: runAutomationsEdit,
 }
@@ -114,10 +118,15 @@ var automationsDebugCmd = &cobra.Command{
 
 This shows the history of automation executions with timing and step information.
 Use --run-id to see details of a specific execution.
+Runs are listed newest first, up to --limit (default 20).
 
 Examples:
-  hass-cli automations debug 1761025981191              # List all traces
-  hass-cli automations debug 1761025981191 --run-id <id>  # Show specific trace`,
+  hass-cli automations debug 1761025981191              # List recent traces
+  hass-cli automations debug 1761025981191 --limit 0    # List every stored trace
+  hass-cli automations debug 1761025981191 --run-id <id>  # Show specific trace
+  hass-cli automations debug 1761025981191 --run-id <id> --pretty  # As a step tree
+  hass-cli automations debug 1761025981191 --since 2h     # Runs in the last 2 hours
+  hass-cli automations debug 1761025981191 --since 1d --until 12h`,
 	Args: cobra.ExactArgs(1),
 	RunE: runAutomationsDebug,
 }
@@ -184,6 +193,9 @@ func init() {
 	automationsCmd.AddCommand(automationsEnableCmd)
 	automationsCmd.AddCommand(automationsDisableCmd)
 
+	// List flags
+	addFieldsFlag(automationsCmd, AutomationInfo{})
+
 	// Create flags
 	automationsCreateCmd.Flags().StringVar(&automationDescription, "description", "", "Description of the automation")
 	automationsCreateCmd.Flags().StringVar(&automationMode, "mode", "single", "Automation mode: single, restart, queued, parallel")
@@ -198,9 +210,17 @@ func init() {
 	automationsEditCmd.Flags().StringVar(&automationTriggers, "triggers", "", "New JSON array of triggers")
 	automationsEditCmd.Flags().StringVar(&automationConditions, "conditions", "", "New JSON array of conditions")
 	automationsEditCmd.Flags().StringVar(&automationActions, "actions", "", "New JSON array of actions")
+	addEditReviewFlags(automationsEditCmd)
 
 	// Debug flags
 	automationsDebugCmd.Flags().StringVar(&automationRunID, "run-id", "", "Specific run ID to inspect")
+	addTraceTimeFlags(automationsDebugCmd)
+	addTraceLimitFlag(automationsDebugCmd)
+	addTracePrettyFlag(automationsDebugCmd)
+
+	addReloadFlag(automationsCreateCmd)
+	addReloadFlag(automationsEditCmd)
+	addReloadFlag(automationsDeleteCmd)
 }
 
 // AutomationInfo combines automation entity info with config details.
@@ -220,7 +240,7 @@ func runAutomations(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	printInfo("Fetching automations...")
 	states, err := client.GetStates()
@@ -228,7 +248,13 @@ func runAutomations(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get states: %w", err)
 	}
 
-	// Filter for automation entities
+	automations := automationInfos(states)
+	return outputData(cmd.OutOrStdout(), automations, automationsTable(automations))
+}
+
+// automationInfos collects the automation entities among states, sorted by
+// name.
+func automationInfos(states []api.State) []AutomationInfo {
 	var automations []AutomationInfo
 	for _, state := range states {
 		if !strings.HasPrefix(state.EntityID, "automation.") {
@@ -278,29 +304,35 @@ func runAutomations(cmd *cobra.Command, args []string) error {
 		return strings.ToLower(automations[i].Name) < strings.ToLower(automations[j].Name)
 	})
 
-	if jsonOutput {
-		return outputJSON(automations)
+	return automations
+}
+
+// listAutomationConfigIDs returns the config IDs of every automation created
+// in the UI, in name order. Automations defined in YAML have no config ID and
+// are left out.
+func listAutomationConfigIDs(client *api.Client) ([]string, error) {
+	states, err := client.GetStates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get states: %w", err)
 	}
 
-	return outputAutomationsTable(automations)
+	ids := []string{}
+	for _, a := range automationInfos(states) {
+		if a.ConfigID != "" {
+			ids = append(ids, a.ConfigID)
+		}
+	}
+	return ids, nil
 }
 
-func outputAutomationsTable(automations []AutomationInfo) error {
-	if len(automations) == 0 {
-		fmt.Println("No automations found")
-		return nil
+func automationsTable(automations []AutomationInfo) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "CONFIG ID"}, {Header: "NAME", Width: 35}, {Header: "STATE"}, {Header: "MODE"}, {Header: "LAST TRIGGERED"}},
+		Noun:    "automations",
+		Empty:   "No automations found",
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "CONFIG ID\tNAME\tSTATE\tMODE\tLAST TRIGGERED")
-	fmt.Fprintln(w, "---------\t----\t-----\t----\t--------------")
-
 	for _, a := range automations {
-		name := a.Name
-		if len(name) > 35 {
-			name = name[:32] + "..."
-		}
-
 		configID := a.ConfigID
 		if configID == "" {
 			configID = "-"
@@ -310,25 +342,22 @@ func outputAutomationsTable(automations []AutomationInfo) error {
 		if lastTriggered != "" && lastTriggered != "None" {
 			// Parse and format the timestamp
 			if t, err := time.Parse(time.RFC3339, lastTriggered); err == nil {
-				lastTriggered = t.Local().Format("2006-01-02 15:04:05")
+				lastTriggered = displayTime(t).Format("2006-01-02 15:04:05")
 			}
 		} else {
 			lastTriggered = "-"
 		}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+		t.addRow(
 			configID,
-			name,
+			a.Name,
 			a.State,
 			a.Mode,
 			lastTriggered,
 		)
 	}
 
-	w.Flush()
-	fmt.Printf("\nTotal: %d automations\n", len(automations))
-
-	return nil
+	return t
 }
 
 func runAutomationsInspect(cmd *cobra.Command, args []string) error {
@@ -339,25 +368,11 @@ func runAutomationsInspect(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
-
-	// Try to extract config ID from entity ID if needed
-	configID := normalizeAutomationID(automationID)
+	client := newRESTClient(cfg)
 
-	// If it looks like an entity ID, we need to look up the config ID
-	if strings.HasPrefix(automationID, "automation.") {
-		printInfo("Looking up automation config ID...")
-		state, err := client.GetState(automationID)
-		if err != nil {
-			return fmt.Errorf("failed to get automation state: %w", err)
-		}
-		if id, ok := state.Attributes["id"].(string); ok {
-			configID = id
-		} else if id, ok := state.Attributes["id"].(float64); ok {
-			configID = strconv.FormatFloat(id, 'f', 0, 64)
-		} else {
-			return fmt.Errorf("could not find config ID for %s", automationID)
-		}
+	configID, err := resolveAutomationConfigID(client, automationID)
+	if err != nil {
+		return err
 	}
 
 	printInfo("Fetching automation configuration...")
@@ -366,7 +381,29 @@ func runAutomationsInspect(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get automation: %w", err)
 	}
 
-	return outputJSON(config)
+	return outputJSON(cmd.OutOrStdout(), config)
+}
+
+// resolveAutomationConfigID returns the config ID for an automation given by
+// config ID or entity ID. Entity IDs are looked up via the automation's id
+// attribute.
+func resolveAutomationConfigID(client *api.Client, automationID string) (string, error) {
+	if !strings.HasPrefix(automationID, "automation.") {
+		return normalizeAutomationID(automationID), nil
+	}
+
+	printInfo("Looking up automation config ID...")
+	state, err := client.GetState(automationID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get automation state: %w", err)
+	}
+	if id, ok := state.Attributes["id"].(string); ok {
+		return id, nil
+	}
+	if id, ok := state.Attributes["id"].(float64); ok {
+		return strconv.FormatFloat(id, 'f', 0, 64), nil
+	}
+	return "", fmt.Errorf("could not find config ID for %s", automationID)
 }
 
 func runAutomationsCreate(cmd *cobra.Command, args []string) error {
@@ -377,7 +414,7 @@ func runAutomationsCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	// Parse triggers if provided
 	var triggers []map[string]interface{}
@@ -431,12 +468,10 @@ func runAutomationsCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create automation: %w", err)
 	}
 
-	fmt.Printf("Automation created: %s\n", name)
-	fmt.Printf("Config ID: %s\n", automationID)
-	fmt.Printf("Entity ID will be: automation.%s\n", slugify(name))
-	fmt.Println("\nNote: You may need to reload automations or restart Home Assistant for the new automation to appear.")
-
-	return nil
+	printSuccess("Automation created: %s", name)
+	printSuccess("Config ID: %s", automationID)
+	printSuccess("Entity ID will be: automation.%s", slugify(name))
+	return reloadOrNote(cfg, "automation", "You may need to reload automations or restart Home Assistant for the new automation to appear.")
 }
 
 func runAutomationsEdit(cmd *cobra.Command, args []string) error {
@@ -447,7 +482,7 @@ func runAutomationsEdit(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	// Get existing config
 	printInfo("Fetching current automation configuration...")
@@ -456,6 +491,8 @@ func runAutomationsEdit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get automation: %w", err)
 	}
 
+	before := *config
+
 	// Apply updates
 	if automationAlias != "" {
 		config.Alias = automationAlias
@@ -488,14 +525,19 @@ func runAutomationsEdit(cmd *cobra.Command, args []string) error {
 		config.Actions = actions
 	}
 
+	apply, err := reviewEdit(cmd.OutOrStdout(), before, config)
+	if err != nil || !apply {
+		return err
+	}
+
 	printInfo("Updating automation...")
 	if err := client.UpdateAutomation(automationID, config); err != nil {
 		return fmt.Errorf("failed to update automation: %w", err)
 	}
 
-	fmt.Printf("Automation updated: %s\n", config.Alias)
+	printSuccess("Automation updated: %s", config.Alias)
 
-	return nil
+	return reloadOrNote(cfg, "automation", "")
 }
 
 func runAutomationsRename(cmd *cobra.Command, args []string) error {
@@ -507,7 +549,7 @@ func runAutomationsRename(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	// Get existing config
 	printInfo("Fetching current automation configuration...")
@@ -524,7 +566,7 @@ func runAutomationsRename(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to rename automation: %w", err)
 	}
 
-	fmt.Printf("Automation renamed: '%s' -> '%s'\n", oldName, newName)
+	printSuccess("Automation renamed: '%s' -> '%s'", oldName, newName)
 
 	return nil
 }
@@ -537,7 +579,7 @@ func runAutomationsTrigger(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	// Build entity ID if needed
 	entityID := automationID
@@ -590,7 +632,7 @@ func runAutomationsDebug(cmd *cobra.Command, args []string) error {
 	}
 
 	printInfo("Connecting to Home Assistant...")
-	wsClient, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	wsClient, err := newWSClient(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to connect: %w", err)
 	}
@@ -604,7 +646,7 @@ func runAutomationsDebug(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to get trace: %w", err)
 		}
 
-		return outputJSON(trace)
+		return outputTrace(cmd.OutOrStdout(), trace)
 	}
 
 	// List all traces
@@ -614,57 +656,13 @@ func runAutomationsDebug(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to list traces: %w", err)
 	}
 
-	if jsonOutput {
-		return outputJSON(traces)
-	}
-
-	return outputAutomationTracesTable(traces)
-}
-
-func outputAutomationTracesTable(traces []websocket.TraceSummary) error {
-	if len(traces) == 0 {
-		fmt.Println("No traces found")
-		return nil
-	}
-
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "RUN ID\tSTATE\tRESULT\tSTARTED\tDURATION")
-	fmt.Fprintln(w, "------\t-----\t------\t-------\t--------")
-
-	for _, t := range traces {
-		started := t.Timestamp.Start
-		if s, err := time.Parse(time.RFC3339, t.Timestamp.Start); err == nil {
-			started = s.Local().Format("2006-01-02 15:04:05")
-		}
-
-		duration := ""
-		if t.Timestamp.Start != "" && t.Timestamp.Finish != "" {
-			start, err1 := time.Parse(time.RFC3339, t.Timestamp.Start)
-			finish, err2 := time.Parse(time.RFC3339, t.Timestamp.Finish)
-			if err1 == nil && err2 == nil {
-				d := finish.Sub(start)
-				if d < time.Second {
-					duration = fmt.Sprintf("%dms", d.Milliseconds())
-				} else {
-					duration = d.Round(time.Millisecond).String()
-				}
-			}
-		}
-
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-			t.RunID,
-			t.State,
-			t.ScriptExecution,
-			started,
-			duration,
-		)
+	traces, err = applyTraceTimeFlags(traces)
+	if err != nil {
+		return err
 	}
+	traces = applyTraceLimit(traces)
 
-	w.Flush()
-	fmt.Printf("\nTotal: %d traces\n", len(traces))
-	fmt.Println("\nUse --run-id <id> to see detailed trace information")
-
-	return nil
+	return outputData(cmd.OutOrStdout(), traces, tracesTable(traces))
 }
 
 func runAutomationsDelete(cmd *cobra.Command, args []string) error {
@@ -675,7 +673,7 @@ func runAutomationsDelete(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	printInfo("Deleting automation '%s'...", automationID)
 	if err := client.DeleteAutomation(automationID); err != nil {
@@ -683,9 +681,7 @@ func runAutomationsDelete(cmd *cobra.Command, args []string) error {
 	}
 
 	printSuccess("Automation deleted: %s", automationID)
-	fmt.Println("\nNote: You may need to reload automations or restart Home Assistant for the change to take effect.")
-
-	return nil
+	return reloadOrNote(cfg, "automation", "You may need to reload automations or restart Home Assistant for the change to take effect.")
 }
 
 func runAutomationsEnable(cmd *cobra.Command, args []string) error {
@@ -696,7 +692,7 @@ func runAutomationsEnable(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	// Build entity ID if needed
 	entityID := buildAutomationEntityID(automationID, client)
@@ -722,7 +718,7 @@ func runAutomationsDisable(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	// Build entity ID if needed
 	entityID := buildAutomationEntityID(automationID, client)
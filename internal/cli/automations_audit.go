@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var automationsAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Find automations that reference missing entities",
+	Long: `Check every automation for entity IDs that no longer exist.
+
+The triggers, conditions and actions of each automation are scanned for
+entity IDs, which are checked against the current states and the entity
+registry. Automations referencing an entity that was renamed or removed are
+reported with the missing entities, and the command exits with status 1.
+
+Entity IDs inside templates are not checked. Automations defined in YAML
+without an id cannot be fetched and are skipped.
+
+Examples:
+  hass-cli automations audit
+  hass-cli automations audit --json`,
+	Args: cobra.NoArgs,
+	RunE: runAutomationsAudit,
+}
+
+func init() {
+	automationsCmd.AddCommand(automationsAuditCmd)
+}
+
+// AutomationAudit lists the missing entities an automation references.
+type AutomationAudit struct {
+	EntityID string   `json:"entity_id"`
+	Name     string   `json:"name"`
+	ConfigID string   `json:"config_id"`
+	Missing  []string `json:"missing"`
+}
+
+// referencedEntityPattern matches a string that is exactly an entity ID.
+// The domain must start with a letter so numbers such as "0.5" are not
+// mistaken for one.
+var referencedEntityPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*\.[a-z0-9_]+$`)
+
+func runAutomationsAudit(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	printInfo("Fetching states...")
+	states, err := client.GetStates()
+	if err != nil {
+		return fmt.Errorf("failed to get states: %w", err)
+	}
+
+	printInfo("Connecting to Home Assistant...")
+	wsClient, err := newWSClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer wsClient.Close()
+
+	printInfo("Fetching entity registry...")
+	entities, err := wsClient.GetEntities()
+	if err != nil {
+		return fmt.Errorf("failed to get entities: %w", err)
+	}
+
+	known := make(map[string]bool, len(states)+len(entities))
+	for _, state := range states {
+		known[state.EntityID] = true
+	}
+	for _, entity := range entities {
+		known[entity.EntityID] = true
+	}
+
+	var audits []AutomationAudit
+	skipped := 0
+	for _, state := range states {
+		if !strings.HasPrefix(state.EntityID, "automation.") {
+			continue
+		}
+
+		configID := ""
+		if id, ok := state.Attributes["id"].(string); ok {
+			configID = id
+		} else if id, ok := state.Attributes["id"].(float64); ok {
+			configID = strconv.FormatFloat(id, 'f', 0, 64)
+		}
+		if configID == "" {
+			skipped++
+			continue
+		}
+
+		printInfo("Checking %s...", state.EntityID)
+		config, err := client.GetAutomationConfig(configID)
+		if err != nil {
+			if api.IsNotFound(err) {
+				skipped++
+				continue
+			}
+			return fmt.Errorf("failed to get config of %s: %w", state.EntityID, err)
+		}
+
+		var missing []string
+		for _, id := range automationEntityRefs(config) {
+			if !known[id] {
+				missing = append(missing, id)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		name := config.Alias
+		if name == "" {
+			name = state.EntityID
+		}
+		audits = append(audits, AutomationAudit{EntityID: state.EntityID, Name: name, ConfigID: configID, Missing: missing})
+	}
+
+	sort.Slice(audits, func(i, j int) bool {
+		return strings.ToLower(audits[i].Name) < strings.ToLower(audits[j].Name)
+	})
+
+	if skipped > 0 {
+		printInfo("Skipped %d automations without a stored config", skipped)
+	}
+
+	if err := outputData(cmd.OutOrStdout(), audits, automationAuditTable(audits)); err != nil {
+		return err
+	}
+	if len(audits) > 0 {
+		return &ExitError{Code: 1}
+	}
+
+	return nil
+}
+
+func automationAuditTable(audits []AutomationAudit) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "AUTOMATION", Width: 35}, {Header: "CONFIG ID"}, {Header: "MISSING ENTITIES"}},
+		Noun:    "automations with missing entities",
+		Empty:   "All referenced entities exist",
+	}
+
+	for _, a := range audits {
+		t.addRow(
+			a.Name,
+			a.ConfigID,
+			strings.Join(a.Missing, ", "),
+		)
+	}
+
+	return t
+}
+
+// automationEntityRefs returns the entity IDs an automation's triggers,
+// conditions and actions reference, sorted and without duplicates.
+func automationEntityRefs(config *api.AutomationConfig) []string {
+	seen := make(map[string]bool)
+	for _, list := range [][]map[string]interface{}{config.Triggers, config.Conditions, config.Actions} {
+		for _, item := range list {
+			collectEntityRefs(item, "", seen)
+		}
+	}
+
+	refs := make([]string, 0, len(seen))
+	for id := range seen {
+		refs = append(refs, id)
+	}
+	sort.Strings(refs)
+
+	return refs
+}
+
+// collectEntityRefs adds every string in v that is an entity ID to seen,
+// descending into maps and lists. key is the map key v was found under;
+// service names under action and service look like entity IDs and are
+// ignored. Comma-separated lists of entity IDs are split.
+func collectEntityRefs(v interface{}, key string, seen map[string]bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			collectEntityRefs(child, k, seen)
+		}
+	case []interface{}:
+		for _, child := range v {
+			collectEntityRefs(child, key, seen)
+		}
+	case string:
+		if key == "action" || key == "service" {
+			return
+		}
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); referencedEntityPattern.MatchString(part) {
+				seen[part] = true
+			}
+		}
+	}
+}
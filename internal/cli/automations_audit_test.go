@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+)
+
+func TestAutomationEntityRefs(t *testing.T) {
+	config := &api.AutomationConfig{
+		Triggers: []map[string]interface{}{
+			{"trigger": "state", "entity_id": []interface{}{"binary_sensor.door", "binary_sensor.window"}, "to": "on"},
+			{"trigger": "numeric_state", "entity_id": "sensor.temp", "above": "0.5"},
+		},
+		Conditions: []map[string]interface{}{
+			{"condition": "state", "entity_id": "light.hall, light.kitchen", "state": "off"},
+			{"condition": "template", "value_template": "{{ is_state('sun.sun', 'below_horizon') }}"},
+		},
+		Actions: []map[string]interface{}{
+			{"action": "light.turn_on", "target": map[string]interface{}{"entity_id": "light.hall"}},
+			{"service": "notify.mobile_app", "data": map[string]interface{}{"message": "Door opened"}},
+			{"choose": []interface{}{
+				map[string]interface{}{"sequence": []interface{}{
+					map[string]interface{}{"action": "scene.turn_on", "target": map[string]interface{}{"entity_id": "scene.evening"}},
+				}},
+			}},
+		},
+	}
+
+	got := strings.Join(automationEntityRefs(config), ",")
+	want := "binary_sensor.door,binary_sensor.window,light.hall,light.kitchen,scene.evening,sensor.temp"
+	if got != want {
+		t.Errorf("automationEntityRefs() = %s, want %s", got, want)
+	}
+}
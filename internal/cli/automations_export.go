@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var automationsExportCmd = &cobra.Command{
+	Use:   "export [automation_id]",
+	Short: "Export automation configurations to a YAML or JSON file",
+	Long: `Export the configuration of one automation, or of every automation, to a
+file that 'hass-cli automations import' can recreate them from.
+
+The automation is given by config ID or entity ID. Without one, every
+automation created in the UI is exported; automations defined in YAML have no
+config ID and are skipped.
+
+The file is written as JSON when --output ends in .json or --json is given,
+and as YAML otherwise. Without --output it is written to stdout.
+
+Examples:
+  hass-cli automations export -o automations.yaml
+  hass-cli automations export automation.morning_lights -o morning.json
+  hass-cli automations export 1767672291452`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAutomationsExport,
+}
+
+var automationsImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Create automations from an exported YAML or JSON file",
+	Long: `Create the automations in a file written by 'hass-cli automations export'.
+
+Each automation keeps its config ID, so importing into the instance it was
+exported from overwrites the automations with those IDs. Use --new-ids to give
+every automation a fresh ID instead, e.g. to duplicate automations or to import
+them into another instance. Automations without an ID always get a new one.
+
+The file is YAML or JSON, either an object with an "automations" list or just
+the list itself.
+
+Examples:
+  hass-cli automations import automations.yaml
+  hass-cli automations import morning.json --new-ids --reload`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAutomationsImport,
+}
+
+var (
+	automationExportOutput string
+	automationImportNewIDs bool
+)
+
+func init() {
+	automationsCmd.AddCommand(automationsExportCmd)
+	automationsCmd.AddCommand(automationsImportCmd)
+
+	automationsExportCmd.Flags().StringVarP(&automationExportOutput, "output", "o", "", "File to write the automations to (default: stdout)")
+	automationsImportCmd.Flags().BoolVar(&automationImportNewIDs, "new-ids", false, "Give every imported automation a new timestamp-based ID")
+
+	addReloadFlag(automationsImportCmd)
+}
+
+// AutomationFile is the top-level structure of an automations export file.
+// Automations are kept as Home Assistant stores them, so keys the CLI does not
+// model, such as variables or max_exceeded, survive a round trip.
+type AutomationFile struct {
+	Automations []map[string]interface{} `json:"automations" yaml:"automations"`
+}
+
+// configString returns the string value of key in a raw config, or "".
+func configString(config map[string]interface{}, key string) string {
+	s, _ := config[key].(string)
+	return s
+}
+
+func runAutomationsExport(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	var ids []string
+	if len(args) == 1 {
+		id, err := resolveAutomationConfigID(client, args[0])
+		if err != nil {
+			return err
+		}
+		ids = []string{id}
+	} else {
+		printInfo("Fetching automations...")
+		if ids, err = listAutomationConfigIDs(client); err != nil {
+			return err
+		}
+	}
+
+	file := AutomationFile{Automations: []map[string]interface{}{}}
+	for _, id := range ids {
+		printInfo("Fetching configuration of %s...", id)
+		config, err := client.GetAutomationConfigRaw(id)
+		if err != nil {
+			return fmt.Errorf("failed to get automation %s: %w", id, err)
+		}
+		if config == nil {
+			config = map[string]interface{}{}
+		}
+		if configString(config, "id") == "" {
+			config["id"] = id
+		}
+		file.Automations = append(file.Automations, config)
+	}
+
+	asJSON := exportAsJSON(automationExportOutput)
+
+	if automationExportOutput == "" {
+		return writeExport(cmd.OutOrStdout(), &file, asJSON)
+	}
+
+	out, err := os.Create(automationExportOutput)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer out.Close()
+
+	if err := writeExport(out, &file, asJSON); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	printSuccess("Exported %d automations to %s", len(file.Automations), automationExportOutput)
+	return nil
+}
+
+// loadAutomationFile reads and validates an automations export file.
+func loadAutomationFile(path string) (*AutomationFile, error) {
+	var file AutomationFile
+	if err := readExport(path, "automations", &file.Automations); err != nil {
+		return nil, err
+	}
+
+	if len(file.Automations) == 0 {
+		return nil, fmt.Errorf("automations file declares no automations")
+	}
+
+	var problems []string
+	for i, a := range file.Automations {
+		if strings.TrimSpace(configString(a, "alias")) == "" {
+			problems = append(problems, fmt.Sprintf("  automations[%d]: alias is required", i))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid automations file:\n%s", strings.Join(problems, "\n"))
+	}
+
+	return &file, nil
+}
+
+func runAutomationsImport(cmd *cobra.Command, args []string) error {
+	file, err := loadAutomationFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	ids := make([]string, len(file.Automations))
+	for i, automation := range file.Automations {
+		ids[i] = configString(automation, "id")
+	}
+	assignConfigIDs(ids, func(id *string) *string { return id }, automationImportNewIDs, time.Now())
+
+	for i, automation := range file.Automations {
+		id, alias := ids[i], configString(automation, "alias")
+		automation["id"] = id
+
+		printInfo("Creating automation '%s'...", alias)
+		if err := client.CreateAutomationRaw(id, automation); err != nil {
+			return fmt.Errorf("failed to create automation %q: %w", alias, err)
+		}
+		printSuccess("Automation imported: %s (ID: %s)", alias, id)
+	}
+
+	printFooter(os.Stderr, "Imported: %d", len(file.Automations))
+	return reloadOrNote(cfg, "automation", "You may need to reload automations or restart Home Assistant for the imported automations to appear.")
+}
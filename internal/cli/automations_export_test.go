@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/dorinclisu/hass-cli/internal/testutil"
+)
+
+func TestListAutomationConfigIDs(t *testing.T) {
+	mock := testutil.NewRESTMock(t, testToken)
+	mock.HandleJSON(http.MethodGet, "/api/states", http.StatusOK, []api.State{
+		{EntityID: "automation.wake_up", Attributes: map[string]interface{}{"friendly_name": "Wake up", "id": "1700000000002"}},
+		{EntityID: "automation.yaml_only", Attributes: map[string]interface{}{"friendly_name": "From YAML"}},
+		{EntityID: "automation.arrive", Attributes: map[string]interface{}{"friendly_name": "Arrive home", "id": 1700000000001.0}},
+		{EntityID: "light.kitchen", Attributes: map[string]interface{}{"id": "not-an-automation"}},
+	})
+
+	client := api.NewClient(mock.URL(), testToken, 5*time.Second)
+	ids, err := listAutomationConfigIDs(client)
+	if err != nil {
+		t.Fatalf("listAutomationConfigIDs() error = %v", err)
+	}
+	if got := strings.Join(ids, ","); got != "1700000000001,1700000000002" {
+		t.Errorf("listAutomationConfigIDs() = %s, want 1700000000001,1700000000002", got)
+	}
+}
+
+func TestAutomationFile_RoundTrip(t *testing.T) {
+	file := &AutomationFile{Automations: []map[string]interface{}{{
+		"id":           "1700000000001",
+		"alias":        "Arrive home",
+		"mode":         "queued",
+		"max":          5.0,
+		"max_exceeded": "silent",
+		"variables":    map[string]interface{}{"target": "light.hall"},
+		"trigger":      []interface{}{map[string]interface{}{"platform": "state", "entity_id": "person.me", "to": "home"}},
+		"action":       []interface{}{map[string]interface{}{"service": "light.turn_on", "target": map[string]interface{}{"entity_id": "{{ target }}"}}},
+	}}}
+
+	for _, asJSON := range []bool{false, true} {
+		var buf bytes.Buffer
+		if err := writeExport(&buf, file, asJSON); err != nil {
+			t.Fatalf("writeExport(json=%v) error = %v", asJSON, err)
+		}
+
+		path := filepath.Join(t.TempDir(), "automations")
+		if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := loadAutomationFile(path)
+		if err != nil {
+			t.Fatalf("loadAutomationFile(json=%v) error = %v\n%s", asJSON, err, buf.String())
+		}
+		// YAML reads 5 back as an int, so compare what would be sent to Home Assistant
+		gotJSON, _ := json.Marshal(got.Automations)
+		wantJSON, _ := json.Marshal(file.Automations)
+		if string(gotJSON) != string(wantJSON) {
+			t.Errorf("round trip (json=%v) = %s, want %s", asJSON, gotJSON, wantJSON)
+		}
+	}
+}
+
+func TestAutomationsExportImport_KeepsRawConfig(t *testing.T) {
+	stored := map[string]interface{}{
+		"id":                "1700000000001",
+		"alias":             "Arrive home",
+		"mode":              "restart",
+		"trace":             map[string]interface{}{"stored_traces": 10.0},
+		"trigger_variables": map[string]interface{}{"who": "person.me"},
+		"trigger":           []interface{}{map[string]interface{}{"platform": "state", "entity_id": "person.me", "to": "home"}},
+		"condition":         []interface{}{},
+		"action":            []interface{}{map[string]interface{}{"service": "light.turn_on"}},
+	}
+
+	var posted map[string]interface{}
+	mock := testutil.NewRESTMock(t, testToken)
+	mock.HandleJSON(http.MethodGet, "/api/config/automation/config/1700000000001", http.StatusOK, stored)
+	mock.Handle(http.MethodPost, "/api/config/automation/config/1700000000001", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+			t.Errorf("decode import body: %v", err)
+		}
+		w.Write([]byte(`{"result":"ok"}`))
+	})
+
+	defer func() {
+		serverURL, token, configPath = "", "", ""
+		automationExportOutput = ""
+		rootCmd.SetArgs(nil)
+	}()
+
+	path := filepath.Join(t.TempDir(), "automations.yaml")
+	base := []string{"--url", mock.URL(), "--token", testToken, "--config", filepath.Join(t.TempDir(), "none.yaml")}
+	for _, args := range [][]string{
+		{"automations", "export", "1700000000001", "-o", path},
+		{"automations", "import", path},
+	} {
+		rootCmd.SetArgs(append(base, args...))
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("%v: error = %v", args, err)
+		}
+	}
+
+	if !reflect.DeepEqual(posted, stored) {
+		t.Errorf("imported config = %v, want %v", posted, stored)
+	}
+}
+
+func TestLoadAutomationFile_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "empty", content: "", wantErr: "no automations"},
+		{name: "wrong key", content: "scenes:\n  - alias: Wake up\n", wantErr: "no automations"},
+		{name: "missing alias", content: "- mode: single\n", wantErr: "alias is required"},
+		{name: "scalar", content: "automations", wantErr: "expected a list"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "automations.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := loadAutomationFile(path); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("loadAutomationFile() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
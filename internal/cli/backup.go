@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+	"github.com/spf13/cobra"
+)
+
+var (
+	backupName   string
+	backupAgents []string
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Create and list Home Assistant backups",
+	Long: `Manage the backups made by Home Assistant's backup integration.
+
+These are the full backups Home Assistant itself creates (the ones shown under
+Settings > System > Backups), not an export of hass-cli's view of the
+configuration.
+
+Examples:
+  hass-cli backup list                   # List existing backups
+  hass-cli backup create                 # Start a new backup
+  hass-cli backup create --name nightly  # Start a named backup`,
+}
+
+var backupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List existing backups",
+	Long: `List the backups known to the backup integration, newest first.
+
+Examples:
+  hass-cli backup list
+  hass-cli backup list --json`,
+	Args: cobra.NoArgs,
+	RunE: runBackupList,
+}
+
+var backupCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Start a backup",
+	Long: `Start generating a backup with the backup integration.
+
+Backups take a while, so this returns as soon as Home Assistant has accepted
+the request and prints the backup (or backup job) ID. Generation continues on
+the server; use 'hass-cli backup list' to see the finished backup.
+
+The backup is stored on the local disk unless --agent names other backup
+locations, by agent ID (e.g. cloud.cloud) or name. --agent can be repeated.
+
+Examples:
+  hass-cli backup create
+  hass-cli backup create --name "Before upgrade"
+  hass-cli backup create --agent backup.local --agent cloud.cloud`,
+	Args: cobra.NoArgs,
+	RunE: runBackupCreate,
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	backupCmd.AddCommand(backupListCmd)
+	backupCmd.AddCommand(backupCreateCmd)
+
+	backupCreateCmd.Flags().StringVar(&backupName, "name", "", "Backup name (default: chosen by Home Assistant)")
+	backupCreateCmd.Flags().StringArrayVar(&backupAgents, "agent", nil, "Backup agent to store the backup with, by ID or name (default: the local agent, repeatable)")
+}
+
+// errNoBackupIntegration is returned when the server has no backup integration.
+var errNoBackupIntegration = errors.New("backups are not available (is the backup integration enabled?)")
+
+func runBackupList(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	printInfo("Connecting to Home Assistant...")
+	client, err := newWSClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer client.Close()
+
+	printInfo("Fetching backups...")
+	backups, err := client.ListBackups()
+	if err != nil {
+		if websocket.IsUnknownCommand(err) {
+			return errNoBackupIntegration
+		}
+		return fmt.Errorf("failed to get backups: %w", err)
+	}
+
+	sortBackups(backups)
+
+	return outputData(cmd.OutOrStdout(), backups, backupsTable(backups))
+}
+
+// sortBackups sorts backups newest first. Dates are compared as times, since
+// their offsets may differ; backups with an unreadable date go last.
+func sortBackups(backups []websocket.BackupInfo) {
+	dates := make(map[string]time.Time, len(backups))
+	for _, b := range backups {
+		if parsed, err := time.Parse(time.RFC3339, b.Date); err == nil {
+			dates[b.Date] = parsed
+		}
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		return dates[backups[i].Date].After(dates[backups[j].Date])
+	})
+}
+
+func backupsTable(backups []websocket.BackupInfo) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "BACKUP ID"}, {Header: "NAME", Width: 40}, {Header: "DATE"}, {Header: "SIZE"}},
+		Noun:    "backups",
+		Empty:   "No backups found",
+	}
+
+	for _, b := range backups {
+		date := b.Date
+		if parsed, err := time.Parse(time.RFC3339, b.Date); err == nil {
+			date = displayTime(parsed).Format("2006-01-02 15:04:05")
+		}
+
+		t.addRow(
+			b.BackupID,
+			b.Name,
+			date,
+			fmt.Sprintf("%.1f MB", b.Size),
+		)
+	}
+
+	return t
+}
+
+func runBackupCreate(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	printInfo("Connecting to Home Assistant...")
+	client, err := newWSClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer client.Close()
+
+	printInfo("Fetching backup agents...")
+	var agentIDs []string
+	agents, err := client.ListBackupAgents()
+	switch {
+	case websocket.IsUnknownCommand(err):
+		// Servers before 2025.1 have no agents and always back up locally
+		if len(backupAgents) > 0 {
+			return fmt.Errorf("this Home Assistant version does not support backup agents (requires 2025.1 or later)")
+		}
+	case err != nil:
+		return fmt.Errorf("failed to get backup agents: %w", err)
+	default:
+		if agentIDs, err = resolveBackupAgents(agents, backupAgents); err != nil {
+			return err
+		}
+	}
+
+	printInfo("Starting backup...")
+	backupID, err := client.CreateBackup(backupName, agentIDs)
+	if err != nil {
+		if websocket.IsUnknownCommand(err) {
+			return errNoBackupIntegration
+		}
+		return fmt.Errorf("failed to create backup: %w", err)
+	}
+
+	if jsonOutput {
+		return outputJSON(cmd.OutOrStdout(), map[string]interface{}{
+			"success":   true,
+			"backup_id": backupID,
+		})
+	}
+
+	if backupID != "" {
+		printSuccess("Backup started: %s", backupID)
+	} else {
+		printSuccess("Backup started")
+	}
+	printSuccess("Generation continues on the server; run 'hass-cli backup list' to see the finished backup.")
+	return nil
+}
+
+// resolveBackupAgents returns the IDs of the agents named, by ID or name, or
+// of the local agent when none are.
+func resolveBackupAgents(agents []websocket.BackupAgent, names []string) ([]string, error) {
+	if len(agents) == 0 {
+		return nil, fmt.Errorf("no backup agents are available")
+	}
+
+	if len(names) == 0 {
+		for _, a := range agents {
+			if strings.HasSuffix(a.AgentID, ".local") {
+				return []string{a.AgentID}, nil
+			}
+		}
+		return nil, fmt.Errorf("no local backup agent found; choose one with --agent (available: %s)", backupAgentIDs(agents))
+	}
+
+	var ids []string
+	for _, name := range names {
+		found := false
+		for _, a := range agents {
+			if strings.EqualFold(name, a.AgentID) || strings.EqualFold(name, a.Name) {
+				ids = append(ids, a.AgentID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown backup agent: %s (available: %s)", name, backupAgentIDs(agents))
+		}
+	}
+	return ids, nil
+}
+
+func backupAgentIDs(agents []websocket.BackupAgent) string {
+	ids := make([]string, len(agents))
+	for i, a := range agents {
+		ids[i] = a.AgentID
+	}
+	return strings.Join(ids, ", ")
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+)
+
+func TestSortBackups(t *testing.T) {
+	// As strings, newyork sorts before utc and tokyo after it, but in time
+	// newyork is the newest and tokyo the oldest
+	backups := []websocket.BackupInfo{
+		{BackupID: "utc", Date: "2025-01-05T03:00:00+00:00"},
+		{BackupID: "broken", Date: "yesterday"},
+		{BackupID: "tokyo", Date: "2025-01-05T11:30:00+09:00"},
+		{BackupID: "newyork", Date: "2025-01-04T23:00:00-05:00"},
+	}
+
+	sortBackups(backups)
+
+	var got []string
+	for _, b := range backups {
+		got = append(got, b.BackupID)
+	}
+	if strings.Join(got, ",") != "newyork,utc,tokyo,broken" {
+		t.Errorf("sortBackups() order = %v, want [newyork utc tokyo broken]", got)
+	}
+}
+
+func TestResolveBackupAgents(t *testing.T) {
+	agents := []websocket.BackupAgent{
+		{AgentID: "cloud.cloud", Name: "cloud"},
+		{AgentID: "hassio.local", Name: "local"},
+	}
+
+	tests := []struct {
+		name    string
+		agents  []websocket.BackupAgent
+		names   []string
+		want    string
+		wantErr bool
+	}{
+		{name: "defaults to local", agents: agents, want: "hassio.local"},
+		{name: "by id and name", agents: agents, names: []string{"Cloud", "hassio.local"}, want: "cloud.cloud,hassio.local"},
+		{name: "unknown", agents: agents, names: []string{"nas"}, wantErr: true},
+		{name: "no local agent", agents: agents[:1], wantErr: true},
+		{name: "no agents", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveBackupAgents(tt.agents, tt.names)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("resolveBackupAgents() = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveBackupAgents() error = %v", err)
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("resolveBackupAgents() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+	"github.com/spf13/cobra"
+)
+
+// Bulk commands act on every item matching their filters. To keep a missing
+// filter from silently targeting the whole install, they must be given at
+// least one filter flag or an explicit --all.
+
+// addAllFlag registers the standard --all flag on a bulk command.
+func addAllFlag(cmd *cobra.Command, all *bool) {
+	cmd.Flags().BoolVar(all, "all", false, "Operate on everything (required when no filter is given)")
+}
+
+// requireScope returns an error unless at least one of filterFlags was set on
+// cmd or all is true. --all cannot be combined with a filter.
+func requireScope(cmd *cobra.Command, all bool, filterFlags ...string) error {
+	var set []string
+	for _, name := range filterFlags {
+		if cmd.Flags().Changed(name) {
+			set = append(set, "--"+name)
+		}
+	}
+
+	if all && len(set) > 0 {
+		return fmt.Errorf("--all cannot be combined with %s", strings.Join(set, ", "))
+	}
+	if !all && len(set) == 0 {
+		names := make([]string, len(filterFlags))
+		for i, name := range filterFlags {
+			names[i] = "--" + name
+		}
+		return fmt.Errorf("refusing to operate on everything: specify a filter (%s) or pass --all", strings.Join(names, ", "))
+	}
+
+	return nil
+}
+
+// runBulk calls fn for each item in order, all over client's connection. If
+// the connection drops, it reconnects once and resumes with the item that
+// was in flight, since its command may not have reached the server. A second
+// connection failure, or any other error, stops the run. Errors report how
+// many items were processed.
+func runBulk[T any](client *websocket.Client, items []T, fn func(T) error) error {
+	reconnectedAt := -1
+
+	for i := 0; i < len(items); {
+		err := fn(items[i])
+		if err == nil {
+			i++
+			continue
+		}
+
+		if !websocket.IsConnectionError(err) || reconnectedAt >= 0 {
+			return fmt.Errorf("stopped after %d of %d items: %w", i, len(items), err)
+		}
+
+		fmt.Fprintf(os.Stderr, "Warning: connection lost after %d of %d items (%v), reconnecting...\n", i, len(items), err)
+		if err := client.Reconnect(); err != nil {
+			return fmt.Errorf("connection lost after %d of %d items and reconnect failed: %w", i, len(items), err)
+		}
+		reconnectedAt = i
+	}
+
+	if reconnectedAt >= 0 {
+		fmt.Fprintf(os.Stderr, "Reconnected: %d items processed before the connection was lost, %d after\n", reconnectedAt, len(items)-reconnectedAt)
+	}
+
+	return nil
+}
+
+// runBulkConcurrent sends cmds pipelined over client's connection, so the
+// server can work on them in parallel, and returns their results in order.
+// If the connection drops, it reconnects once and resends the commands that
+// failed with it, since they may not have reached the server; commands that
+// are resent must therefore be safe to repeat.
+func runBulkConcurrent(client *websocket.Client, cmds []websocket.Command) []websocket.CommandResult {
+	results := client.SendCommandsConcurrent(cmds)
+
+	var retry []int
+	for i, r := range results {
+		if websocket.IsConnectionError(r.Err) {
+			retry = append(retry, i)
+		}
+	}
+	if len(retry) == 0 {
+		return results
+	}
+
+	fmt.Fprintf(os.Stderr, "Warning: connection lost with %d of %d commands unanswered (%v), reconnecting...\n", len(retry), len(cmds), results[retry[0]].Err)
+	if err := client.Reconnect(); err != nil {
+		for _, i := range retry {
+			results[i].Err = fmt.Errorf("connection lost and reconnect failed: %w", err)
+		}
+		return results
+	}
+
+	resend := make([]websocket.Command, len(retry))
+	for j, i := range retry {
+		resend[j] = cmds[i]
+	}
+	for j, r := range client.SendCommandsConcurrent(resend) {
+		results[retry[j]] = r
+	}
+
+	fmt.Fprintf(os.Stderr, "Reconnected: resent %d commands\n", len(retry))
+	return results
+}
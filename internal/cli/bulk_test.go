@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dorinclisu/hass-cli/internal/testutil"
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+	"github.com/spf13/cobra"
+)
+
+const testToken = "cli-test-token"
+
+func TestRequireScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "no filter and no --all", args: nil, wantErr: true},
+		{name: "domain filter", args: []string{"--domain", "light"}},
+		{name: "area filter", args: []string{"--area", "kitchen"}},
+		{name: "--all", args: []string{"--all"}},
+		{name: "--all with filter", args: []string{"--all", "--domain", "light"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var all bool
+			var domain, area string
+			cmd := &cobra.Command{Use: "bulk"}
+			cmd.Flags().StringVar(&domain, "domain", "", "")
+			cmd.Flags().StringVar(&area, "area", "", "")
+			addAllFlag(cmd, &all)
+
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+
+			err := requireScope(cmd, all, "domain", "area")
+			if tt.wantErr && err == nil {
+				t.Error("requireScope() expected error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("requireScope() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestRunBulk_Reconnect(t *testing.T) {
+	tests := []struct {
+		name      string
+		dropAt    map[int]bool // call numbers on which the server drops the connection
+		wantItems []string
+		wantErr   bool
+	}{
+		{name: "no drop", wantItems: []string{"a", "b", "c", "d"}},
+		{name: "one drop resumes in-flight item", dropAt: map[int]bool{3: true}, wantItems: []string{"a", "b", "c", "d"}},
+		{name: "second drop stops", dropAt: map[int]bool{2: true, 4: true}, wantItems: []string{"a", "b"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := testutil.NewWSMock(t, testToken)
+			// Each connection is served by its own goroutine, so count atomically
+			var calls atomic.Int32
+			mock.Handle("item", func(msg map[string]interface{}) (interface{}, error) {
+				if tt.dropAt[int(calls.Add(1))] {
+					return nil, testutil.ErrDropConnection
+				}
+				return nil, nil
+			})
+
+			client, err := websocket.NewClient(mock.URL(), testToken, 5*time.Second)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			defer client.Close()
+
+			var done []string
+			err = runBulk(client, []string{"a", "b", "c", "d"}, func(item string) error {
+				if _, err := client.SendCommand("item", map[string]interface{}{"item": item}); err != nil {
+					return err
+				}
+				done = append(done, item)
+				return nil
+			})
+
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("runBulk() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if fmt.Sprint(done) != fmt.Sprint(tt.wantItems) {
+				t.Errorf("processed = %v, want %v", done, tt.wantItems)
+			}
+		})
+	}
+}
+
+func TestRunBulkConcurrent_Reconnect(t *testing.T) {
+	tests := []struct {
+		name       string
+		dropAt     map[int]bool // call numbers on which the server drops the connection
+		wantFailed []string
+	}{
+		{name: "no drop"},
+		{name: "one drop resends unanswered", dropAt: map[int]bool{3: true}},
+		{name: "second drop fails the rest", dropAt: map[int]bool{2: true, 4: true}, wantFailed: []string{"c", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := testutil.NewWSMock(t, testToken)
+			// Each connection is served by its own goroutine, so count atomically
+			var calls atomic.Int32
+			mock.Handle("item", func(msg map[string]interface{}) (interface{}, error) {
+				if tt.dropAt[int(calls.Add(1))] {
+					return nil, testutil.ErrDropConnection
+				}
+				return nil, nil
+			})
+
+			client, err := websocket.NewClient(mock.URL(), testToken, 5*time.Second)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			defer client.Close()
+
+			items := []string{"a", "b", "c", "d"}
+			cmds := make([]websocket.Command, len(items))
+			for i, item := range items {
+				cmds[i] = websocket.Command{Type: "item", Payload: map[string]interface{}{"item": item}}
+			}
+
+			var failed []string
+			for i, r := range runBulkConcurrent(client, cmds) {
+				if r.Err != nil {
+					failed = append(failed, items[i])
+				}
+			}
+			if fmt.Sprint(failed) != fmt.Sprint(tt.wantFailed) {
+				t.Errorf("failed = %v, want %v", failed, tt.wantFailed)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var calendarEventsDays float64
+
+var calendarsCmd = &cobra.Command{
+	Use:   "calendars",
+	Short: "List calendars and their events",
+	Long: `List the calendar entities of Home Assistant.
+
+Examples:
+  hass-cli calendars                                 # List calendars
+  hass-cli calendars events calendar.family          # Events in the next 7 days
+  hass-cli calendars events calendar.family --days 30
+  hass-cli calendars events calendar.family --json`,
+	Args: cobra.NoArgs,
+	RunE: runCalendars,
+}
+
+var calendarEventsCmd = &cobra.Command{
+	Use:   "events <entity_id>",
+	Short: "Show upcoming events of a calendar",
+	Long: `Show the events of a calendar from now until --days days ahead.
+
+Examples:
+  hass-cli calendars events calendar.family
+  hass-cli calendars events calendar.family --days 1
+  hass-cli calendars events calendar.family --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCalendarEvents,
+}
+
+func init() {
+	rootCmd.AddCommand(calendarsCmd)
+	calendarsCmd.AddCommand(calendarEventsCmd)
+
+	calendarEventsCmd.Flags().Float64Var(&calendarEventsDays, "days", 7, "Number of days ahead to show events for")
+}
+
+func runCalendars(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	printInfo("Fetching calendars...")
+	calendars, err := client.GetCalendars()
+	if err != nil {
+		if api.IsNotFound(err) {
+			return fmt.Errorf("calendars are not available (is the calendar integration set up?)")
+		}
+		return fmt.Errorf("failed to get calendars: %w", err)
+	}
+
+	return outputData(cmd.OutOrStdout(), calendars, calendarsTable(calendars))
+}
+
+func calendarsTable(calendars []api.Calendar) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "ENTITY ID"}, {Header: "NAME"}},
+		Noun:    "calendars",
+		Empty:   "No calendars found",
+	}
+
+	for _, c := range calendars {
+		t.addRow(c.EntityID, c.Name)
+	}
+
+	return t
+}
+
+func runCalendarEvents(cmd *cobra.Command, args []string) error {
+	if calendarEventsDays <= 0 {
+		return fmt.Errorf("--days must be positive")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	start := time.Now()
+	end := start.Add(time.Duration(calendarEventsDays * float64(24*time.Hour)))
+
+	printInfo("Fetching events...")
+	events, err := client.GetCalendarEvents(args[0], start, end)
+	if err != nil {
+		if api.IsNotFound(err) {
+			return fmt.Errorf("calendar not found: %s", args[0])
+		}
+		return fmt.Errorf("failed to get events: %w", err)
+	}
+
+	return outputData(cmd.OutOrStdout(), events, calendarEventsTable(events))
+}
+
+func calendarEventsTable(events []api.CalendarEvent) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "START"}, {Header: "END"}, {Header: "SUMMARY", Width: 40}, {Header: "LOCATION", Width: 30}},
+		Noun:    "events",
+		Empty:   "No upcoming events",
+	}
+
+	for _, e := range events {
+		t.addRow(
+			formatCalendarTime(e.Start),
+			formatCalendarTime(e.End),
+			e.Summary,
+			e.Location,
+		)
+	}
+
+	return t
+}
+
+// formatCalendarTime formats the start or end of an event: the date for
+// all-day events, otherwise the date and time of day.
+func formatCalendarTime(t api.CalendarEventTime) string {
+	if t.AllDay() {
+		return t.Date
+	}
+	parsed, err := time.Parse(time.RFC3339, t.DateTime)
+	if err != nil {
+		return t.DateTime
+	}
+	return displayTime(parsed).Format("2006-01-02 15:04")
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+)
+
+func TestCalendarEventsTable(t *testing.T) {
+	displayLocation = time.UTC
+	defer func() { displayLocation = time.Local }()
+
+	events := []api.CalendarEvent{
+		{
+			Summary: "Dentist",
+			Start:   api.CalendarEventTime{DateTime: "2024-01-16T09:00:00+01:00"},
+			End:     api.CalendarEventTime{DateTime: "2024-01-16T10:00:00+01:00"},
+		},
+		{
+			Summary: "Holiday",
+			Start:   api.CalendarEventTime{Date: "2024-01-18"},
+			End:     api.CalendarEventTime{Date: "2024-01-19"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeTable(&buf, calendarEventsTable(events)); err != nil {
+		t.Fatalf("writeTable() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{"2024-01-16 08:00", "2024-01-16 09:00", "Dentist", "2024-01-18", "2024-01-19", "Total: 2 events"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
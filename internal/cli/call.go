@@ -1,13 +1,21 @@
 package cli
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dorinclisu/hass-cli/internal/api"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var callCmd = &cobra.Command{
@@ -25,25 +33,89 @@ Examples:
   hass-cli call switch.toggle -e switch.fan
   hass-cli call scene.turn_on -e scene.movie_night
   hass-cli call homeassistant.restart
-  hass-cli call notify.mobile_app --data '{"message": "Hello!"}'`,
+  hass-cli call notify.mobile_app --data '{"message": "Hello!"}'
+  hass-cli call light.turn_on --data @defaults.json --data '{"brightness": 255}'
+  cat payload.json | hass-cli call notify.mobile_app --data -
+  hass-cli call notify.mobile_app --data-file payload.json --set message=Hi
+  hass-cli call light.turn_on -a kitchen --print-entities | xargs -n1 hass-cli state get
+  hass-cli call light.turn_off -e light.a -e light.b -e light.c --concurrent 8
+  hass-cli call light.turn_on -a kitchen --set brightness=128 --dry-run
+  hass-cli call light.turn_on -e light.desk --interactive
+
+Service data is built in this order, later values overriding earlier ones
+for the same key: -e/-a, then --data-file, then each --data object in the
+order given, then each --set field. Empty input (such as an empty file or
+nothing piped to stdin) adds no data.
+
+--interactive fetches the service's definition and asks for each of its
+fields that is not already set, showing its description, an example and
+whether it is required. Answers are parsed like --set values; optional
+fields left blank are skipped.
+
+Each -e entity must exist, so a typo such as light.livingroom is reported
+with the closest matching entity ID instead of silently doing nothing. Use
+--no-validate to skip the check.
+
+When -e is given more than once, the service is called once per entity, up
+to --concurrent calls at a time. Every call is made even if some fail; the
+result of each is reported at the end and the exit status is non-zero if any
+failed.
+
+--dry-run makes no call and prints the service call as YAML instead, ready
+to paste into an automation or script. entity_id, area_id and device_id go
+into its target section.
+
+Some services take much longer than the default request timeout. These get a
+longer timeout unless --call-timeout is given:
+  camera.snapshot, camera.record     2 minutes
+  backup.create, hassio.backup_full,
+  hassio.backup_partial              10 minutes
+
+Use --call-timeout to set the timeout for this call explicitly:
+  hass-cli call camera.snapshot -e camera.door --set filename=/tmp/door.jpg --call-timeout 90`,
 	Args: cobra.ExactArgs(1),
 	RunE: runCall,
 }
 
 var (
-	callEntityID string
-	callAreaID   string
-	callData     string
-	callDataArgs []string
+	callEntityIDs  []string
+	callConcurrent int
+	callAreaID     string
+	callData       []string
+	callDataFile   string
+	callDataArgs   []string
+	callTimeout    int
+
+	callPrintEntities bool
+	callDryRun        bool
+	callInteractive   bool
+	callNoValidate    bool
 )
 
+// slowServiceTimeouts are the request timeouts used for services known to
+// outlast the default --timeout, unless --call-timeout is given.
+var slowServiceTimeouts = map[string]time.Duration{
+	"camera.snapshot":       2 * time.Minute,
+	"camera.record":         2 * time.Minute,
+	"backup.create":         10 * time.Minute,
+	"hassio.backup_full":    10 * time.Minute,
+	"hassio.backup_partial": 10 * time.Minute,
+}
+
 func init() {
 	rootCmd.AddCommand(callCmd)
 
-	callCmd.Flags().StringVarP(&callEntityID, "entity", "e", "", "Target entity ID")
+	callCmd.Flags().StringArrayVarP(&callEntityIDs, "entity", "e", nil, "Target entity ID, can be repeated to call the service once per entity")
+	callCmd.Flags().IntVar(&callConcurrent, "concurrent", 4, "Maximum number of calls in flight when several entities are given")
 	callCmd.Flags().StringVarP(&callAreaID, "area", "a", "", "Target area ID")
-	callCmd.Flags().StringVar(&callData, "data", "", "Service data as JSON string")
+	callCmd.Flags().StringArrayVar(&callData, "data", nil, "Service data as a JSON object, @file or - for stdin; can be repeated and is merged in order")
+	callCmd.Flags().StringVar(&callDataFile, "data-file", "", "File with service data as a JSON object (- for stdin); merged before --data")
 	callCmd.Flags().StringArrayVarP(&callDataArgs, "set", "s", []string{}, "Set service data field (key=value), can be specified multiple times")
+	callCmd.Flags().BoolVar(&callPrintEntities, "print-entities", false, "Print only the IDs of the entities whose state changed, one per line")
+	callCmd.Flags().BoolVar(&callDryRun, "dry-run", false, "Print the call as automation YAML instead of making it")
+	callCmd.Flags().BoolVar(&callNoValidate, "no-validate", false, "Call the service even if an --entity does not exist")
+	callCmd.Flags().BoolVarP(&callInteractive, "interactive", "i", false, "Prompt for the service's fields on stdin")
+	callCmd.Flags().IntVar(&callTimeout, "call-timeout", 0, "Timeout in seconds for this service call (default: --timeout, longer for known slow services)")
 }
 
 func runCall(cmd *cobra.Command, args []string) error {
@@ -56,29 +128,319 @@ func runCall(cmd *cobra.Command, args []string) error {
 	domain := parts[0]
 	service := parts[1]
 
+	dataArgs := callData
+	if callDataFile == "-" {
+		if slices.Contains(dataArgs, "-") {
+			return fmt.Errorf("stdin can only be read once: --data-file - cannot be combined with --data -")
+		}
+		dataArgs = append([]string{"-"}, dataArgs...)
+	} else if callDataFile != "" {
+		dataArgs = append([]string{"@" + callDataFile}, dataArgs...)
+	}
+
+	entityID := ""
+	if len(callEntityIDs) == 1 {
+		entityID = callEntityIDs[0]
+	}
+
+	if callInteractive && slices.Contains(dataArgs, "-") {
+		return fmt.Errorf("--interactive reads answers from stdin and cannot be combined with --data -")
+	}
+
+	data, err := buildServiceData(entityID, callAreaID, dataArgs, callDataArgs)
+	if err != nil {
+		return err
+	}
+
+	if callInteractive {
+		if err := promptForService(cmd, domain, service, data); err != nil {
+			return err
+		}
+	}
+
+	if callDryRun {
+		snippet, err := serviceCallYAML(fullService, data, callEntityIDs)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(cmd.OutOrStdout(), snippet)
+		return nil
+	}
+
 	cfg, err := loadConfig()
 	if err != nil {
 		return err
 	}
 
-	// Build service data
+	if callTimeout < 0 {
+		return fmt.Errorf("--call-timeout must not be negative")
+	}
+	client := newRESTClientWithTimeout(cfg, serviceCallTimeout(fullService, callTimeout, time.Duration(timeout)*time.Second))
+
+	// all and none target every entity of the domain, or none, and have no state
+	checkIDs := slices.DeleteFunc(slices.Clone(callEntityIDs), func(id string) bool {
+		return id == "all" || id == "none"
+	})
+	if len(checkIDs) > 0 && !callNoValidate {
+		if err := checkEntitiesExist(client, checkIDs); err != nil {
+			return err
+		}
+	}
+
+	if len(callEntityIDs) > 1 {
+		if callConcurrent < 1 {
+			return fmt.Errorf("--concurrent must be at least 1")
+		}
+		return runCallPerEntity(cmd, client, domain, service, data)
+	}
+
+	printInfo("Calling %s.%s...", domain, service)
+	changedStates, err := client.CallService(domain, service, data)
+	if err != nil {
+		return fmt.Errorf("service call failed: %w", err)
+	}
+
+	if callPrintEntities {
+		for _, entityID := range changedEntityIDs(changedStates) {
+			fmt.Fprintln(cmd.OutOrStdout(), entityID)
+		}
+		return nil
+	}
+
+	if jsonOutput {
+		return outputJSON(cmd.OutOrStdout(), map[string]interface{}{
+			"success":        true,
+			"changed_states": changedStates,
+		})
+	}
+
+	printSuccess("Service %s.%s called successfully", domain, service)
+
+	if len(changedStates) > 0 {
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "\nChanged states (%d):\n", len(changedStates))
+		for _, state := range changedStates {
+			fmt.Fprintf(out, "  %s: %s\n", state.EntityID, state.State)
+		}
+	}
+
+	return nil
+}
+
+// serviceTargetKeys are the service data fields that belong in the target
+// section of a service call.
+var serviceTargetKeys = []string{"entity_id", "area_id", "device_id"}
+
+// serviceCallYAML renders a service call as the YAML of an automation
+// action. Several entityIDs, which are called one at a time by call, become
+// a single call targeting all of them.
+func serviceCallYAML(fullService string, data map[string]interface{}, entityIDs []string) (string, error) {
+	snippet := struct {
+		Service string                 `yaml:"service"`
+		Target  map[string]interface{} `yaml:"target,omitempty"`
+		Data    map[string]interface{} `yaml:"data,omitempty"`
+	}{Service: fullService}
+
+	rest := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		rest[k] = v
+	}
+	if len(entityIDs) > 1 {
+		if _, ok := rest["entity_id"]; !ok {
+			rest["entity_id"] = entityIDs
+		}
+	}
+
+	for _, key := range serviceTargetKeys {
+		if v, ok := rest[key]; ok {
+			if snippet.Target == nil {
+				snippet.Target = make(map[string]interface{})
+			}
+			snippet.Target[key] = v
+			delete(rest, key)
+		}
+	}
+	if len(rest) > 0 {
+		snippet.Data = rest
+	}
+	if redactOutput {
+		snippet.Data, _ = redactValue(snippet.Data).(map[string]interface{})
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(snippet); err != nil {
+		return "", fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode YAML: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// CallResult is the outcome of calling a service for one entity.
+type CallResult struct {
+	EntityID      string      `json:"entity_id"`
+	Success       bool        `json:"success"`
+	Error         string      `json:"error,omitempty"`
+	ChangedStates []api.State `json:"changed_states,omitempty"`
+}
+
+// runCallPerEntity calls the service once for each --entity, with data as
+// the shared service data, and reports the results together.
+func runCallPerEntity(cmd *cobra.Command, client *api.Client, domain, service string, data map[string]interface{}) error {
+	printInfo("Calling %s.%s for %d entities...", domain, service, len(callEntityIDs))
+	results := callForEntities(callEntityIDs, callConcurrent, func(entityID string) ([]api.State, error) {
+		// The entity comes first so --data and --set can still override it
+		entityData := map[string]interface{}{"entity_id": entityID}
+		for k, v := range data {
+			entityData[k] = v
+		}
+		return client.CallService(domain, service, entityData)
+	})
+
+	var changedStates []api.State
+	failed := 0
+	for _, r := range results {
+		changedStates = append(changedStates, r.ChangedStates...)
+		if !r.Success {
+			failed++
+		}
+	}
+
+	var err error
+	if failed > 0 {
+		err = &ExitError{Code: 1}
+	}
+
+	if callPrintEntities {
+		for _, entityID := range changedEntityIDs(changedStates) {
+			fmt.Fprintln(cmd.OutOrStdout(), entityID)
+		}
+		for _, r := range results {
+			if !r.Success {
+				fmt.Fprintf(os.Stderr, "Error: %s: %s\n", r.EntityID, r.Error)
+			}
+		}
+		return err
+	}
+
+	if jsonOutput {
+		if jsonErr := outputJSON(cmd.OutOrStdout(), map[string]interface{}{
+			"success":        failed == 0,
+			"results":        results,
+			"changed_states": changedStates,
+		}); jsonErr != nil {
+			return jsonErr
+		}
+		return err
+	}
+
+	printSuccess("Service %s.%s called for %d entities: %d succeeded, %d failed", domain, service, len(results), len(results)-failed, failed)
+	out := cmd.OutOrStdout()
+	for _, r := range results {
+		if r.Success {
+			fmt.Fprintf(out, "  %s: ok\n", r.EntityID)
+		} else {
+			fmt.Fprintf(out, "  %s: failed: %s\n", r.EntityID, r.Error)
+		}
+	}
+
+	if len(changedStates) > 0 {
+		fmt.Fprintf(out, "\nChanged states (%d):\n", len(changedStates))
+		for _, state := range changedStates {
+			fmt.Fprintf(out, "  %s: %s\n", state.EntityID, state.State)
+		}
+	}
+
+	return err
+}
+
+// callForEntities runs call for every entity ID with at most concurrency
+// calls in flight. It waits for all of them, whether or not some fail, and
+// returns the results in the order of entityIDs.
+func callForEntities(entityIDs []string, concurrency int, call func(entityID string) ([]api.State, error)) []CallResult {
+	results := make([]CallResult, len(entityIDs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, entityID := range entityIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			states, err := call(entityID)
+			results[i] = CallResult{EntityID: entityID, Success: err == nil, ChangedStates: states}
+			if err != nil {
+				results[i].Error = err.Error()
+			}
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
+
+// changedEntityIDs returns the IDs of the changed states in the order Home
+// Assistant reported them, without duplicates.
+func changedEntityIDs(states []api.State) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, state := range states {
+		if !seen[state.EntityID] {
+			seen[state.EntityID] = true
+			ids = append(ids, state.EntityID)
+		}
+	}
+	return ids
+}
+
+// serviceCallTimeout returns the request timeout for calling service:
+// override seconds when given, otherwise the longer of base and the
+// service's entry in slowServiceTimeouts.
+func serviceCallTimeout(service string, override int, base time.Duration) time.Duration {
+	if override > 0 {
+		return time.Duration(override) * time.Second
+	}
+	if slow, ok := slowServiceTimeouts[service]; ok && slow > base {
+		return slow
+	}
+	return base
+}
+
+// buildServiceData assembles the data for a service call. Sources are
+// applied in order, later keys overriding earlier ones: the entity and area
+// targets, each --data object, then each --set field.
+func buildServiceData(entityID, areaID string, dataArgs, setArgs []string) (map[string]interface{}, error) {
 	data := make(map[string]interface{})
 
 	// Add entity_id if specified
-	if callEntityID != "" {
-		data["entity_id"] = callEntityID
+	if entityID != "" {
+		data["entity_id"] = entityID
 	}
 
 	// Add area_id if specified
-	if callAreaID != "" {
-		data["area_id"] = callAreaID
+	if areaID != "" {
+		data["area_id"] = areaID
 	}
 
-	// Parse --data JSON if provided
-	if callData != "" {
+	// Merge --data objects
+	for _, arg := range dataArgs {
+		raw, err := readDataArg(arg)
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(raw)) == 0 {
+			continue
+		}
+
 		var jsonData map[string]interface{}
-		if err := json.Unmarshal([]byte(callData), &jsonData); err != nil {
-			return fmt.Errorf("invalid JSON in --data: %w", err)
+		if err := json.Unmarshal(raw, &jsonData); err != nil {
+			return nil, fmt.Errorf("invalid JSON in --data: %w", err)
 		}
 		for k, v := range jsonData {
 			data[k] = v
@@ -86,46 +448,148 @@ func runCall(cmd *cobra.Command, args []string) error {
 	}
 
 	// Parse --set arguments
-	for _, arg := range callDataArgs {
+	for _, arg := range setArgs {
 		keyValue := strings.SplitN(arg, "=", 2)
 		if len(keyValue) != 2 {
-			return fmt.Errorf("invalid --set format: %s (expected key=value)", arg)
+			return nil, fmt.Errorf("invalid --set format: %s (expected key=value)", arg)
 		}
 		key := keyValue[0]
 		value := keyValue[1]
 
-		// Try to parse as JSON for complex values (numbers, booleans, arrays, objects)
-		var jsonValue interface{}
-		if err := json.Unmarshal([]byte(value), &jsonValue); err == nil {
-			data[key] = jsonValue
-		} else {
-			data[key] = value
-		}
+		data[key] = parseFieldValue(value)
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	return data, nil
+}
 
-	printInfo("Calling %s.%s...", domain, service)
-	changedStates, err := client.CallService(domain, service, data)
+// parseFieldValue parses a --set or prompted value as JSON for complex
+// values (numbers, booleans, arrays, objects), falling back to the string.
+func parseFieldValue(value string) interface{} {
+	var jsonValue interface{}
+	if err := json.Unmarshal([]byte(value), &jsonValue); err == nil {
+		return jsonValue
+	}
+	return value
+}
+
+// promptForService looks up domain.service and prompts for its fields.
+func promptForService(cmd *cobra.Command, domain, service string, data map[string]interface{}) error {
+	cfg, err := loadConfig()
 	if err != nil {
-		return fmt.Errorf("service call failed: %w", err)
+		return err
 	}
 
-	if jsonOutput {
-		return outputJSON(map[string]interface{}{
-			"success":        true,
-			"changed_states": changedStates,
-		})
+	printInfo("Fetching service details...")
+	services, err := newRESTClient(cfg).GetServices()
+	if err != nil {
+		return fmt.Errorf("failed to get services: %w", err)
+	}
+	svcInfo, ok := services[domain][service]
+	if !ok {
+		return fmt.Errorf("service not found: %s.%s", domain, service)
 	}
 
-	fmt.Printf("Service %s.%s called successfully\n", domain, service)
+	return promptServiceFields(os.Stdin, cmd.ErrOrStderr(), svcInfo.Fields, data)
+}
 
-	if len(changedStates) > 0 {
-		fmt.Printf("\nChanged states (%d):\n", len(changedStates))
-		for _, state := range changedStates {
-			fmt.Printf("  %s: %s\n", state.EntityID, state.State)
+// promptServiceFields asks for each field not already in data, required
+// fields first, and stores the answers in data. Prompts go to out so they
+// stay out of the command's output. Required fields are asked again until
+// answered; blank optional fields are skipped.
+func promptServiceFields(in io.Reader, out io.Writer, fields map[string]api.ServiceField, data map[string]interface{}) error {
+	fields = flattenServiceFields(fields)
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		if _, ok := data[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if fields[names[i]].Required != fields[names[j]].Required {
+			return fields[names[i]].Required
+		}
+		return names[i] < names[j]
+	})
+
+	reader := bufio.NewReader(in)
+	for _, name := range names {
+		field := fields[name]
+
+		fmt.Fprintln(out)
+		label := name
+		if field.Required {
+			label += " (required)"
+		}
+		fmt.Fprintln(out, label)
+		if field.Description != "" {
+			fmt.Fprintf(out, "  %s\n", field.Description)
+		}
+		if field.Example != nil {
+			fmt.Fprintf(out, "  Example: %v\n", field.Example)
+		}
+
+		for {
+			fmt.Fprintf(out, "%s: ", name)
+			input, err := reader.ReadString('\n')
+			input = strings.TrimSpace(input)
+			if err != nil && err != io.EOF {
+				return fmt.Errorf("failed to read %s: %w", name, err)
+			}
+
+			if input != "" {
+				data[name] = parseFieldValue(input)
+				break
+			}
+			if !field.Required {
+				break
+			}
+			if err == io.EOF {
+				return fmt.Errorf("no value given for required field %s", name)
+			}
+			fmt.Fprintf(out, "%s is required\n", name)
 		}
 	}
 
 	return nil
 }
+
+// flattenServiceFields replaces each section of fields, such as
+// advanced_fields, with the fields it groups. Sections only structure the
+// UI; their fields are given at the top level of the service data.
+func flattenServiceFields(fields map[string]api.ServiceField) map[string]api.ServiceField {
+	flat := make(map[string]api.ServiceField, len(fields))
+	for name, field := range fields {
+		if field.Fields == nil {
+			flat[name] = field
+			continue
+		}
+		for nested, f := range flattenServiceFields(field.Fields) {
+			flat[nested] = f
+		}
+	}
+	return flat
+}
+
+// readDataArg returns the JSON given to --data: the contents of a file for
+// "@path", standard input for "-", or the argument itself otherwise.
+func readDataArg(arg string) ([]byte, error) {
+	switch {
+	case arg == "-":
+		if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+			return nil, fmt.Errorf("--data - reads JSON from stdin, but stdin is a terminal")
+		}
+		raw, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read --data from stdin: %w", err)
+		}
+		return raw, nil
+	case strings.HasPrefix(arg, "@"):
+		raw, err := os.ReadFile(arg[1:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read --data file: %w", err)
+		}
+		return raw, nil
+	default:
+		return []byte(arg), nil
+	}
+}
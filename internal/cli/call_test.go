@@ -0,0 +1,347 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/dorinclisu/hass-cli/internal/testutil"
+)
+
+func TestBuildServiceData_MergeOrder(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "defaults.json")
+	if err := os.WriteFile(file, []byte(`{"brightness": 50, "transition": 2, "color_name": "red"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := buildServiceData(
+		"light.kitchen",
+		"",
+		[]string{
+			"@" + file,
+			`{"brightness": 200, "entity_id": "light.hall"}`,
+			`{"transition": 5}`,
+		},
+		[]string{"color_name=blue"},
+	)
+	if err != nil {
+		t.Fatalf("buildServiceData() error = %v", err)
+	}
+
+	want := map[string]interface{}{
+		"entity_id":  "light.hall", // --data overrides -e
+		"brightness": float64(200), // later --data overrides earlier
+		"transition": float64(5),
+		"color_name": "blue", // --set overrides --data
+	}
+	if len(data) != len(want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+	for k, v := range want {
+		if data[k] != v {
+			t.Errorf("data[%q] = %v, want %v", k, data[k], v)
+		}
+	}
+}
+
+func TestBuildServiceData_EmptyInput(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "empty.json")
+	if err := os.WriteFile(file, []byte("\n  \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := buildServiceData("light.kitchen", "", []string{"@" + file, "", `{"brightness": 10}`}, nil)
+	if err != nil {
+		t.Fatalf("buildServiceData() error = %v", err)
+	}
+	if len(data) != 2 || data["entity_id"] != "light.kitchen" || data["brightness"] != float64(10) {
+		t.Errorf("data = %v, want entity_id and brightness only", data)
+	}
+}
+
+func TestBuildServiceData_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []string
+		set  []string
+	}{
+		{"invalid JSON", []string{`{brightness}`}, nil},
+		{"not an object", []string{`[1, 2]`}, nil},
+		{"missing file", []string{"@/nonexistent/data.json"}, nil},
+		{"invalid set", nil, []string{"brightness"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := buildServiceData("", "", tt.data, tt.set); err == nil {
+				t.Errorf("buildServiceData(%q, %q) expected error", tt.data, tt.set)
+			}
+		})
+	}
+}
+
+func TestPromptServiceFields(t *testing.T) {
+	fields := map[string]api.ServiceField{
+		"message":    {Description: "Message body", Required: true, Example: "Hello"},
+		"title":      {Description: "Optional title"},
+		"data":       {},
+		"target":     {},
+		"brightness": {},
+	}
+	data := map[string]interface{}{"target": "phone"}
+
+	// message is asked first and again after a blank answer; optional
+	// fields follow in name order: brightness, data, title
+	in := strings.NewReader("\nHi there\n42\n\n")
+	var out bytes.Buffer
+	if err := promptServiceFields(in, &out, fields, data); err != nil {
+		t.Fatalf("promptServiceFields() error = %v", err)
+	}
+
+	want := map[string]interface{}{"target": "phone", "message": "Hi there", "brightness": 42.0}
+	if len(data) != len(want) {
+		t.Fatalf("data = %v, want %v", data, want)
+	}
+	for k, v := range want {
+		if data[k] != v {
+			t.Errorf("data[%q] = %v (%T), want %v", k, data[k], data[k], v)
+		}
+	}
+
+	prompts := out.String()
+	for _, s := range []string{"message (required)", "Message body", "Example: Hello", "message is required"} {
+		if !strings.Contains(prompts, s) {
+			t.Errorf("prompts missing %q:\n%s", s, prompts)
+		}
+	}
+	if strings.Contains(prompts, "target:") {
+		t.Errorf("prompted for a field that was already set:\n%s", prompts)
+	}
+
+	if err := promptServiceFields(strings.NewReader(""), &out, fields, map[string]interface{}{}); err == nil {
+		t.Error("promptServiceFields() with no answer for a required field expected error")
+	}
+}
+
+func TestPromptServiceFields_Sections(t *testing.T) {
+	// light.turn_on groups most of its fields in sections
+	raw := `{
+		"brightness_pct": {"selector": {"number": {}}},
+		"advanced_fields": {
+			"collapsed": true,
+			"fields": {
+				"transition": {"description": "Duration in seconds"},
+				"flash": {}
+			}
+		}
+	}`
+	var fields map[string]api.ServiceField
+	if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+		t.Fatal(err)
+	}
+
+	// fields in name order: brightness_pct, flash, transition
+	in := strings.NewReader("50\n\n2\n")
+	var out bytes.Buffer
+	data := map[string]interface{}{}
+	if err := promptServiceFields(in, &out, fields, data); err != nil {
+		t.Fatalf("promptServiceFields() error = %v", err)
+	}
+
+	want := map[string]interface{}{"brightness_pct": 50.0, "transition": 2.0}
+	if len(data) != len(want) {
+		t.Fatalf("data = %v, want %v", data, want)
+	}
+	for k, v := range want {
+		if data[k] != v {
+			t.Errorf("data[%q] = %v, want %v", k, data[k], v)
+		}
+	}
+
+	prompts := out.String()
+	if strings.Contains(prompts, "advanced_fields") {
+		t.Errorf("prompted for a section:\n%s", prompts)
+	}
+	if !strings.Contains(prompts, "Duration in seconds") {
+		t.Errorf("prompts missing the nested field:\n%s", prompts)
+	}
+}
+
+func TestChangedEntityIDs(t *testing.T) {
+	states := []api.State{
+		{EntityID: "light.kitchen", State: "on"},
+		{EntityID: "light.counter", State: "on"},
+		{EntityID: "light.kitchen", State: "on"},
+	}
+
+	got := changedEntityIDs(states)
+	if !slices.Equal(got, []string{"light.kitchen", "light.counter"}) {
+		t.Errorf("changedEntityIDs() = %v", got)
+	}
+	if got := changedEntityIDs(nil); len(got) != 0 {
+		t.Errorf("changedEntityIDs(nil) = %v, want empty", got)
+	}
+}
+
+func TestCallForEntities(t *testing.T) {
+	var inFlight, maxInFlight int32
+	ids := []string{"light.a", "light.b", "light.c", "light.d", "light.e"}
+
+	results := callForEntities(ids, 2, func(entityID string) ([]api.State, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		if entityID == "light.c" {
+			return nil, errors.New("entity not found")
+		}
+		return []api.State{{EntityID: entityID, State: "off"}}, nil
+	})
+
+	if got := atomic.LoadInt32(&maxInFlight); got > 2 {
+		t.Errorf("max calls in flight = %d, want at most 2", got)
+	}
+	if len(results) != len(ids) {
+		t.Fatalf("got %d results, want %d", len(results), len(ids))
+	}
+	for i, r := range results {
+		if r.EntityID != ids[i] {
+			t.Errorf("results[%d].EntityID = %q, want %q", i, r.EntityID, ids[i])
+		}
+		wantOK := ids[i] != "light.c"
+		if r.Success != wantOK {
+			t.Errorf("results[%d].Success = %v, want %v", i, r.Success, wantOK)
+		}
+		if !wantOK && r.Error != "entity not found" {
+			t.Errorf("results[%d].Error = %q", i, r.Error)
+		}
+		if wantOK && len(r.ChangedStates) != 1 {
+			t.Errorf("results[%d].ChangedStates = %v", i, r.ChangedStates)
+		}
+	}
+}
+
+func TestServiceCallYAML(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      map[string]interface{}
+		entityIDs []string
+		want      string
+	}{
+		{
+			name: "target and data",
+			data: map[string]interface{}{"entity_id": "light.kitchen", "area_id": "hall", "brightness": float64(128)},
+			want: "service: light.turn_on\ntarget:\n  area_id: hall\n  entity_id: light.kitchen\ndata:\n  brightness: 128\n",
+		},
+		{
+			name:      "several entities",
+			data:      map[string]interface{}{"transition": "2"},
+			entityIDs: []string{"light.a", "light.b"},
+			want:      "service: light.turn_on\ntarget:\n  entity_id:\n    - light.a\n    - light.b\ndata:\n  transition: \"2\"\n",
+		},
+		{
+			name: "no data",
+			want: "service: light.turn_on\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := serviceCallYAML("light.turn_on", tt.data, tt.entityIDs)
+			if err != nil {
+				t.Fatalf("serviceCallYAML() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("serviceCallYAML() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceCallTimeout(t *testing.T) {
+	base := 30 * time.Second
+
+	tests := []struct {
+		name     string
+		service  string
+		override int
+		base     time.Duration
+		want     time.Duration
+	}{
+		{"default", "light.turn_on", 0, base, base},
+		{"slow service", "backup.create", 0, base, 10 * time.Minute},
+		{"override", "light.turn_on", 5, base, 5 * time.Second},
+		{"override slow service", "camera.snapshot", 90, base, 90 * time.Second},
+		{"base exceeds slow default", "camera.snapshot", 0, time.Hour, time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serviceCallTimeout(tt.service, tt.override, tt.base); got != tt.want {
+				t.Errorf("serviceCallTimeout(%q, %d, %v) = %v, want %v", tt.service, tt.override, tt.base, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCall_SpecialEntityIDs(t *testing.T) {
+	mock := testutil.NewRESTMock(t, testToken)
+	mock.HandleJSON(http.MethodGet, "/api/states", http.StatusOK, []api.State{{EntityID: "light.kitchen", State: "on"}})
+	mock.HandleJSON(http.MethodPost, "/api/services/light/turn_off", http.StatusOK, []api.State{})
+
+	defer func() {
+		serverURL, token, configPath = "", "", ""
+		callEntityIDs = nil
+		rootCmd.SetArgs(nil)
+	}()
+
+	run := func(entityID string) error {
+		callEntityIDs = nil
+		rootCmd.SetArgs([]string{"call", "light.turn_off", "-e", entityID,
+			"--url", mock.URL(), "--token", testToken, "--config", filepath.Join(t.TempDir(), "none.yaml")})
+		var err error
+		captureStdout(t, func() { err = rootCmd.Execute() })
+		return err
+	}
+
+	for _, entityID := range []string{"all", "none"} {
+		if err := run(entityID); err != nil {
+			t.Errorf("call -e %s error = %v", entityID, err)
+		}
+	}
+
+	// Other unknown entities are still caught
+	if err := run("light.kitchn"); err == nil || !strings.Contains(err.Error(), "entity not found") {
+		t.Errorf("call -e light.kitchn error = %v, want entity not found", err)
+	}
+}
+
+func TestCall_StdinReadOnce(t *testing.T) {
+	defer func() {
+		callData, callDataFile = nil, ""
+		rootCmd.SetArgs(nil)
+	}()
+
+	rootCmd.SetArgs([]string{"call", "light.turn_on", "--data-file", "-", "--data", "-"})
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "cannot be combined with --data -") {
+		t.Errorf("call --data-file - --data - error = %v, want it rejected", err)
+	}
+}
@@ -0,0 +1,351 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var automationsTestConditionsCmd = &cobra.Command{
+	Use:   "test-conditions <automation_id>",
+	Short: "Evaluate an automation's conditions against the current state",
+	Long: `Check whether each of an automation's conditions passes right now, without
+triggering the automation or running any of its actions.
+
+Conditions are evaluated by rendering them as templates on the server:
+  template        the value_template as written
+  state           is_state() / is_state_attr() for each entity
+  numeric_state   the entity state or attribute against above / below
+  and, or, not    combined from their nested conditions
+
+Other condition types (time, sun, zone, trigger, device, ...) are reported as
+unsupported, as are conditions that depend on the trigger (e.g. templates
+using the trigger variable, which is not defined outside a run).
+
+Examples:
+  hass-cli automations test-conditions 1761025981191
+  hass-cli automations test-conditions automation.hallway_motion --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAutomationsTestConditions,
+}
+
+func init() {
+	automationsCmd.AddCommand(automationsTestConditionsCmd)
+}
+
+// Condition check results.
+const (
+	conditionPass        = "pass"
+	conditionFail        = "fail"
+	conditionUnsupported = "unsupported"
+	conditionError       = "error"
+	conditionDisabled    = "disabled"
+)
+
+// ConditionCheck is the result of evaluating one top-level condition.
+type ConditionCheck struct {
+	Index     int    `json:"index"`
+	Condition string `json:"condition"`
+	Result    string `json:"result"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+func runAutomationsTestConditions(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	configID, err := resolveAutomationConfigID(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	printInfo("Fetching automation configuration...")
+	config, err := client.GetAutomationConfig(configID)
+	if err != nil {
+		return fmt.Errorf("failed to get automation: %w", err)
+	}
+
+	printInfo("Evaluating %d conditions...", len(config.Conditions))
+	checks := checkConditions(config.Conditions, client.RenderTemplate)
+
+	table := conditionChecksTable(checks)
+	if len(checks) > 0 {
+		switch combineAll(checks) {
+		case conditionPass:
+			table.Hint = "All conditions pass: the actions would run if triggered now."
+		case conditionFail:
+			table.Hint = "At least one condition fails: the actions would not run if triggered now."
+		default:
+			table.Hint = "Some conditions could not be evaluated."
+		}
+	}
+
+	return outputData(cmd.OutOrStdout(), checks, table)
+}
+
+func conditionChecksTable(checks []ConditionCheck) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "#"}, {Header: "CONDITION"}, {Header: "RESULT"}, {Header: "DETAIL", Width: 60}},
+		Noun:    "conditions",
+		Empty:   "Automation has no conditions",
+	}
+
+	for _, c := range checks {
+		t.addRow(strconv.Itoa(c.Index), c.Condition, c.Result, c.Detail)
+	}
+
+	return t
+}
+
+// templateRenderer renders a template on the server.
+type templateRenderer func(template string) (string, error)
+
+// checkConditions evaluates each top-level condition with render.
+func checkConditions(conditions []map[string]interface{}, render templateRenderer) []ConditionCheck {
+	checks := make([]ConditionCheck, 0, len(conditions))
+	for i, cond := range conditions {
+		kind, _ := cond["condition"].(string)
+		result, detail := evaluateCondition(cond, render)
+		checks = append(checks, ConditionCheck{Index: i, Condition: kind, Result: result, Detail: detail})
+	}
+	return checks
+}
+
+// combineAll reports whether all checks pass, like the automation's implicit
+// "and" of its conditions. Disabled conditions are ignored.
+func combineAll(checks []ConditionCheck) string {
+	results := make([]string, 0, len(checks))
+	for _, c := range checks {
+		results = append(results, c.Result)
+	}
+	return combineResults("and", results)
+}
+
+// evaluateCondition evaluates a single condition and returns its result and
+// a short explanation.
+func evaluateCondition(cond map[string]interface{}, render templateRenderer) (string, string) {
+	if enabled, ok := cond["enabled"].(bool); ok && !enabled {
+		return conditionDisabled, ""
+	}
+
+	kind, _ := cond["condition"].(string)
+	switch kind {
+	case "and", "or", "not":
+		nested, ok := cond["conditions"].([]interface{})
+		if !ok {
+			return conditionError, "missing nested conditions"
+		}
+		results := make([]string, 0, len(nested))
+		var details []string
+		for _, raw := range nested {
+			sub, ok := raw.(map[string]interface{})
+			if !ok {
+				results = append(results, conditionUnsupported)
+				continue
+			}
+			result, _ := evaluateCondition(sub, render)
+			results = append(results, result)
+			subKind, _ := sub["condition"].(string)
+			details = append(details, subKind+"="+result)
+		}
+		return combineResults(kind, results), strings.Join(details, ", ")
+
+	case "template":
+		tpl, _ := cond["value_template"].(string)
+		if tpl == "" {
+			return conditionError, "missing value_template"
+		}
+		return renderCondition(tpl, render)
+
+	case "state", "numeric_state":
+		tpl, err := conditionTemplate(cond)
+		if err != nil {
+			return conditionUnsupported, err.Error()
+		}
+		return renderCondition(tpl, render)
+
+	default:
+		return conditionUnsupported, fmt.Sprintf("%q conditions cannot be evaluated outside an automation run", kind)
+	}
+}
+
+// renderCondition renders tpl and interprets the result as a boolean, the
+// way Home Assistant does for template conditions.
+func renderCondition(tpl string, render templateRenderer) (string, string) {
+	out, err := render(tpl)
+	if err != nil {
+		return conditionError, err.Error()
+	}
+
+	out = strings.TrimSpace(out)
+	if resultAsBoolean(out) {
+		return conditionPass, tpl
+	}
+	return conditionFail, fmt.Sprintf("%s rendered %q", tpl, out)
+}
+
+// resultAsBoolean mirrors Home Assistant's conversion of a rendered template
+// to a condition result.
+func resultAsBoolean(s string) bool {
+	switch strings.ToLower(s) {
+	case "1", "true", "yes", "on", "enable":
+		return true
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f != 0
+	}
+	return false
+}
+
+// combineResults combines nested results for and/or/not. A result that could
+// not be determined makes the combination undetermined unless the others
+// already decide it.
+func combineResults(kind string, results []string) string {
+	var passed, failed, other int
+	for _, r := range results {
+		switch r {
+		case conditionPass:
+			passed++
+		case conditionFail:
+			failed++
+		case conditionDisabled:
+		default:
+			other++
+		}
+	}
+
+	switch kind {
+	case "and":
+		if failed > 0 {
+			return conditionFail
+		}
+		if other > 0 {
+			return conditionUnsupported
+		}
+		return conditionPass
+	case "or":
+		if passed > 0 {
+			return conditionPass
+		}
+		if other > 0 {
+			return conditionUnsupported
+		}
+		return conditionFail
+	default: // not: passes when none of the nested conditions pass
+		if passed > 0 {
+			return conditionFail
+		}
+		if other > 0 {
+			return conditionUnsupported
+		}
+		return conditionPass
+	}
+}
+
+// conditionTemplate translates a state or numeric_state condition into an
+// equivalent template.
+func conditionTemplate(cond map[string]interface{}) (string, error) {
+	entities := stringList(cond["entity_id"])
+	if len(entities) == 0 {
+		return "", fmt.Errorf("missing entity_id")
+	}
+	attr, _ := cond["attribute"].(string)
+
+	join := " and "
+	if match, _ := cond["match"].(string); match == "any" {
+		join = " or "
+	}
+
+	var parts []string
+	switch cond["condition"] {
+	case "state":
+		states := stringList(cond["state"])
+		if len(states) == 0 {
+			return "", fmt.Errorf("missing state")
+		}
+		if _, ok := cond["for"]; ok {
+			return "", fmt.Errorf("state conditions with \"for\" are not supported")
+		}
+		for _, entity := range entities {
+			var alternatives []string
+			for _, state := range states {
+				if attr != "" {
+					alternatives = append(alternatives, fmt.Sprintf("is_state_attr(%s, %s, %s)", jinjaString(entity), jinjaString(attr), jinjaString(state)))
+				} else {
+					alternatives = append(alternatives, fmt.Sprintf("is_state(%s, %s)", jinjaString(entity), jinjaString(state)))
+				}
+			}
+			parts = append(parts, "("+strings.Join(alternatives, " or ")+")")
+		}
+
+	case "numeric_state":
+		if _, ok := cond["value_template"]; ok {
+			return "", fmt.Errorf("numeric_state conditions with value_template are not supported")
+		}
+		above, hasAbove := cond["above"]
+		below, hasBelow := cond["below"]
+		if !hasAbove && !hasBelow {
+			return "", fmt.Errorf("missing above or below")
+		}
+		for _, entity := range entities {
+			value := fmt.Sprintf("(states(%s) | float(none))", jinjaString(entity))
+			if attr != "" {
+				value = fmt.Sprintf("(state_attr(%s, %s) | float(none))", jinjaString(entity), jinjaString(attr))
+			}
+			checks := []string{value + " is not none"}
+			if hasAbove {
+				checks = append(checks, value+" > "+numericBound(above))
+			}
+			if hasBelow {
+				checks = append(checks, value+" < "+numericBound(below))
+			}
+			parts = append(parts, "("+strings.Join(checks, " and ")+")")
+		}
+	}
+
+	return "{{ " + strings.Join(parts, join) + " }}", nil
+}
+
+// numericBound renders an above/below value: a number, or an entity ID whose
+// state holds the number.
+func numericBound(v interface{}) string {
+	if s, ok := v.(string); ok {
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return fmt.Sprintf("(states(%s) | float(0))", jinjaString(s))
+		}
+		return s
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// stringList returns v as a list of strings. A single value becomes a list
+// of one; numbers and booleans are formatted the way YAML would write them.
+func stringList(v interface{}) []string {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		var list []string
+		for _, item := range v {
+			list = append(list, stringList(item)...)
+		}
+		return list
+	case string:
+		return []string{v}
+	default:
+		return []string{fmt.Sprintf("%v", v)}
+	}
+}
+
+// jinjaString quotes s as a Jinja string literal.
+func jinjaString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConditionTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cond    map[string]interface{}
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "state",
+			cond: map[string]interface{}{"condition": "state", "entity_id": "light.hall", "state": "on"},
+			want: `{{ (is_state("light.hall", "on")) }}`,
+		},
+		{
+			name: "state with several entities and states",
+			cond: map[string]interface{}{"condition": "state", "entity_id": []interface{}{"light.a", "light.b"}, "state": []interface{}{"on", "dim"}},
+			want: `{{ (is_state("light.a", "on") or is_state("light.a", "dim")) and (is_state("light.b", "on") or is_state("light.b", "dim")) }}`,
+		},
+		{
+			name: "state attribute with match any",
+			cond: map[string]interface{}{"condition": "state", "entity_id": []interface{}{"climate.a", "climate.b"}, "attribute": "hvac_action", "state": "heating", "match": "any"},
+			want: `{{ (is_state_attr("climate.a", "hvac_action", "heating")) or (is_state_attr("climate.b", "hvac_action", "heating")) }}`,
+		},
+		{
+			name: "numeric_state",
+			cond: map[string]interface{}{"condition": "numeric_state", "entity_id": "sensor.temp", "above": float64(17), "below": "input_number.max_temp"},
+			want: `{{ ((states("sensor.temp") | float(none)) is not none and (states("sensor.temp") | float(none)) > 17 and (states("sensor.temp") | float(none)) < (states("input_number.max_temp") | float(0))) }}`,
+		},
+		{
+			name:    "state with for",
+			cond:    map[string]interface{}{"condition": "state", "entity_id": "light.hall", "state": "on", "for": "00:05:00"},
+			wantErr: true,
+		},
+		{
+			name:    "numeric_state without bounds",
+			cond:    map[string]interface{}{"condition": "numeric_state", "entity_id": "sensor.temp"},
+			wantErr: true,
+		},
+		{
+			name:    "missing entity",
+			cond:    map[string]interface{}{"condition": "state", "state": "on"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := conditionTemplate(tt.cond)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("conditionTemplate() = %q, expected error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("conditionTemplate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("conditionTemplate() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckConditions(t *testing.T) {
+	rendered := map[string]string{
+		"{{ is_state('sun.sun', 'below_horizon') }}": "True",
+		"{{ states('sensor.lux') | int < 10 }}":      "False",
+		`{{ (is_state("light.hall", "off")) }}`:      "True",
+	}
+	render := func(tpl string) (string, error) {
+		out, ok := rendered[tpl]
+		if !ok {
+			return "", errors.New("UndefinedError: 'trigger' is undefined")
+		}
+		return out, nil
+	}
+
+	conditions := []map[string]interface{}{
+		{"condition": "template", "value_template": "{{ is_state('sun.sun', 'below_horizon') }}"},
+		{"condition": "template", "value_template": "{{ states('sensor.lux') | int < 10 }}"},
+		{"condition": "state", "entity_id": "light.hall", "state": "off"},
+		{"condition": "time", "after": "22:00:00"},
+		{"condition": "template", "value_template": "{{ trigger.to_state.state == 'on' }}"},
+		{"condition": "or", "conditions": []interface{}{
+			map[string]interface{}{"condition": "time", "after": "22:00:00"},
+			map[string]interface{}{"condition": "state", "entity_id": "light.hall", "state": "off"},
+		}},
+		{"condition": "not", "conditions": []interface{}{
+			map[string]interface{}{"condition": "template", "value_template": "{{ is_state('sun.sun', 'below_horizon') }}"},
+		}},
+		{"condition": "template", "value_template": "{{ false }}", "enabled": false},
+	}
+
+	want := []string{conditionPass, conditionFail, conditionPass, conditionUnsupported, conditionError, conditionPass, conditionFail, conditionDisabled}
+
+	checks := checkConditions(conditions, render)
+	if len(checks) != len(want) {
+		t.Fatalf("len(checks) = %d, want %d", len(checks), len(want))
+	}
+	for i, c := range checks {
+		if c.Index != i {
+			t.Errorf("checks[%d].Index = %d", i, c.Index)
+		}
+		if c.Result != want[i] {
+			t.Errorf("checks[%d] (%s) = %q (%s), want %q", i, c.Condition, c.Result, c.Detail, want[i])
+		}
+	}
+
+	if got := combineAll(checks); got != conditionFail {
+		t.Errorf("combineAll() = %q, want %q", got, conditionFail)
+	}
+}
+
+func TestCombineResults(t *testing.T) {
+	tests := []struct {
+		kind    string
+		results []string
+		want    string
+	}{
+		{"and", []string{conditionPass, conditionPass}, conditionPass},
+		{"and", []string{conditionPass, conditionUnsupported}, conditionUnsupported},
+		{"and", []string{conditionFail, conditionUnsupported}, conditionFail},
+		{"and", []string{conditionPass, conditionDisabled}, conditionPass},
+		{"or", []string{conditionFail, conditionFail}, conditionFail},
+		{"or", []string{conditionFail, conditionError}, conditionUnsupported},
+		{"or", []string{conditionPass, conditionError}, conditionPass},
+		{"not", []string{conditionFail, conditionFail}, conditionPass},
+		{"not", []string{conditionFail, conditionPass}, conditionFail},
+	}
+
+	for _, tt := range tests {
+		if got := combineResults(tt.kind, tt.results); got != tt.want {
+			t.Errorf("combineResults(%q, %v) = %q, want %q", tt.kind, tt.results, got, tt.want)
+		}
+	}
+}
+
+func TestResultAsBoolean(t *testing.T) {
+	for _, s := range []string{"True", "true", "on", "yes", "1", "2.5"} {
+		if !resultAsBoolean(s) {
+			t.Errorf("resultAsBoolean(%q) = false, want true", s)
+		}
+	}
+	for _, s := range []string{"False", "off", "0", "", "unknown"} {
+		if resultAsBoolean(s) {
+			t.Errorf("resultAsBoolean(%q) = true, want false", s)
+		}
+	}
+}
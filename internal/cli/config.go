@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dorinclisu/hass-cli/internal/config"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show and change hass-cli settings",
+	Long: `Show and change the settings stored in the hass-cli config file.
+
+Keys:
+  server.url         Home Assistant URL
+  defaults.output    Default output format: human, table, json, tsv or csv
+  defaults.timeout   Default request timeout in seconds
+  defaults.entity    Entity used by 'state get' and 'value' when none is given
+
+Command-line flags and HASS_URL still take precedence over these settings.
+The access token is changed with 'hass-cli login'.
+
+Examples:
+  hass-cli config show
+  hass-cli config get defaults.timeout
+  hass-cli config set defaults.timeout 60
+  hass-cli config set defaults.output json`,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the configuration with the token redacted",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigShow,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a single setting",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting in the config file",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runConfigSet,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+}
+
+// configFilePath returns the config file in use: --config or the default.
+func configFilePath() string {
+	if configPath != "" {
+		return configPath
+	}
+	return config.DefaultConfigPath()
+}
+
+// loadConfigFile reads the config file without applying --url, --token or
+// environment overrides, so what is shown or saved is the file's content.
+func loadConfigFile() (*config.Config, error) {
+	return config.LoadFrom(configFilePath())
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	redacted := *cfg
+	if cfg.Server.Token != "" {
+		redacted.Server.Token = cfg.RedactedToken()
+	}
+
+	if jsonOutput {
+		return outputJSON(cmd.OutOrStdout(), map[string]interface{}{
+			"path": configFilePath(),
+			"server": map[string]interface{}{
+				"url":   redacted.Server.URL,
+				"token": redacted.Server.Token,
+			},
+			"defaults": map[string]interface{}{
+				"output":  redacted.Defaults.Output,
+				"timeout": redacted.Defaults.Timeout,
+				"entity":  redacted.Defaults.Entity,
+			},
+		})
+	}
+
+	data, err := yaml.Marshal(&redacted)
+	if err != nil {
+		return fmt.Errorf("failed to serialize config: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "# %s\n%s", configFilePath(), data)
+	return nil
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	value, err := cfg.Get(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), value)
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	key, value := args[0], args[1]
+
+	cfg, err := loadConfigFile()
+	if errors.Is(err, config.ErrNotConfigured) {
+		// Settings may be changed before logging in
+		cfg = &config.Config{Defaults: config.DefaultsConfig{Output: "human", Timeout: 30}}
+	} else if err != nil {
+		return err
+	}
+
+	if err := cfg.Set(key, value); err != nil {
+		return err
+	}
+	if err := cfg.SaveTo(configFilePath()); err != nil {
+		return err
+	}
+
+	saved, _ := cfg.Get(key)
+	printSuccess("Set %s to %s", key, saved)
+	return nil
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+	"github.com/spf13/cobra"
+)
+
+var configFlowsCmd = &cobra.Command{
+	Use:   "config-flows",
+	Short: "List integration config flows in progress",
+	Long: `List integration setup flows that are currently in progress.
+
+Flows stuck here are usually discovered integrations waiting for user input
+in the UI, or integrations whose setup step keeps failing.
+
+Examples:
+  hass-cli config-flows              # List flows in progress
+  hass-cli config-flows --json       # Output as JSON`,
+	Args: cobra.NoArgs,
+	RunE: runConfigFlows,
+}
+
+func init() {
+	rootCmd.AddCommand(configFlowsCmd)
+}
+
+func runConfigFlows(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	printInfo("Connecting to Home Assistant...")
+	client, err := newWSClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer client.Close()
+
+	printInfo("Fetching config flows...")
+	flows, err := client.GetConfigFlows()
+	if err != nil {
+		return fmt.Errorf("failed to get config flows: %w", err)
+	}
+
+	sort.Slice(flows, func(i, j int) bool {
+		if flows[i].Handler != flows[j].Handler {
+			return flows[i].Handler < flows[j].Handler
+		}
+		return flows[i].FlowID < flows[j].FlowID
+	})
+
+	return outputData(cmd.OutOrStdout(), flows, configFlowsTable(flows))
+}
+
+func configFlowsTable(flows []websocket.ConfigFlow) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "FLOW ID"}, {Header: "INTEGRATION"}, {Header: "STEP"}, {Header: "SOURCE"}, {Header: "TITLE"}},
+		Noun:    "flows",
+		Empty:   "No flows in progress",
+	}
+
+	for _, f := range flows {
+		title := ""
+		if name, ok := f.Context.TitlePlaceholders["name"]; ok {
+			title = fmt.Sprintf("%v", name)
+		}
+
+		t.addRow(
+			f.FlowID,
+			f.Handler,
+			f.StepID,
+			f.Context.Source,
+			title,
+		)
+	}
+
+	return t
+}
@@ -3,13 +3,11 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
-	"text/tabwriter"
-	"time"
 
-	"github.com/dorinclisu/hass-cli/internal/config"
 	"github.com/dorinclisu/hass-cli/internal/websocket"
 	"github.com/spf13/cobra"
 )
@@ -24,10 +22,33 @@ Displays device information including name, manufacturer, model, and area.
 Examples:
   hass-cli devices              # List all devices
   hass-cli devices --json       # Output as JSON
-  hass-cli devices -m philips   # Filter by manufacturer`,
+  hass-cli devices -m philips   # Filter by manufacturer
+  hass-cli devices --fields name,model,sw_version  # Pick table columns
+  hass-cli devices --limit 20                      # First 20 devices
+  hass-cli devices --label "Needs battery"         # Devices with a label`,
 	RunE: runDevices,
 }
 
+var devicesSearchCmd = &cobra.Command{
+	Use:   "search <query>...",
+	Short: "Find devices by name, manufacturer or model",
+	Long: `Find devices whose name, manufacturer, model or model ID contain the
+query, best matches first. Renamed devices are also found by the name the
+integration gave them.
+
+Each word of the query must appear in at least one of these fields, so a
+query can span several of them (e.g. "aqara motion"). Matches on the name
+rank above matches on the manufacturer or model, and whole fields or word
+beginnings above matches in the middle of a word.
+
+Examples:
+  hass-cli devices search aqara motion
+  hass-cli devices search hue bulb --json
+  hass-cli devices search SNZB-02`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runDevicesSearch,
+}
+
 var devicesInspectCmd = &cobra.Command{
 	Use:   "inspect <device_id>",
 	Short: "Show detailed information about a device",
@@ -36,9 +57,13 @@ var devicesInspectCmd = &cobra.Command{
 The device ID can be found by running 'hass-cli devices'.
 You can use a partial ID (prefix match) for convenience.
 
+With --entities, the registry entries of the entities the device provides
+are included as an "entities" array.
+
 Examples:
   hass-cli devices inspect 4ee3f48beb2fcdeee4f8195b8f1730da
-  hass-cli devices inspect 4ee3f48b    # Prefix match`,
+  hass-cli devices inspect 4ee3f48b    # Prefix match
+  hass-cli devices inspect 4ee3f48b --entities`,
 	Args: cobra.ExactArgs(1),
 	RunE: runDevicesInspect,
 }
@@ -98,28 +123,37 @@ Examples:
 }
 
 var devicesRenameCmd = &cobra.Command{
-	Use:   "rename <device_id> <new_name>",
+	Use:   "rename <device_id> [new_name]",
 	Short: "Rename a device",
 	Long: `Rename a device in the Home Assistant device registry.
 
 The device ID can be found by running 'hass-cli devices'.
 You can use a partial ID (prefix match) for convenience.
 
+The new name is stored as a user override; use --clear to drop it and go
+back to the name provided by the integration.
+
 Examples:
   hass-cli devices rename 95a3100700e6 "Spare - 2"
-  hass-cli devices rename 95a3 "Living Room Light"`,
-	Args: cobra.ExactArgs(2),
+  hass-cli devices rename 95a3 "Living Room Light"
+  hass-cli devices rename 95a3 --clear`,
+	Args: cobra.RangeArgs(1, 2),
 	RunE: runDevicesRename,
 }
 
 var (
 	deviceManufacturer string
 	deviceArea         string
+	deviceLabels       []string
+
+	deviceRenameClear     bool
+	deviceInspectEntities bool
 )
 
 func init() {
 	rootCmd.AddCommand(devicesCmd)
 	devicesCmd.AddCommand(devicesInspectCmd)
+	devicesCmd.AddCommand(devicesSearchCmd)
 	devicesCmd.AddCommand(devicesRemoveCmd)
 	devicesCmd.AddCommand(devicesDisableCmd)
 	devicesCmd.AddCommand(devicesEnableCmd)
@@ -127,6 +161,12 @@ func init() {
 
 	devicesCmd.Flags().StringVarP(&deviceManufacturer, "manufacturer", "m", "", "Filter by manufacturer (case-insensitive)")
 	devicesCmd.Flags().StringVarP(&deviceArea, "area", "a", "", "Filter by area ID")
+	devicesCmd.Flags().StringSliceVar(&deviceLabels, "label", nil, "Only show devices with this label ID or name (repeatable, any matches)")
+	addFieldsFlag(devicesCmd, websocket.Device{})
+	addPageFlags(devicesCmd)
+
+	devicesRenameCmd.Flags().BoolVar(&deviceRenameClear, "clear", false, "Remove the name override")
+	devicesInspectCmd.Flags().BoolVar(&deviceInspectEntities, "entities", false, "Include the entities the device provides")
 }
 
 func runDevices(cmd *cobra.Command, args []string) error {
@@ -136,19 +176,43 @@ func runDevices(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Create WebSocket client
-	printInfo("Connecting to Home Assistant...")
-	client, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
-	if err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
-	}
-	defer client.Close()
+	return withWSClient(cfg, func(client *websocket.Client) error {
+		devices, areaMap, err := fetchDevices(client)
+		if err != nil {
+			return err
+		}
+
+		labelIDs, err := fetchLabelIDs(client, deviceLabels)
+		if err != nil {
+			return err
+		}
+
+		// Filter devices
+		filtered := filterDevices(devices, areaMap, labelIDs)
+
+		// Sort by name
+		sort.Slice(filtered, func(i, j int) bool {
+			return strings.ToLower(filtered[i].DisplayName()) < strings.ToLower(filtered[j].DisplayName())
+		})
+
+		page, err := applyPage(filtered)
+		if err != nil {
+			return err
+		}
+
+		// Output
+		return outputData(cmd.OutOrStdout(), page, devicesTable(page, areaMap))
+	})
+}
 
+// fetchDevices gets all devices and a map from area ID to name for showing
+// their areas. Failing to get the areas only costs the names.
+func fetchDevices(client *websocket.Client) ([]websocket.Device, map[string]string, error) {
 	// Get devices
 	printInfo("Fetching devices...")
 	devices, err := client.GetDevices()
 	if err != nil {
-		return fmt.Errorf("failed to get devices: %w", err)
+		return nil, nil, fmt.Errorf("failed to get devices: %w", err)
 	}
 
 	// Get areas for resolving area names
@@ -164,24 +228,107 @@ func runDevices(cmd *cobra.Command, args []string) error {
 		areaMap[area.AreaID] = area.Name
 	}
 
-	// Filter devices
-	filtered := filterDevices(devices, areaMap)
+	return devices, areaMap, nil
+}
+
+func runDevicesSearch(cmd *cobra.Command, args []string) error {
+	query := strings.Join(args, " ")
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	return withWSClient(cfg, func(client *websocket.Client) error {
+		devices, areaMap, err := fetchDevices(client)
+		if err != nil {
+			return err
+		}
 
-	// Sort by name
-	sort.Slice(filtered, func(i, j int) bool {
-		return strings.ToLower(filtered[i].DisplayName()) < strings.ToLower(filtered[j].DisplayName())
+		matches := searchDevices(devices, query)
+		table := devicesTable(matches, areaMap)
+		table.Empty = fmt.Sprintf("No devices match %q", query)
+		return outputData(cmd.OutOrStdout(), matches, table)
 	})
+}
+
+// searchDevices returns the devices matching every word of query, best
+// match first and then by name.
+func searchDevices(devices []websocket.Device, query string) []websocket.Device {
+	terms := strings.Fields(strings.ToLower(query))
 
-	// Output
-	if jsonOutput {
-		return outputJSON(filtered)
+	type scored struct {
+		device websocket.Device
+		score  int
 	}
+	var matches []scored
+	for _, d := range devices {
+		if score := deviceScore(d, terms); score > 0 {
+			matches = append(matches, scored{d, score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return strings.ToLower(matches[i].device.DisplayName()) < strings.ToLower(matches[j].device.DisplayName())
+	})
 
-	return outputDevicesTable(filtered, areaMap)
+	result := make([]websocket.Device, len(matches))
+	for i, m := range matches {
+		result[i] = m.device
+	}
+	return result
 }
 
-func filterDevices(devices []websocket.Device, areaMap map[string]string) []websocket.Device {
-	if deviceManufacturer == "" && deviceArea == "" {
+// deviceScore rates how well d matches the lowercase search terms: zero if
+// any term is missing from all searched fields, otherwise the sum of each
+// term's best match. A match is worth 3 if it is the whole field, 2 if a
+// word of the field starts with it and 1 otherwise, doubled for the name.
+func deviceScore(d websocket.Device, terms []string) int {
+	deref := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return strings.ToLower(*s)
+	}
+	fields := []struct {
+		value  string
+		weight int
+	}{
+		{strings.ToLower(d.DisplayName()), 2},
+		{deref(d.Name), 2}, // the integration's name when renamed by the user
+		{deref(d.Manufacturer), 1},
+		{deref(d.Model), 1},
+		{deref(d.ModelID), 1},
+	}
+
+	total := 0
+	for _, term := range terms {
+		best := 0
+		for _, f := range fields {
+			score := 0
+			switch {
+			case f.value == term:
+				score = 3
+			case strings.HasPrefix(f.value, term) || strings.Contains(f.value, " "+term):
+				score = 2
+			case strings.Contains(f.value, term):
+				score = 1
+			}
+			best = max(best, score*f.weight)
+		}
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+	return total
+}
+
+func filterDevices(devices []websocket.Device, areaMap map[string]string, labelIDs []string) []websocket.Device {
+	if deviceManufacturer == "" && deviceArea == "" && len(labelIDs) == 0 {
 		return devices
 	}
 
@@ -210,22 +357,23 @@ func filterDevices(devices []websocket.Device, areaMap map[string]string) []webs
 			}
 		}
 
+		if !hasAnyLabel(d.Labels, labelIDs) {
+			continue
+		}
+
 		filtered = append(filtered, d)
 	}
 
 	return filtered
 }
 
-func outputDevicesTable(devices []websocket.Device, areaMap map[string]string) error {
-	if len(devices) == 0 {
-		fmt.Println("No devices found")
-		return nil
+func devicesTable(devices []websocket.Device, areaMap map[string]string) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "ID"}, {Header: "NAME", Width: 35}, {Header: "MANUFACTURER", Width: 18}, {Header: "MODEL", Width: 18}, {Header: "AREA"}},
+		Noun:    "devices",
+		Empty:   "No devices found",
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "ID\tNAME\tMANUFACTURER\tMODEL\tAREA")
-	fmt.Fprintln(w, "--\t----\t------------\t-----\t----")
-
 	for _, d := range devices {
 		area := ""
 		if d.AreaID != nil {
@@ -236,39 +384,31 @@ func outputDevicesTable(devices []websocket.Device, areaMap map[string]string) e
 			}
 		}
 
-		name := d.DisplayName()
-		if len(name) > 35 {
-			name = name[:32] + "..."
-		}
-
-		manufacturer := d.DisplayManufacturer()
-		if len(manufacturer) > 18 {
-			manufacturer = manufacturer[:15] + "..."
-		}
-
-		model := d.DisplayModel()
-		if len(model) > 18 {
-			model = model[:15] + "..."
-		}
-
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+		t.addRow(
 			d.ID,
-			name,
-			manufacturer,
-			model,
+			d.DisplayName(),
+			d.DisplayManufacturer(),
+			d.DisplayModel(),
 			area,
 		)
 	}
 
-	w.Flush()
-	fmt.Printf("\nTotal: %d devices\n", len(devices))
-
-	return nil
+	return t
 }
 
-func outputJSON(data interface{}) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
+func outputJSON(out io.Writer, data interface{}) error {
+	if redactOutput {
+		redacted, err := redactData(data)
+		if err != nil {
+			return err
+		}
+		data = redacted
+	}
+
+	encoder := json.NewEncoder(out)
+	if !compactJSON {
+		encoder.SetIndent("", "  ")
+	}
 	return encoder.Encode(data)
 }
 
@@ -281,53 +421,48 @@ func runDevicesInspect(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Create WebSocket client
-	printInfo("Connecting to Home Assistant...")
-	client, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
-	if err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
-	}
-	defer client.Close()
-
-	// Get devices
-	printInfo("Fetching devices...")
-	devices, err := client.GetDevices()
-	if err != nil {
-		return fmt.Errorf("failed to get devices: %w", err)
-	}
-
-	// Find device by ID (exact or prefix match)
-	var found *websocket.Device
-	var matches []websocket.Device
-
-	for i := range devices {
-		if devices[i].ID == deviceID {
-			// Exact match
-			found = &devices[i]
-			break
+	return withWSClient(cfg, func(client *websocket.Client) error {
+		found, err := resolveDevice(client, deviceID)
+		if err != nil {
+			return err
 		}
-		if strings.HasPrefix(devices[i].ID, deviceID) {
-			matches = append(matches, devices[i])
+
+		if !deviceInspectEntities {
+			// Output the device as formatted JSON
+			return outputJSON(cmd.OutOrStdout(), found)
 		}
-	}
 
-	// If no exact match, check prefix matches
-	if found == nil {
-		if len(matches) == 0 {
-			return fmt.Errorf("no device found with ID: %s", deviceID)
+		printInfo("Fetching entities...")
+		entities, err := client.GetEntities()
+		if err != nil {
+			return fmt.Errorf("failed to get entities: %w", err)
 		}
-		if len(matches) > 1 {
-			fmt.Fprintf(os.Stderr, "Multiple devices match '%s':\n", deviceID)
-			for _, d := range matches {
-				fmt.Fprintf(os.Stderr, "  %s  %s\n", d.ID, d.DisplayName())
-			}
-			return fmt.Errorf("please provide a more specific ID")
+
+		return outputJSON(cmd.OutOrStdout(), DeviceWithEntities{
+			Device:   *found,
+			Entities: deviceEntities(entities, found.ID),
+		})
+	})
+}
+
+// DeviceWithEntities is a device together with the entities it provides,
+// as output by devices inspect --entities.
+type DeviceWithEntities struct {
+	websocket.Device
+	Entities []websocket.Entity `json:"entities"`
+}
+
+// deviceEntities returns the entities that belong to deviceID, sorted by
+// entity ID. The result is never nil so it is output as an empty array.
+func deviceEntities(entities []websocket.Entity, deviceID string) []websocket.Entity {
+	result := []websocket.Entity{}
+	for _, e := range entities {
+		if e.DeviceID != nil && *e.DeviceID == deviceID {
+			result = append(result, e)
 		}
-		found = &matches[0]
 	}
-
-	// Output the device as formatted JSON
-	return outputJSON(found)
+	sort.Slice(result, func(i, j int) bool { return result[i].EntityID < result[j].EntityID })
+	return result
 }
 
 func runDevicesRemove(cmd *cobra.Command, args []string) error {
@@ -339,69 +474,33 @@ func runDevicesRemove(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Create WebSocket client
-	printInfo("Connecting to Home Assistant...")
-	client, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
-	if err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
-	}
-	defer client.Close()
-
-	// Get devices to resolve partial ID and show name
-	printInfo("Fetching devices...")
-	devices, err := client.GetDevices()
-	if err != nil {
-		return fmt.Errorf("failed to get devices: %w", err)
-	}
-
-	// Find device by ID (exact or prefix match)
-	var found *websocket.Device
-	var matches []websocket.Device
-
-	for i := range devices {
-		if devices[i].ID == deviceID {
-			found = &devices[i]
-			break
-		}
-		if strings.HasPrefix(devices[i].ID, deviceID) {
-			matches = append(matches, devices[i])
+	return withWSClient(cfg, func(client *websocket.Client) error {
+		found, err := resolveDevice(client, deviceID)
+		if err != nil {
+			return err
 		}
-	}
 
-	if found == nil {
-		if len(matches) == 0 {
-			return fmt.Errorf("no device found with ID: %s", deviceID)
+		// Check if device has config entries
+		if len(found.ConfigEntries) == 0 {
+			return fmt.Errorf("device has no config entries - it may already be orphaned or managed differently")
 		}
-		if len(matches) > 1 {
-			fmt.Fprintf(os.Stderr, "Multiple devices match '%s':\n", deviceID)
-			for _, d := range matches {
-				fmt.Fprintf(os.Stderr, "  %s  %s\n", d.ID, d.DisplayName())
-			}
-			return fmt.Errorf("please provide a more specific ID")
-		}
-		found = &matches[0]
-	}
 
-	// Check if device has config entries
-	if len(found.ConfigEntries) == 0 {
-		return fmt.Errorf("device has no config entries - it may already be orphaned or managed differently")
-	}
-
-	// Remove all config entries from the device
-	printInfo("Removing device %s (%s)...", found.ID, found.DisplayName())
-	for _, configEntryID := range found.ConfigEntries {
-		printInfo("  Removing config entry %s...", configEntryID)
-		if err := client.RemoveConfigEntryFromDevice(found.ID, configEntryID); err != nil {
-			errStr := err.Error()
-			if strings.Contains(errStr, "does not support device removal") {
-				return fmt.Errorf("integration does not support device removal via API - use the Home Assistant UI or remove the integration")
+		// Remove all config entries from the device
+		printInfo("Removing device %s (%s)...", found.ID, found.DisplayName())
+		for _, configEntryID := range found.ConfigEntries {
+			printInfo("  Removing config entry %s...", configEntryID)
+			if err := client.RemoveConfigEntryFromDevice(found.ID, configEntryID); err != nil {
+				errStr := err.Error()
+				if strings.Contains(errStr, "does not support device removal") {
+					return fmt.Errorf("integration does not support device removal via API - use the Home Assistant UI or remove the integration")
+				}
+				return fmt.Errorf("failed to remove config entry %s: %w", configEntryID, err)
 			}
-			return fmt.Errorf("failed to remove config entry %s: %w", configEntryID, err)
 		}
-	}
 
-	fmt.Printf("Device removed: %s (%s)\n", found.ID, found.DisplayName())
-	return nil
+		printSuccess("Device removed: %s (%s)", found.ID, found.DisplayName())
+		return nil
+	})
 }
 
 func runDevicesDisable(cmd *cobra.Command, args []string) error {
@@ -418,171 +517,110 @@ func setDeviceDisabled(deviceID string, disable bool) error {
 		return err
 	}
 
-	printInfo("Connecting to Home Assistant...")
-	client, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
-	if err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
-	}
-	defer client.Close()
-
-	printInfo("Fetching devices...")
-	devices, err := client.GetDevices()
-	if err != nil {
-		return fmt.Errorf("failed to get devices: %w", err)
-	}
-
-	// Find device by ID (exact or prefix match)
-	var found *websocket.Device
-	var matches []websocket.Device
-
-	for i := range devices {
-		if devices[i].ID == deviceID {
-			found = &devices[i]
-			break
-		}
-		if strings.HasPrefix(devices[i].ID, deviceID) {
-			matches = append(matches, devices[i])
+	return withWSClient(cfg, func(client *websocket.Client) error {
+		found, err := resolveDevice(client, deviceID)
+		if err != nil {
+			return err
 		}
-	}
 
-	if found == nil {
-		if len(matches) == 0 {
-			return fmt.Errorf("no device found with ID: %s", deviceID)
-		}
-		if len(matches) > 1 {
-			fmt.Fprintf(os.Stderr, "Multiple devices match '%s':\n", deviceID)
-			for _, d := range matches {
-				fmt.Fprintf(os.Stderr, "  %s  %s\n", d.ID, d.DisplayName())
+		var device *websocket.Device
+		if disable {
+			printInfo("Disabling device %s (%s)...", found.ID, found.DisplayName())
+			device, err = client.DisableDevice(found.ID)
+			if err != nil {
+				return fmt.Errorf("failed to disable device: %w", err)
 			}
-			return fmt.Errorf("please provide a more specific ID")
-		}
-		found = &matches[0]
-	}
-
-	var device *websocket.Device
-	if disable {
-		printInfo("Disabling device %s (%s)...", found.ID, found.DisplayName())
-		device, err = client.DisableDevice(found.ID)
-		if err != nil {
-			return fmt.Errorf("failed to disable device: %w", err)
-		}
-		fmt.Printf("Device disabled: %s (%s)\n", device.ID, device.DisplayName())
-	} else {
-		printInfo("Enabling device %s (%s)...", found.ID, found.DisplayName())
-		device, err = client.EnableDevice(found.ID)
-		if err != nil {
-			return fmt.Errorf("failed to enable device: %w", err)
+			printSuccess("Device disabled: %s (%s)", device.ID, device.DisplayName())
+		} else {
+			printInfo("Enabling device %s (%s)...", found.ID, found.DisplayName())
+			device, err = client.EnableDevice(found.ID)
+			if err != nil {
+				return fmt.Errorf("failed to enable device: %w", err)
+			}
+			printSuccess("Device enabled: %s (%s)", device.ID, device.DisplayName())
 		}
-		fmt.Printf("Device enabled: %s (%s)\n", device.ID, device.DisplayName())
-	}
 
-	return nil
+		return nil
+	})
 }
 
 func runDevicesRename(cmd *cobra.Command, args []string) error {
 	deviceID := args[0]
-	newName := args[1]
+
+	var newName interface{}
+	switch {
+	case deviceRenameClear && len(args) > 1:
+		return fmt.Errorf("cannot combine <new_name> with --clear")
+	case deviceRenameClear:
+		newName = nil
+	case len(args) > 1 && strings.TrimSpace(args[1]) != "":
+		newName = args[1]
+	default:
+		return fmt.Errorf("specify <new_name> or --clear")
+	}
 
 	cfg, err := loadConfig()
 	if err != nil {
 		return err
 	}
 
-	printInfo("Connecting to Home Assistant...")
-	client, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
-	if err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
-	}
-	defer client.Close()
+	return withWSClient(cfg, func(client *websocket.Client) error {
+		found, err := resolveDevice(client, deviceID)
+		if err != nil {
+			return err
+		}
+		oldName := found.DisplayName()
+
+		device, err := client.UpdateDevice(found.ID, map[string]interface{}{
+			"name_by_user": newName,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to rename device: %w", err)
+		}
 
+		if deviceRenameClear {
+			printSuccess("Cleared name override for device %s: %s -> %s", device.ID, oldName, device.DisplayName())
+		} else {
+			printSuccess("Renamed device %s: %s -> %s", device.ID, oldName, device.DisplayName())
+		}
+		return nil
+	})
+}
+
+// resolveDevice fetches the device registry and finds the device with the
+// given ID or unique ID prefix.
+func resolveDevice(client *websocket.Client, deviceID string) (*websocket.Device, error) {
 	printInfo("Fetching devices...")
 	devices, err := client.GetDevices()
 	if err != nil {
-		return fmt.Errorf("failed to get devices: %w", err)
+		return nil, fmt.Errorf("failed to get devices: %w", err)
 	}
+	return matchDevice(devices, deviceID)
+}
 
-	// Find device by ID (exact or prefix match)
-	var found *websocket.Device
+// matchDevice finds a device by exact ID or, failing that, by a prefix that
+// matches exactly one device. Ambiguous prefixes list the candidates on
+// stderr.
+func matchDevice(devices []websocket.Device, deviceID string) (*websocket.Device, error) {
 	var matches []websocket.Device
-
 	for i := range devices {
 		if devices[i].ID == deviceID {
-			found = &devices[i]
-			break
+			return &devices[i], nil
 		}
 		if strings.HasPrefix(devices[i].ID, deviceID) {
 			matches = append(matches, devices[i])
 		}
 	}
 
-	if found == nil {
-		if len(matches) == 0 {
-			return fmt.Errorf("no device found with ID: %s", deviceID)
-		}
-		if len(matches) > 1 {
-			fmt.Fprintf(os.Stderr, "Multiple devices match '%s':\n", deviceID)
-			for _, d := range matches {
-				fmt.Fprintf(os.Stderr, "  %s  %s\n", d.ID, d.DisplayName())
-			}
-			return fmt.Errorf("please provide a more specific ID")
-		}
-		found = &matches[0]
-	}
-
-	device, err := client.UpdateDevice(found.ID, map[string]interface{}{
-		"name_by_user": newName,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to rename device: %w", err)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no device found with ID: %s", deviceID)
 	}
-
-	fmt.Printf("Renamed device %s to: %s\n", device.ID, newName)
-	return nil
-}
-
-// loadConfig loads the configuration, respecting command-line overrides.
-func loadConfig() (*config.Config, error) {
-	var cfg *config.Config
-	var err error
-
-	// Load from file
-	if configPath != "" {
-		cfg, err = config.LoadFrom(configPath)
-	} else {
-		cfg, err = config.Load()
-	}
-
-	// If config doesn't exist but URL and token are provided via flags, create a temporary config
-	if err == config.ErrNotConfigured && serverURL != "" && token != "" {
-		cfg = &config.Config{
-			Server: config.ServerConfig{
-				URL:   serverURL,
-				Token: token,
-			},
-			Defaults: config.DefaultsConfig{
-				Output:  "human",
-				Timeout: timeout,
-			},
+	if len(matches) > 1 {
+		fmt.Fprintf(os.Stderr, "Multiple devices match '%s':\n", deviceID)
+		for _, d := range matches {
+			fmt.Fprintf(os.Stderr, "  %s  %s\n", d.ID, d.DisplayName())
 		}
-		err = nil
-	}
-
-	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("please provide a more specific ID")
 	}
-
-	// Apply command-line overrides
-	if serverURL != "" {
-		cfg.Server.URL = serverURL
-	}
-	if token != "" {
-		cfg.Server.Token = token
-	}
-
-	// Validate
-	if !cfg.IsConfigured() {
-		return nil, config.ErrNotConfigured
-	}
-
-	return cfg, nil
+	return &matches[0], nil
 }
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+)
+
+func TestOutputDevicesTable(t *testing.T) {
+	devices := []websocket.Device{
+		{
+			ID:           "abc123",
+			Name:         strPtr("Hue Bulb"),
+			NameByUser:   strPtr("Desk Lamp"),
+			Manufacturer: strPtr("Signify Netherlands B.V."),
+			Model:        strPtr("LCT015"),
+			AreaID:       strPtr("office"),
+		},
+		{
+			ID:     "def456",
+			AreaID: strPtr("garage"),
+		},
+	}
+	areaMap := map[string]string{"office": "Office"}
+
+	var buf bytes.Buffer
+	if err := writeTable(&buf, devicesTable(devices, areaMap)); err != nil {
+		t.Fatalf("writeTable() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"MANUFACTURER",
+		"Desk Lamp",
+		"Signify Netherl...",
+		"LCT015",
+		"Office",
+		"Unknown",
+		"garage", // falls back to area ID when the area is unknown
+		"Total: 2 devices",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Hue Bulb") {
+		t.Errorf("output shows default name instead of user name:\n%s", out)
+	}
+}
+
+func TestOutputJSON_Compact(t *testing.T) {
+	old := compactJSON
+	defer func() { compactJSON = old }()
+
+	data := map[string]int{"a": 1}
+
+	var buf bytes.Buffer
+	compactJSON = false
+	outputJSON(&buf, data)
+	if got := buf.String(); got != "{\n  \"a\": 1\n}\n" {
+		t.Errorf("pretty output = %q", got)
+	}
+
+	buf.Reset()
+	compactJSON = true
+	outputJSON(&buf, data)
+	if got := buf.String(); got != "{\"a\":1}\n" {
+		t.Errorf("compact output = %q", got)
+	}
+}
+
+func TestMatchDevice(t *testing.T) {
+	devices := []websocket.Device{
+		{ID: "95a3100700e6"},
+		{ID: "95a3"},
+		{ID: "b71c2e"},
+		{ID: "b71d90"},
+	}
+
+	tests := []struct {
+		id      string
+		want    string
+		wantErr bool
+	}{
+		{id: "95a3", want: "95a3"},          // exact match wins over prefix matches
+		{id: "95a31", want: "95a3100700e6"}, // unique prefix
+		{id: "b71", wantErr: true},          // ambiguous prefix
+		{id: "ffff", wantErr: true},         // no match
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			got, err := matchDevice(devices, tt.id)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("matchDevice(%q) = %s, expected error", tt.id, got.ID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("matchDevice(%q) error = %v", tt.id, err)
+			}
+			if got.ID != tt.want {
+				t.Errorf("matchDevice(%q) = %s, want %s", tt.id, got.ID, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeviceEntities(t *testing.T) {
+	entities := []websocket.Entity{
+		{EntityID: "sensor.b", DeviceID: strPtr("dev1")},
+		{EntityID: "sensor.other", DeviceID: strPtr("dev2")},
+		{EntityID: "sensor.a", DeviceID: strPtr("dev1")},
+		{EntityID: "sensor.loose"},
+	}
+
+	got := deviceEntities(entities, "dev1")
+	if len(got) != 2 || got[0].EntityID != "sensor.a" || got[1].EntityID != "sensor.b" {
+		t.Errorf("deviceEntities(dev1) = %+v, want sensor.a, sensor.b", got)
+	}
+
+	var buf bytes.Buffer
+	if err := outputJSON(&buf, DeviceWithEntities{Device: websocket.Device{ID: "dev3"}, Entities: deviceEntities(entities, "dev3")}); err != nil {
+		t.Fatalf("outputJSON() error = %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, `"id": "dev3"`) || !strings.Contains(out, `"entities": []`) {
+		t.Errorf("output should flatten the device and include an empty entities array:\n%s", out)
+	}
+}
+
+func TestSearchDevices(t *testing.T) {
+	devices := []websocket.Device{
+		{ID: "d1", Name: strPtr("Hallway Motion Sensor"), Manufacturer: strPtr("Aqara"), Model: strPtr("RTCGQ11LM")},
+		{ID: "d2", Name: strPtr("Kitchen Light"), Manufacturer: strPtr("Signify"), Model: strPtr("Hue white bulb"), ModelID: strPtr("LWB010")},
+		{ID: "d3", Name: strPtr("Aqara Hub"), Manufacturer: strPtr("Aqara"), Model: strPtr("M2")},
+		{ID: "d4", Name: strPtr("Garage Door"), Manufacturer: strPtr("Promotional Devices")},
+		{ID: "d5", NameByUser: strPtr("Desk Lamp"), Name: strPtr("Hue bulb"), Manufacturer: strPtr("Signify"), ModelID: strPtr("LWB010")},
+	}
+
+	ids := func(list []websocket.Device) string {
+		var out []string
+		for _, d := range list {
+			out = append(out, d.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		// The hub has "aqara" in its name; the sensor only as manufacturer
+		{"aqara", "d3,d1"},
+		// Every word must match; the sensor matches both across fields
+		{"aqara motion", "d1"},
+		// A word-start match ranks above one in the middle of a word
+		{"motion", "d1,d4"},
+		{"lwb010", "d5,d2"},
+		// d5 was renamed, but its original name still matches
+		{"HUE BULB", "d5,d2"},
+		{"zigbee", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := ids(searchDevices(devices, tt.query)); got != tt.want {
+				t.Errorf("searchDevices(%q) = %s, want %s", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	editDiff   bool
+	editDryRun bool
+	editYes    bool
+)
+
+// diffLine is one line of a line-based diff. Op is ' ' for unchanged lines,
+// '-' for lines only in the old text and '+' for lines only in the new text.
+type diffLine struct {
+	Op   byte
+	Text string
+}
+
+// diffLines computes a line diff of a and b from their longest common
+// subsequence.
+func diffLines(a, b []string) []diffLine {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, diffLine{'+', b[j]})
+	}
+
+	return lines
+}
+
+// configDiff diffs the indented JSON representations of two configs.
+func configDiff(before, after interface{}) ([]diffLine, error) {
+	a, err := json.MarshalIndent(before, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize current config: %w", err)
+	}
+	b, err := json.MarshalIndent(after, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize updated config: %w", err)
+	}
+
+	return diffLines(strings.Split(string(a), "\n"), strings.Split(string(b), "\n")), nil
+}
+
+// hasChanges reports whether a diff contains any added or removed lines.
+func hasChanges(lines []diffLine) bool {
+	for _, l := range lines {
+		if l.Op != ' ' {
+			return true
+		}
+	}
+	return false
+}
+
+// diffContext is how many unchanged lines are shown around each change.
+const diffContext = 3
+
+// diffHunk is a run of changes with the unchanged lines around them. The
+// starts are 1-based line numbers in the old and new text.
+type diffHunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	Lines              []diffLine
+}
+
+// diffHunks groups the changes in lines into hunks with up to context
+// unchanged lines on either side, merging hunks whose context overlaps.
+func diffHunks(lines []diffLine, context int) []diffHunk {
+	// A line is shown when it is within context lines of a change
+	show := make([]bool, len(lines))
+	for i, l := range lines {
+		if l.Op == ' ' {
+			continue
+		}
+		for j := max(0, i-context); j <= min(len(lines)-1, i+context); j++ {
+			show[j] = true
+		}
+	}
+
+	var hunks []diffHunk
+	var cur *diffHunk
+	oldLine, newLine := 1, 1
+	for i, l := range lines {
+		if !show[i] {
+			cur = nil
+		} else {
+			if cur == nil {
+				hunks = append(hunks, diffHunk{OldStart: oldLine, NewStart: newLine})
+				cur = &hunks[len(hunks)-1]
+			}
+			cur.Lines = append(cur.Lines, l)
+			if l.Op != '+' {
+				cur.OldLines++
+			}
+			if l.Op != '-' {
+				cur.NewLines++
+			}
+		}
+
+		if l.Op != '+' {
+			oldLine++
+		}
+		if l.Op != '-' {
+			newLine++
+		}
+	}
+
+	return hunks
+}
+
+// hunkRange formats one side of a hunk header. As in diff -u, an empty range
+// starts at the line before it.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// writeDiff prints a unified diff: "-"/"+" markers and a few lines of
+// context around each change, with unchanged stretches left out.
+func writeDiff(out io.Writer, lines []diffLine) {
+	fmt.Fprintln(out, "--- current")
+	fmt.Fprintln(out, "+++ updated")
+	for _, h := range diffHunks(lines, diffContext) {
+		fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(h.OldStart, h.OldLines), hunkRange(h.NewStart, h.NewLines))
+		for _, l := range h.Lines {
+			fmt.Fprintf(out, "%c %s\n", l.Op, l.Text)
+		}
+	}
+}
+
+// addEditReviewFlags registers --diff, --dry-run and --yes on an edit command.
+func addEditReviewFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&editDiff, "diff", false, "Show the changes and ask for confirmation before saving")
+	cmd.Flags().BoolVar(&editDryRun, "dry-run", false, "Show the changes without saving")
+	cmd.Flags().BoolVarP(&editYes, "yes", "y", false, "Apply the changes shown by --diff without asking")
+}
+
+// reviewEdit shows the changes between before and after when --diff or
+// --dry-run is set, and reports whether the update should be saved.
+func reviewEdit(out io.Writer, before, after interface{}) (bool, error) {
+	if !editDiff && !editDryRun {
+		return true, nil
+	}
+
+	lines, err := configDiff(before, after)
+	if err != nil {
+		return false, err
+	}
+
+	if !hasChanges(lines) {
+		fmt.Fprintln(out, "No changes")
+		return false, nil
+	}
+
+	writeDiff(out, lines)
+
+	if editDryRun {
+		fmt.Fprintln(out, "\nDry run: no changes saved")
+		return false, nil
+	}
+
+	if editYes {
+		return true, nil
+	}
+
+	if !confirm("\nApply these changes?") {
+		fmt.Fprintln(out, "Aborted")
+		return false, nil
+	}
+
+	return true, nil
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	a := []string{"{", `  "alias": "Old",`, `  "mode": "single"`, "}"}
+	b := []string{"{", `  "alias": "New",`, `  "mode": "single",`, `  "icon": "mdi:star"`, "}"}
+
+	var buf bytes.Buffer
+	writeDiff(&buf, diffLines(a, b))
+
+	want := `--- current
++++ updated
+@@ -1,4 +1,5 @@
+  {
+-   "alias": "Old",
+-   "mode": "single"
++   "alias": "New",
++   "mode": "single",
++   "icon": "mdi:star"
+  }
+`
+	if got := buf.String(); got != want {
+		t.Errorf("diff =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDiffHunks(t *testing.T) {
+	var a []string
+	for i := 1; i <= 20; i++ {
+		a = append(a, fmt.Sprintf("line %d", i))
+	}
+	b := slices.Clone(a)
+	b[1] = "changed 2"                            // near the start
+	b = slices.Insert(b, 15, "inserted after 15") // far from the first change
+	b = slices.Delete(b, len(b)-1, len(b))        // last line removed
+
+	var buf bytes.Buffer
+	writeDiff(&buf, diffLines(a, b))
+
+	want := `--- current
++++ updated
+@@ -1,5 +1,5 @@
+  line 1
+- line 2
++ changed 2
+  line 3
+  line 4
+  line 5
+@@ -13,8 +13,8 @@
+  line 13
+  line 14
+  line 15
++ inserted after 15
+  line 16
+  line 17
+  line 18
+  line 19
+- line 20
+`
+	if got := buf.String(); got != want {
+		t.Errorf("diff =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDiffHunks_EmptySide(t *testing.T) {
+	hunks := diffHunks(diffLines(nil, []string{"a", "b"}), diffContext)
+	if len(hunks) != 1 {
+		t.Fatalf("got %d hunks, want 1", len(hunks))
+	}
+	h := hunks[0]
+	if got := "-" + hunkRange(h.OldStart, h.OldLines) + " +" + hunkRange(h.NewStart, h.NewLines); got != "-0,0 +1,2" {
+		t.Errorf("hunk header = %s, want -0,0 +1,2", got)
+	}
+}
+
+func TestConfigDiff_NoChanges(t *testing.T) {
+	config := map[string]interface{}{"alias": "Test", "sequence": []interface{}{"a", "b"}}
+
+	lines, err := configDiff(config, config)
+	if err != nil {
+		t.Fatalf("configDiff() error = %v", err)
+	}
+	if hasChanges(lines) {
+		t.Errorf("hasChanges() = true, want false")
+	}
+}
+
+func TestConfigDiff_Changes(t *testing.T) {
+	before := map[string]interface{}{"alias": "Test", "sequence": []interface{}{"a", "b"}}
+	after := map[string]interface{}{"alias": "Test", "sequence": []interface{}{"a", "c"}}
+
+	lines, err := configDiff(before, after)
+	if err != nil {
+		t.Fatalf("configDiff() error = %v", err)
+	}
+
+	var removed, added []string
+	for _, l := range lines {
+		switch l.Op {
+		case '-':
+			removed = append(removed, l.Text)
+		case '+':
+			added = append(added, l.Text)
+		}
+	}
+	if len(removed) != 1 || removed[0] != `    "b"` {
+		t.Errorf("removed = %q, want [%q]", removed, `    "b"`)
+	}
+	if len(added) != 1 || added[0] != `    "c"` {
+		t.Errorf("added = %q, want [%q]", added, `    "c"`)
+	}
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var entitiesDuplicatesCmd = &cobra.Command{
+	Use:   "duplicates",
+	Short: "Find entities with clashing names or IDs",
+	Long: `Report groups of entities that are easy to confuse:
+
+  name       entities sharing the same friendly name (case-insensitive)
+  entity_id  entity IDs that differ only by a numeric suffix, such as
+             light.kitchen and light.kitchen_2, which usually means an
+             entity was re-created after a device was re-paired
+
+Examples:
+  hass-cli entities duplicates
+  hass-cli entities duplicates --json`,
+	Args: cobra.NoArgs,
+	RunE: runEntitiesDuplicates,
+}
+
+func init() {
+	entitiesCmd.AddCommand(entitiesDuplicatesCmd)
+}
+
+// DuplicateGroup is a set of entities that share a name or base entity ID.
+type DuplicateGroup struct {
+	Kind      string   `json:"kind"` // "name" or "entity_id"
+	Match     string   `json:"match"`
+	EntityIDs []string `json:"entity_ids"`
+}
+
+// numericSuffix matches the "_2" style suffix Home Assistant appends to
+// entity IDs that would otherwise collide.
+var numericSuffix = regexp.MustCompile(`_\d+$`)
+
+// findDuplicates groups states by friendly name and by entity ID without a
+// numeric suffix, keeping only groups with more than one entity.
+func findDuplicates(states []api.State) []DuplicateGroup {
+	byName := make(map[string][]string)
+	names := make(map[string]string) // lowercased -> first spelling seen
+	byBase := make(map[string][]string)
+
+	for _, state := range states {
+		if name, ok := state.Attributes["friendly_name"].(string); ok && strings.TrimSpace(name) != "" {
+			key := strings.ToLower(strings.TrimSpace(name))
+			if _, seen := names[key]; !seen {
+				names[key] = strings.TrimSpace(name)
+			}
+			byName[key] = append(byName[key], state.EntityID)
+		}
+
+		base := numericSuffix.ReplaceAllString(state.EntityID, "")
+		byBase[base] = append(byBase[base], state.EntityID)
+	}
+
+	var groups []DuplicateGroup
+	for key, ids := range byName {
+		if len(ids) > 1 {
+			sort.Strings(ids)
+			groups = append(groups, DuplicateGroup{Kind: "name", Match: names[key], EntityIDs: ids})
+		}
+	}
+	for base, ids := range byBase {
+		if len(ids) > 1 {
+			sort.Strings(ids)
+			groups = append(groups, DuplicateGroup{Kind: "entity_id", Match: base, EntityIDs: ids})
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Kind != groups[j].Kind {
+			return groups[i].Kind > groups[j].Kind // names first
+		}
+		return strings.ToLower(groups[i].Match) < strings.ToLower(groups[j].Match)
+	})
+
+	return groups
+}
+
+func runEntitiesDuplicates(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	printInfo("Fetching states...")
+	states, err := client.GetStates()
+	if err != nil {
+		return fmt.Errorf("failed to get states: %w", err)
+	}
+
+	groups := findDuplicates(states)
+
+	return outputData(cmd.OutOrStdout(), groups, duplicatesTable(groups))
+}
+
+func duplicatesTable(groups []DuplicateGroup) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "KIND"}, {Header: "MATCH", Width: 35}, {Header: "ENTITIES"}},
+		Noun:    "duplicate groups",
+		Empty:   "No duplicates found",
+	}
+
+	for _, g := range groups {
+		t.addRow(
+			g.Kind,
+			g.Match,
+			strings.Join(g.EntityIDs, ", "),
+		)
+	}
+
+	return t
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	named := func(entityID, name string) api.State {
+		return api.State{EntityID: entityID, Attributes: map[string]interface{}{"friendly_name": name}}
+	}
+
+	states := []api.State{
+		named("light.kitchen", "Kitchen"),
+		named("light.kitchen_2", "Kitchen Light"),
+		named("switch.kitchen", "kitchen "),
+		named("sensor.temp", "Temperature"),
+		named("sensor.humidity", "Humidity"),
+		named("sensor.humidity_10", "Bathroom Humidity"),
+		{EntityID: "sun.sun"},
+	}
+
+	groups := findDuplicates(states)
+
+	var got []string
+	for _, g := range groups {
+		got = append(got, g.Kind+":"+g.Match+"="+strings.Join(g.EntityIDs, ","))
+	}
+	want := []string{
+		"name:Kitchen=light.kitchen,switch.kitchen",
+		"entity_id:light.kitchen=light.kitchen,light.kitchen_2",
+		"entity_id:sensor.humidity=sensor.humidity,sensor.humidity_10",
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("findDuplicates() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("findDuplicates()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
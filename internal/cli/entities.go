@@ -3,9 +3,11 @@ package cli
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
-	"text/tabwriter"
+	"sync"
 	"time"
 
 	"github.com/dorinclisu/hass-cli/internal/api"
@@ -20,11 +22,23 @@ var entitiesCmd = &cobra.Command{
 
 Displays entity information including ID, state, and area.
 
+Disabled and hidden entities are listed too. --disabled and --hidden list
+only those (either kind when both are given), and --enabled-only leaves out
+disabled entities.
+
 Examples:
   hass-cli entities              # List all entities
   hass-cli entities -d light     # Filter by domain
   hass-cli entities -a kitchen   # Filter by area
   hass-cli entities -D <device>  # Filter by device ID (prefix match)
+  hass-cli entities -d sensor --stale 7d  # Sensors unchanged for a week
+  hass-cli entities -g "*temperature*"    # Glob on entity ID or name
+  hass-cli entities --regex '^sensor\..*_(battery|rssi)$'
+  hass-cli entities -d sensor --attributes battery_level,temperature
+  hass-cli entities --fields entity_id,platform,area_name
+  hass-cli entities --limit 50 --offset 100   # Rows 101-150
+  hass-cli entities --disabled   # Audit disabled entities
+  hass-cli entities --label battery       # Entities with a label (ID or name)
   hass-cli entities --json       # Output as JSON`,
 	RunE: runEntities,
 }
@@ -42,14 +56,24 @@ Examples:
 }
 
 var entitiesRenameCmd = &cobra.Command{
-	Use:   "rename <entity_id> <new_name>",
-	Short: "Rename an entity",
+	Use:   "rename <entity_id> [new_name]",
+	Short: "Rename an entity or change its entity ID",
 	Long: `Rename an entity in the Home Assistant entity registry.
 
+Entities of modern integrations derive their friendly name from their device
+("<device name> <entity name>"). A name set here replaces that whole friendly
+name, so include the device name if you want to keep it. Use --clear to drop
+the override and go back to the integration-provided name.
+
+--new-id changes the entity ID itself; the domain cannot change. Automations,
+scripts and dashboards referring to the old ID are not updated.
+
 Examples:
   hass-cli entities rename light.old_bulb "Spare - 1"
-  hass-cli entities rename sensor.temp "Kitchen Temperature"`,
-	Args: cobra.ExactArgs(2),
+  hass-cli entities rename sensor.temp --name "Kitchen Temperature"
+  hass-cli entities rename sensor.temp --clear
+  hass-cli entities rename sensor.temp --new-id sensor.kitchen_temperature`,
+	Args: cobra.RangeArgs(1, 2),
 	RunE: runEntitiesRename,
 }
 
@@ -58,20 +82,96 @@ var entitiesSetAreaCmd = &cobra.Command{
 	Short: "Assign an entity to an area",
 	Long: `Assign an entity to a specific area in Home Assistant.
 
+The area can be given by ID or name. With --create, an area that does not
+exist yet is created first, using the argument as its name.
+
 Use an empty string or "none" to remove the area assignment.
 
 Examples:
   hass-cli entities set-area scene.living_room_cozy living_room
   hass-cli entities set-area light.kitchen kitchen
+  hass-cli entities set-area sensor.shed_temp "Garden Shed" --create
   hass-cli entities set-area sensor.temp none    # Remove area assignment`,
 	Args: cobra.ExactArgs(2),
 	RunE: runEntitiesSetArea,
 }
 
+var entitiesDisableCmd = &cobra.Command{
+	Use:   "disable [entity_id]",
+	Short: "Disable an entity, or all entities matching filters",
+	Long: `Disable an entity via the entity registry.
+
+Disabled entities are not added to Home Assistant and keep no state until
+they are enabled again.
+
+Without an entity ID, every enabled entity matching --domain and --platform
+is disabled after confirmation. Use --all to target every entity.
+
+Examples:
+  hass-cli entities disable sensor.unused_signal_strength
+  hass-cli entities disable --domain sensor --platform template
+  hass-cli entities disable --platform flaky_integration --yes`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runEntitiesDisableCommand(cmd, args, true)
+	},
+}
+
+var entitiesEnableCmd = &cobra.Command{
+	Use:   "enable [entity_id]",
+	Short: "Enable an entity, or all entities matching filters",
+	Long: `Enable (re-enable) a previously disabled entity.
+
+Without an entity ID, every disabled entity matching --domain and --platform
+is enabled after confirmation. Use --all to target every entity.
+
+Examples:
+  hass-cli entities enable sensor.unused_signal_strength
+  hass-cli entities enable --platform template`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runEntitiesDisableCommand(cmd, args, false)
+	},
+}
+
+var entitiesUnassignedCmd = &cobra.Command{
+	Use:   "unassigned",
+	Short: "List entities that are not in any area",
+	Long: `List entities that have no area, neither set on the entity itself nor
+inherited from its device. These are the entities that do not show up on
+area-based dashboards.
+
+Examples:
+  hass-cli entities unassigned              # All entities without an area
+  hass-cli entities unassigned -d sensor    # Only sensors
+  hass-cli entities unassigned --json       # Output as JSON`,
+	Args: cobra.NoArgs,
+	RunE: runEntitiesUnassigned,
+}
+
 var (
 	entityDomain string
 	entityArea   string
 	entityDevice string
+	entityStale  string
+	entityMatch  []string
+	entityRegex  string
+	entityAttrs  []string
+	entityLabels []string
+
+	entityDisabled    bool
+	entityHidden      bool
+	entityEnabledOnly bool
+
+	entityRenameClear bool
+	entityRenameName  string
+	entityRenameNewID string
+
+	entityAreaCreate bool
+
+	entityPlatform string
+	entityBulkAll  bool
+	entityBulkYes  bool
 )
 
 func init() {
@@ -79,16 +179,46 @@ func init() {
 	entitiesCmd.AddCommand(entitiesInspectCmd)
 	entitiesCmd.AddCommand(entitiesRenameCmd)
 	entitiesCmd.AddCommand(entitiesSetAreaCmd)
+	entitiesCmd.AddCommand(entitiesDisableCmd)
+	entitiesCmd.AddCommand(entitiesEnableCmd)
+	entitiesCmd.AddCommand(entitiesUnassignedCmd)
+
+	entitiesRenameCmd.Flags().BoolVar(&entityRenameClear, "clear", false, "Remove the name override")
+	entitiesRenameCmd.Flags().StringVar(&entityRenameName, "name", "", "New friendly name (same as <new_name>)")
+	entitiesRenameCmd.Flags().StringVar(&entityRenameNewID, "new-id", "", "New entity ID (must keep the same domain)")
 
 	entitiesCmd.Flags().StringVarP(&entityDomain, "domain", "d", "", "Filter by domain (e.g., light, switch, sensor)")
 	entitiesCmd.Flags().StringVarP(&entityArea, "area", "a", "", "Filter by area name")
 	entitiesCmd.Flags().StringVarP(&entityDevice, "device", "D", "", "Filter by device ID (prefix match supported)")
+	entitiesCmd.Flags().StringVar(&entityStale, "stale", "", "Only show entities whose state has not changed for this long (e.g., 7d, 12h)")
+	entitiesCmd.Flags().StringSliceVarP(&entityMatch, "match", "g", nil, "Only show entities whose ID or name matches this glob (repeatable)")
+	entitiesCmd.Flags().StringVar(&entityRegex, "regex", "", "Only show entities whose ID matches this regular expression")
+	entitiesCmd.Flags().StringSliceVar(&entityAttrs, "attributes", nil, "Add a column for each of these state attributes (comma-separated)")
+	entitiesCmd.Flags().BoolVar(&entityDisabled, "disabled", false, "Only show disabled entities")
+	entitiesCmd.Flags().BoolVar(&entityHidden, "hidden", false, "Only show hidden entities")
+	entitiesCmd.Flags().BoolVar(&entityEnabledOnly, "enabled-only", false, "Leave out disabled entities")
+	entitiesCmd.Flags().StringSliceVar(&entityLabels, "label", nil, "Only show entities with this label ID or name (repeatable, any matches)")
+	entitiesCmd.MarkFlagsMutuallyExclusive("disabled", "enabled-only")
+	addPageFlags(entitiesCmd)
+	addFieldsFlag(entitiesCmd, EntityWithState{})
+
+	entitiesUnassignedCmd.Flags().StringVarP(&entityDomain, "domain", "d", "", "Filter by domain (e.g., light, switch, sensor)")
+
+	entitiesSetAreaCmd.Flags().BoolVar(&entityAreaCreate, "create", false, "Create the area if it does not exist")
+
+	for _, cmd := range []*cobra.Command{entitiesDisableCmd, entitiesEnableCmd} {
+		cmd.Flags().StringVarP(&entityDomain, "domain", "d", "", "Only entities of this domain")
+		cmd.Flags().StringVar(&entityPlatform, "platform", "", "Only entities of this integration platform (e.g., template, mqtt)")
+		cmd.Flags().BoolVarP(&entityBulkYes, "yes", "y", false, "Do not ask for confirmation")
+		addAllFlag(cmd, &entityBulkAll)
+	}
 }
 
 // EntityWithState combines entity registry info with current state.
 type EntityWithState struct {
 	EntityID     string                 `json:"entity_id"`
 	State        string                 `json:"state"`
+	Unit         string                 `json:"unit_of_measurement,omitempty"`
 	AreaID       *string                `json:"area_id"`
 	AreaName     string                 `json:"area_name,omitempty"`
 	DeviceID     *string                `json:"device_id"`
@@ -102,177 +232,404 @@ type EntityWithState struct {
 }
 
 func runEntities(cmd *cobra.Command, args []string) error {
-	cfg, err := loadConfig()
-	if err != nil {
-		return err
+	var staleAfter time.Duration
+	if entityStale != "" {
+		d, err := parseAge(entityStale)
+		if err != nil {
+			return fmt.Errorf("invalid --stale value: %w", err)
+		}
+		staleAfter = d
 	}
 
-	// Get entity registry via WebSocket
-	printInfo("Connecting to Home Assistant...")
-	wsClient, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	patterns, err := parsePatterns(entityMatch)
 	if err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
+		return fmt.Errorf("invalid --match value: %w", err)
 	}
-	defer wsClient.Close()
 
-	printInfo("Fetching entities...")
-	entities, err := wsClient.GetEntities()
-	if err != nil {
-		return fmt.Errorf("failed to get entities: %w", err)
+	var idRegex *regexp.Regexp
+	if entityRegex != "" {
+		idRegex, err = regexp.Compile(entityRegex)
+		if err != nil {
+			return fmt.Errorf("invalid --regex value: %w", err)
+		}
 	}
 
-	// Get areas for name resolution
-	areas, err := wsClient.GetAreas()
+	cfg, err := loadConfig()
 	if err != nil {
-		printInfo("Warning: could not fetch areas: %v", err)
-		areas = []websocket.Area{}
+		return err
 	}
 
-	// Get devices for area resolution (entities may inherit area from device)
-	devices, err := wsClient.GetDevices()
-	if err != nil {
-		printInfo("Warning: could not fetch devices: %v", err)
-		devices = []websocket.Device{}
-	}
+	return withWSClient(cfg, func(wsClient *websocket.Client) error {
+		// Fetch current states via REST while the registries are fetched
+		// over the WebSocket connection
+		var (
+			states    []api.State
+			statesErr error
+			wg        sync.WaitGroup
+		)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			states, statesErr = newRESTClient(cfg).GetStates()
+		}()
+
+		printInfo("Fetching entities...")
+		registries := wsClient.GetRegistries()
+		wg.Wait()
+
+		if registries.EntitiesErr != nil {
+			return fmt.Errorf("failed to get entities: %w", registries.EntitiesErr)
+		}
+		entities := registries.Entities
 
-	// Build lookup maps
-	areaMap := make(map[string]string)
-	for _, area := range areas {
-		areaMap[area.AreaID] = area.Name
-	}
+		// Areas are used for name resolution
+		areas := registries.Areas
+		if registries.AreasErr != nil {
+			printInfo("Warning: could not fetch areas: %v", registries.AreasErr)
+			areas = []websocket.Area{}
+		}
 
-	deviceAreaMap := make(map[string]string)
-	for _, device := range devices {
-		if device.AreaID != nil {
-			deviceAreaMap[device.ID] = *device.AreaID
+		// Devices are used for area resolution (entities may inherit area from device)
+		devices := registries.Devices
+		if registries.DevicesErr != nil {
+			printInfo("Warning: could not fetch devices: %v", registries.DevicesErr)
+			devices = []websocket.Device{}
 		}
-	}
 
-	// Get current states via REST API
-	restClient := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
-	states, err := restClient.GetStates()
-	if err != nil {
-		printInfo("Warning: could not fetch states: %v", err)
-		states = []api.State{}
-	}
+		// Build lookup maps
+		areaMap := make(map[string]string)
+		for _, area := range areas {
+			areaMap[area.AreaID] = area.Name
+		}
 
-	stateMap := make(map[string]api.State)
-	for _, state := range states {
-		stateMap[state.EntityID] = state
-	}
+		deviceAreaMap := deviceAreas(devices)
 
-	// Combine entity registry with states
-	var combined []EntityWithState
-	for _, entity := range entities {
-		// Get area (from entity or inherited from device)
-		areaID := entity.AreaID
-		if areaID == nil && entity.DeviceID != nil {
-			if deviceArea, ok := deviceAreaMap[*entity.DeviceID]; ok {
-				areaID = &deviceArea
-			}
+		labelIDs, err := fetchLabelIDs(wsClient, entityLabels)
+		if err != nil {
+			return err
 		}
 
-		var areaName string
-		if areaID != nil {
-			areaName = areaMap[*areaID]
+		if statesErr != nil {
+			printInfo("Warning: could not fetch states: %v", statesErr)
+			states = []api.State{}
 		}
 
-		state := stateMap[entity.EntityID]
-
-		ews := EntityWithState{
-			EntityID:     entity.EntityID,
-			State:        state.State,
-			AreaID:       areaID,
-			AreaName:     areaName,
-			DeviceID:     entity.DeviceID,
-			Platform:     entity.Platform,
-			Name:         entity.Name,
-			OriginalName: entity.GetOriginalName(),
-			DisabledBy:   entity.DisabledBy,
-			HiddenBy:     entity.HiddenBy,
-			LastChanged:  state.LastChanged,
+		stateMap := make(map[string]api.State)
+		for _, state := range states {
+			stateMap[state.EntityID] = state
 		}
 
-		// Apply filters
-		if entityDomain != "" {
-			parts := strings.SplitN(entity.EntityID, ".", 2)
-			if len(parts) < 2 || !strings.EqualFold(parts[0], entityDomain) {
+		// Combine entity registry with states
+		now := time.Now()
+		var combined []EntityWithState
+		for _, entity := range entities {
+			areaID := entityAreaID(entity, deviceAreaMap)
+
+			var areaName string
+			if areaID != nil {
+				areaName = areaMap[*areaID]
+			}
+
+			state := stateMap[entity.EntityID]
+
+			ews := EntityWithState{
+				EntityID:     entity.EntityID,
+				State:        state.State,
+				Unit:         stateUnit(state.Attributes),
+				AreaID:       areaID,
+				AreaName:     areaName,
+				DeviceID:     entity.DeviceID,
+				Platform:     entity.Platform,
+				Name:         entity.Name,
+				OriginalName: entity.GetOriginalName(),
+				DisabledBy:   entity.DisabledBy,
+				HiddenBy:     entity.HiddenBy,
+				Attributes:   selectAttributes(state.Attributes, entityAttrs),
+				LastChanged:  state.LastChanged,
+			}
+
+			// Apply filters
+			if entityDomain != "" && !inDomain(entity.EntityID, entityDomain) {
 				continue
 			}
-		}
 
-		if entityArea != "" {
-			if areaName == "" {
+			if entityArea != "" {
+				if areaName == "" {
+					continue
+				}
+				if !strings.Contains(strings.ToLower(areaName), strings.ToLower(entityArea)) {
+					continue
+				}
+			}
+
+			if entityDevice != "" {
+				if entity.DeviceID == nil {
+					continue
+				}
+				// Support prefix match
+				if *entity.DeviceID != entityDevice && !strings.HasPrefix(*entity.DeviceID, entityDevice) {
+					continue
+				}
+			}
+
+			if entityStale != "" && !isStale(state.LastChanged, staleAfter, now) {
 				continue
 			}
-			if !strings.Contains(strings.ToLower(areaName), strings.ToLower(entityArea)) {
+
+			if !ews.matches(patterns, idRegex) {
 				continue
 			}
-		}
 
-		if entityDevice != "" {
-			if entity.DeviceID == nil {
+			if !ews.matchesRegistryStatus(entityDisabled, entityHidden, entityEnabledOnly) {
 				continue
 			}
-			// Support prefix match
-			if *entity.DeviceID != entityDevice && !strings.HasPrefix(*entity.DeviceID, entityDevice) {
+
+			if !hasAnyLabel(entity.Labels, labelIDs) {
 				continue
 			}
+
+			combined = append(combined, ews)
 		}
 
-		combined = append(combined, ews)
-	}
+		// Sort by entity_id
+		sort.Slice(combined, func(i, j int) bool {
+			return combined[i].EntityID < combined[j].EntityID
+		})
 
-	// Sort by entity_id
-	sort.Slice(combined, func(i, j int) bool {
-		return combined[i].EntityID < combined[j].EntityID
+		page, err := applyPage(combined)
+		if err != nil {
+			return err
+		}
+
+		return outputData(cmd.OutOrStdout(), page, entitiesTable(page, entityAttrs))
 	})
+}
 
-	if jsonOutput {
-		return outputJSON(combined)
+// deviceAreas maps device IDs to the area each device is assigned to.
+func deviceAreas(devices []websocket.Device) map[string]string {
+	areas := make(map[string]string)
+	for _, device := range devices {
+		if device.AreaID != nil {
+			areas[device.ID] = *device.AreaID
+		}
 	}
-
-	return outputEntitiesTable(combined)
+	return areas
 }
 
-func outputEntitiesTable(entities []EntityWithState) error {
-	if len(entities) == 0 {
-		fmt.Println("No entities found")
-		return nil
+// entityAreaID returns the entity's area: its own, or else the one inherited
+// from its device. It returns nil when the entity has no area either way.
+func entityAreaID(entity websocket.Entity, deviceAreaMap map[string]string) *string {
+	if entity.AreaID != nil {
+		return entity.AreaID
 	}
+	if entity.DeviceID != nil {
+		if deviceArea, ok := deviceAreaMap[*entity.DeviceID]; ok {
+			return &deviceArea
+		}
+	}
+	return nil
+}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "ENTITY ID\tSTATE\tNAME\tAREA")
-	fmt.Fprintln(w, "---------\t-----\t----\t----")
+// inDomain reports whether entityID belongs to domain (case-insensitive).
+func inDomain(entityID, domain string) bool {
+	entityDomain, _, ok := strings.Cut(entityID, ".")
+	return ok && strings.EqualFold(entityDomain, domain)
+}
 
-	for _, e := range entities {
+// matches reports whether the entity passes the --match and --regex filters.
+// Globs are tried against both the entity ID and the display name; the regex
+// only against the entity ID. Empty filters match everything.
+func (e EntityWithState) matches(patterns []string, idRegex *regexp.Regexp) bool {
+	if len(patterns) > 0 {
 		name := ""
 		if e.Name != nil && *e.Name != "" {
 			name = *e.Name
 		} else if e.OriginalName != nil && *e.OriginalName != "" {
 			name = *e.OriginalName
 		}
-		if len(name) > 30 {
-			name = name[:27] + "..."
+		if !matchesPatterns(e.EntityID, patterns) && (name == "" || !matchesPatterns(name, patterns)) {
+			return false
 		}
+	}
+
+	return idRegex == nil || idRegex.MatchString(e.EntityID)
+}
+
+// matchesRegistryStatus applies --disabled, --hidden and --enabled-only.
+// With --disabled or --hidden only entities of the requested kinds are kept
+// (either kind when both are set); --enabled-only drops disabled entities,
+// so combined with --hidden it keeps hidden entities that are enabled.
+func (e EntityWithState) matchesRegistryStatus(disabled, hidden, enabledOnly bool) bool {
+	isDisabled := e.DisabledBy != nil
+	isHidden := e.HiddenBy != nil
+
+	if enabledOnly && isDisabled {
+		return false
+	}
+	if disabled || hidden {
+		return (disabled && isDisabled) || (hidden && isHidden)
+	}
+	return true
+}
 
-		state := e.State
-		if len(state) > 15 {
-			state = state[:12] + "..."
+// parseAge parses a duration such as "90m", "12h" or "7d". In addition to
+// the units accepted by time.ParseDuration, "d" means days.
+func parseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
 		}
+		return time.Duration(n * float64(24*time.Hour)), nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+// isStale reports whether lastChanged is more than age before now.
+// Entities without a parseable last_changed (no state) are never stale.
+func isStale(lastChanged string, age time.Duration, now time.Time) bool {
+	t, err := time.Parse(time.RFC3339, lastChanged)
+	if err != nil {
+		return false
+	}
+	return now.Sub(t) > age
+}
+
+// selectAttributes returns the named attributes that are present in attrs,
+// or nil if there are none.
+func selectAttributes(attrs map[string]interface{}, names []string) map[string]interface{} {
+	var selected map[string]interface{}
+	for _, name := range names {
+		if v, ok := attrs[name]; ok {
+			if selected == nil {
+				selected = make(map[string]interface{})
+			}
+			selected[name] = v
+		}
+	}
+	return selected
+}
+
+// entitiesTable renders entities with an extra column for each of attrs.
+// Entities without an attribute get a blank cell.
+func entitiesTable(entities []EntityWithState, attrs []string) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "ENTITY ID"}, {Header: "STATE", Width: 15}, {Header: "UNIT"}, {Header: "NAME", Width: 30}, {Header: "AREA"}},
+		Noun:    "entities",
+		Empty:   "No entities found",
+	}
+	for _, attr := range attrs {
+		t.Columns = append(t.Columns, tableColumn{Header: strings.ToUpper(attr), Width: 20})
+	}
+
+	for _, e := range entities {
+		name := ""
+		if e.Name != nil && *e.Name != "" {
+			name = *e.Name
+		} else if e.OriginalName != nil && *e.OriginalName != "" {
+			name = *e.OriginalName
+		}
+
+		row := []string{
 			e.EntityID,
-			state,
+			e.State,
+			e.Unit,
 			name,
 			e.AreaName,
-		)
+		}
+		for _, attr := range attrs {
+			value := ""
+			if v, ok := e.Attributes[attr]; ok && v != nil {
+				value = attributeString(v)
+			}
+			row = append(row, value)
+		}
+		t.addRow(row...)
 	}
 
-	w.Flush()
-	fmt.Printf("\nTotal: %d entities\n", len(entities))
+	return t
+}
 
-	return nil
+// UnassignedEntity is an entity that has no area.
+type UnassignedEntity struct {
+	EntityID string  `json:"entity_id"`
+	Platform string  `json:"platform"`
+	DeviceID *string `json:"device_id"`
+}
+
+func runEntitiesUnassigned(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	return withWSClient(cfg, func(client *websocket.Client) error {
+		printInfo("Fetching entities...")
+		entities, err := client.GetEntities()
+		if err != nil {
+			return fmt.Errorf("failed to get entities: %w", err)
+		}
+
+		// Without devices every device-inherited area would be missed, so this
+		// is an error here rather than a warning as in runEntities.
+		devices, err := client.GetDevices()
+		if err != nil {
+			return fmt.Errorf("failed to get devices: %w", err)
+		}
+
+		unassigned := unassignedEntities(entities, devices, entityDomain)
+		return outputData(cmd.OutOrStdout(), unassigned, unassignedTable(unassigned))
+	})
+}
+
+// unassignedEntities returns the entities, optionally limited to one domain,
+// that have no area of their own and none inherited from a device, sorted
+// by entity ID.
+func unassignedEntities(entities []websocket.Entity, devices []websocket.Device, domain string) []UnassignedEntity {
+	deviceAreaMap := deviceAreas(devices)
+
+	var unassigned []UnassignedEntity
+	for _, entity := range entities {
+		if domain != "" && !inDomain(entity.EntityID, domain) {
+			continue
+		}
+		if entityAreaID(entity, deviceAreaMap) != nil {
+			continue
+		}
+		unassigned = append(unassigned, UnassignedEntity{
+			EntityID: entity.EntityID,
+			Platform: entity.Platform,
+			DeviceID: entity.DeviceID,
+		})
+	}
+
+	sort.Slice(unassigned, func(i, j int) bool {
+		return unassigned[i].EntityID < unassigned[j].EntityID
+	})
+
+	return unassigned
+}
+
+func unassignedTable(entities []UnassignedEntity) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "ENTITY ID"}, {Header: "PLATFORM"}},
+		Noun:    "entities without an area",
+		Empty:   "Every entity has an area",
+	}
+
+	for _, e := range entities {
+		t.addRow(e.EntityID, e.Platform)
+	}
+
+	return t
 }
 
 func runEntitiesInspect(cmd *cobra.Command, args []string) error {
@@ -283,7 +640,7 @@ func runEntitiesInspect(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	printInfo("Fetching entity state...")
 	state, err := client.GetState(entityID)
@@ -291,35 +648,124 @@ func runEntitiesInspect(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get entity: %w", err)
 	}
 
-	return outputJSON(state)
+	return outputJSON(cmd.OutOrStdout(), state)
 }
 
 func runEntitiesRename(cmd *cobra.Command, args []string) error {
 	entityID := args[0]
-	newName := args[1]
 
-	cfg, err := loadConfig()
+	name := entityRenameName
+	if len(args) > 1 {
+		if name != "" {
+			return fmt.Errorf("cannot combine <new_name> with --name")
+		}
+		name = args[1]
+	}
+
+	updates, err := entityRenameUpdates(entityID, name, entityRenameClear, entityRenameNewID)
 	if err != nil {
 		return err
 	}
 
-	wsClient, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	cfg, err := loadConfig()
 	if err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
+		return err
 	}
-	defer wsClient.Close()
 
-	updates := map[string]interface{}{
-		"name": newName,
+	return withWSClient(cfg, func(wsClient *websocket.Client) error {
+		entity, err := wsClient.GetEntity(entityID)
+		if err != nil {
+			return fmt.Errorf("failed to get entity: %w", err)
+		}
+
+		// Entities with has_entity_name are named "<device name> <entity name>"
+		// unless the registry name overrides it.
+		deviceName := ""
+		if _, renaming := updates["name"]; renaming && entity.HasEntityName && entity.DeviceID != nil {
+			deviceName = lookupDeviceName(wsClient, *entity.DeviceID)
+		}
+
+		updated, err := wsClient.UpdateEntity(entityID, updates)
+		if err != nil {
+			return fmt.Errorf("failed to rename entity: %w", err)
+		}
+
+		if _, ok := updates["new_entity_id"]; ok {
+			printSuccess("Entity ID updated: %s -> %s", entityID, updated.EntityID)
+		}
+
+		switch {
+		case entityRenameClear:
+			printSuccess("Cleared name override for %s", updated.EntityID)
+			if deviceName != "" {
+				if origName := entity.GetOriginalName(); origName != nil && *origName != "" {
+					printSuccess("Name is now derived from device: %s %s", deviceName, *origName)
+				} else {
+					printSuccess("Name is now derived from device: %s", deviceName)
+				}
+			}
+		case name != "":
+			printSuccess("Renamed %s to: %s", updated.EntityID, name)
+			if deviceName != "" {
+				fmt.Fprintf(os.Stderr, "Warning: %s takes its name from device %q; the new name replaces the full friendly name, so the device name is no longer prefixed\n", updated.EntityID, deviceName)
+			}
+		}
+
+		return nil
+	})
+}
+
+// entityRenameUpdates builds the entity registry update for a rename: a new
+// name (or nil to clear the override) and/or a new entity ID, which must
+// stay in the entity's domain.
+func entityRenameUpdates(entityID, name string, clear bool, newID string) (map[string]interface{}, error) {
+	if name == "" && !clear && newID == "" {
+		return nil, fmt.Errorf("requires <new_name>, --name, --clear or --new-id")
+	}
+	if name != "" && clear {
+		return nil, fmt.Errorf("cannot combine a new name with --clear")
 	}
 
-	_, err = wsClient.UpdateEntity(entityID, updates)
-	if err != nil {
-		return fmt.Errorf("failed to rename entity: %w", err)
+	updates := make(map[string]interface{})
+	if clear {
+		updates["name"] = nil
+	} else if name != "" {
+		updates["name"] = name
 	}
 
-	fmt.Printf("Renamed %s to: %s\n", entityID, newName)
-	return nil
+	if newID != "" && newID != entityID {
+		domain, _, _ := strings.Cut(entityID, ".")
+		newDomain, objectID, ok := strings.Cut(newID, ".")
+		if !ok || newDomain == "" || objectID == "" || strings.Contains(objectID, ".") {
+			return nil, fmt.Errorf("invalid new entity ID format (expected domain.object_id)")
+		}
+		if newDomain != domain {
+			return nil, fmt.Errorf("new entity ID must use the same domain (%s)", domain)
+		}
+		updates["new_entity_id"] = newID
+	}
+
+	if len(updates) == 0 {
+		return nil, fmt.Errorf("new entity ID is the same as the current one")
+	}
+
+	return updates, nil
+}
+
+// lookupDeviceName returns the display name of a device, or "" if it
+// cannot be determined.
+func lookupDeviceName(client *websocket.Client, deviceID string) string {
+	devices, err := client.GetDevices()
+	if err != nil {
+		printInfo("Warning: could not fetch devices: %v", err)
+		return ""
+	}
+	for _, d := range devices {
+		if d.ID == deviceID {
+			return d.DisplayName()
+		}
+	}
+	return ""
 }
 
 func runEntitiesSetArea(cmd *cobra.Command, args []string) error {
@@ -331,49 +777,169 @@ func runEntitiesSetArea(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	wsClient, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	return withWSClient(cfg, func(wsClient *websocket.Client) error {
+		// Handle "none" or empty string to clear area assignment
+		updates := make(map[string]interface{})
+		if areaID == "" || strings.ToLower(areaID) == "none" {
+			updates["area_id"] = nil
+		} else {
+			foundArea, err := resolveArea(wsClient, areaID, entityAreaCreate)
+			if err != nil {
+				return err
+			}
+
+			updates["area_id"] = foundArea.AreaID
+			areaID = foundArea.AreaID // Use the actual ID
+		}
+
+		_, err = wsClient.UpdateEntity(entityID, updates)
+		if err != nil {
+			return fmt.Errorf("failed to set area: %w", err)
+		}
+
+		if areaID == "" || strings.ToLower(args[1]) == "none" {
+			printSuccess("Removed area assignment from %s", entityID)
+		} else {
+			printSuccess("Assigned %s to area: %s", entityID, areaID)
+		}
+
+		return nil
+	})
+}
+
+// runEntitiesDisableCommand runs 'entities disable' or 'entities enable' for
+// a single entity ID, or in bulk for the entities matching the filter flags.
+func runEntitiesDisableCommand(cmd *cobra.Command, args []string, disable bool) error {
+	if len(args) == 0 {
+		return runEntitiesBulkToggleDisabled(cmd, disable)
+	}
+
+	for _, name := range []string{"domain", "platform", "all"} {
+		if cmd.Flags().Changed(name) {
+			return fmt.Errorf("--%s cannot be combined with an entity ID", name)
+		}
+	}
+	return runEntitiesToggleDisabled(args[0], disable)
+}
+
+// bulkToggleTargets returns the entities in domain and platform (either may
+// be empty to match all) that are not already in the requested state.
+func bulkToggleTargets(entities []websocket.Entity, domain, platform string, disable bool) []websocket.Entity {
+	var targets []websocket.Entity
+	for _, entity := range entities {
+		if domain != "" && !inDomain(entity.EntityID, domain) {
+			continue
+		}
+		if platform != "" && !strings.EqualFold(entity.Platform, platform) {
+			continue
+		}
+		if (entity.DisabledBy != nil) == disable {
+			continue
+		}
+		targets = append(targets, entity)
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].EntityID < targets[j].EntityID
+	})
+
+	return targets
+}
+
+func runEntitiesBulkToggleDisabled(cmd *cobra.Command, disable bool) error {
+	if err := requireScope(cmd, entityBulkAll, "domain", "platform"); err != nil {
+		return err
+	}
+
+	action, done := "enable", "Enabled"
+	var disabledBy interface{}
+	if disable {
+		action, done = "disable", "Disabled"
+		disabledBy = "user"
+	}
+
+	cfg, err := loadConfig()
 	if err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
+		return err
 	}
-	defer wsClient.Close()
 
-	// Handle "none" or empty string to clear area assignment
-	updates := make(map[string]interface{})
-	if areaID == "" || strings.ToLower(areaID) == "none" {
-		updates["area_id"] = nil
-	} else {
-		// Validate area exists
-		areas, err := wsClient.GetAreas()
+	return withWSClient(cfg, func(wsClient *websocket.Client) error {
+		printInfo("Fetching entities...")
+		entities, err := wsClient.GetEntities()
 		if err != nil {
-			return fmt.Errorf("failed to get areas: %w", err)
+			return fmt.Errorf("failed to get entities: %w", err)
 		}
 
-		var foundArea *websocket.Area
-		for _, area := range areas {
-			if area.AreaID == areaID || strings.EqualFold(area.Name, areaID) {
-				foundArea = &area
-				break
+		targets := bulkToggleTargets(entities, entityDomain, entityPlatform, disable)
+		if len(targets) == 0 {
+			printSuccess("No entities to %s", action)
+			return nil
+		}
+
+		for _, entity := range targets {
+			printSuccess("  %s (%s)", entity.EntityID, entity.Platform)
+		}
+		if !entityBulkYes && !confirm(fmt.Sprintf("\n%s %d entities?", strings.ToUpper(action[:1])+action[1:], len(targets))) {
+			printSuccess("Aborted")
+			return nil
+		}
+
+		cmds := make([]websocket.Command, len(targets))
+		for i, entity := range targets {
+			cmds[i] = websocket.Command{
+				Type:    "config/entity_registry/update",
+				Payload: map[string]interface{}{"entity_id": entity.EntityID, "disabled_by": disabledBy},
 			}
 		}
 
-		if foundArea == nil {
-			return fmt.Errorf("area not found: %s", areaID)
+		printInfo("Updating %d entities...", len(targets))
+		failed := 0
+		for i, r := range runBulkConcurrent(wsClient, cmds) {
+			if r.Err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to %s %s: %v\n", action, targets[i].EntityID, r.Err)
+				failed++
+			}
 		}
 
-		updates["area_id"] = foundArea.AreaID
-		areaID = foundArea.AreaID // Use the actual ID
-	}
+		printSuccess("%s %d entities", done, len(targets)-failed)
+		if failed > 0 {
+			return fmt.Errorf("failed to %s %d of %d entities", action, failed, len(targets))
+		}
+		return nil
+	})
+}
 
-	_, err = wsClient.UpdateEntity(entityID, updates)
+func runEntitiesToggleDisabled(entityID string, disable bool) error {
+	cfg, err := loadConfig()
 	if err != nil {
-		return fmt.Errorf("failed to set area: %w", err)
+		return err
 	}
 
-	if areaID == "" || strings.ToLower(args[1]) == "none" {
-		fmt.Printf("Removed area assignment from %s\n", entityID)
-	} else {
-		fmt.Printf("Assigned %s to area: %s\n", entityID, areaID)
-	}
+	return withWSClient(cfg, func(wsClient *websocket.Client) error {
+		var disabledBy interface{}
+		if disable {
+			disabledBy = "user"
+		}
 
-	return nil
+		updates := map[string]interface{}{
+			"disabled_by": disabledBy,
+		}
+
+		entity, err := wsClient.UpdateEntity(entityID, updates)
+		if err != nil {
+			action := "enable entity"
+			if disable {
+				action = "disable entity"
+			}
+			return fmt.Errorf("failed to %s: %w", action, err)
+		}
+
+		status := "enabled"
+		if disable {
+			status = "disabled"
+		}
+
+		printSuccess("Entity %s: %s", status, entity.EntityID)
+		return nil
+	})
 }
@@ -0,0 +1,284 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{input: "7d", want: 7 * 24 * time.Hour},
+		{input: "1.5d", want: 36 * time.Hour},
+		{input: "12h", want: 12 * time.Hour},
+		{input: "90m", want: 90 * time.Minute},
+		{input: "d", wantErr: true},
+		{input: "-1d", wantErr: true},
+		{input: "-5m", wantErr: true},
+		{input: "week", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseAge(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseAge(%q) expected error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseAge(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseAge(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsStale(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		lastChanged string
+		age         time.Duration
+		want        bool
+	}{
+		{"older than age", "2024-01-01T12:00:00+00:00", 7 * 24 * time.Hour, true},
+		{"newer than age", "2024-01-14T12:00:00+00:00", 7 * 24 * time.Hour, false},
+		{"fractional seconds", "2024-01-01T12:00:00.123456+00:00", 24 * time.Hour, true},
+		{"no state", "", time.Hour, false},
+		{"invalid timestamp", "yesterday", time.Hour, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isStale(tt.lastChanged, tt.age, now); got != tt.want {
+				t.Errorf("isStale(%q, %v) = %v, want %v", tt.lastChanged, tt.age, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputEntitiesTable(t *testing.T) {
+	entities := []EntityWithState{
+		{
+			EntityID: "light.kitchen",
+			State:    "on",
+			Name:     strPtr("Kitchen Light"),
+			AreaName: "Kitchen",
+		},
+		{
+			EntityID:     "sensor.outdoor",
+			State:        "a very long state value",
+			Unit:         "°C",
+			OriginalName: strPtr("An extremely long original entity name"),
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeTable(&buf, entitiesTable(entities, nil)); err != nil {
+		t.Fatalf("writeTable() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"ENTITY ID",
+		"light.kitchen",
+		"Kitchen Light",
+		"Kitchen",
+		"a very long ...",
+		"An extremely long original ...",
+		"Total: 2 entities",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestOutputEntitiesTable_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeTable(&buf, entitiesTable(nil, nil)); err != nil {
+		t.Fatalf("writeTable() error = %v", err)
+	}
+	if got := buf.String(); got != "No entities found\n" {
+		t.Errorf("output = %q, want %q", got, "No entities found\n")
+	}
+}
+
+func TestOutputEntitiesTable_Attributes(t *testing.T) {
+	entities := []EntityWithState{
+		{
+			EntityID:   "sensor.door_battery",
+			State:      "87",
+			Attributes: selectAttributes(map[string]interface{}{"battery_level": 87.0, "friendly_name": "Door"}, []string{"battery_level", "temperature"}),
+		},
+		{EntityID: "sensor.no_attrs", State: "on"},
+	}
+
+	table := entitiesTable(entities, []string{"battery_level", "temperature"})
+	if got := len(table.Columns); got != 7 {
+		t.Fatalf("got %d columns, want 7", got)
+	}
+	if table.Columns[5].Header != "BATTERY_LEVEL" || table.Columns[6].Header != "TEMPERATURE" {
+		t.Errorf("attribute headers = %q, %q", table.Columns[5].Header, table.Columns[6].Header)
+	}
+	if got := table.Rows[0][5:]; got[0] != "87" || got[1] != "" {
+		t.Errorf("row 0 attributes = %q, want [87 \"\"]", got)
+	}
+	if got := table.Rows[1][5:]; got[0] != "" || got[1] != "" {
+		t.Errorf("row 1 attributes = %q, want blanks", got)
+	}
+	if _, ok := entities[0].Attributes["friendly_name"]; ok {
+		t.Error("selectAttributes() kept an attribute that was not requested")
+	}
+}
+
+func TestEntityRenameUpdates(t *testing.T) {
+	tests := []struct {
+		name    string
+		newName string
+		clear   bool
+		newID   string
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{name: "name", newName: "Kitchen", want: map[string]interface{}{"name": "Kitchen"}},
+		{name: "clear", clear: true, want: map[string]interface{}{"name": nil}},
+		{name: "new id", newID: "sensor.kitchen", want: map[string]interface{}{"new_entity_id": "sensor.kitchen"}},
+		{name: "name and new id", newName: "Kitchen", newID: "sensor.kitchen", want: map[string]interface{}{"name": "Kitchen", "new_entity_id": "sensor.kitchen"}},
+		{name: "nothing to do", wantErr: true},
+		{name: "name with clear", newName: "Kitchen", clear: true, wantErr: true},
+		{name: "different domain", newID: "light.kitchen", wantErr: true},
+		{name: "malformed new id", newID: "kitchen", wantErr: true},
+		{name: "same id", newID: "sensor.temp", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := entityRenameUpdates("sensor.temp", tt.newName, tt.clear, tt.newID)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("entityRenameUpdates() = %v, expected error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("entityRenameUpdates() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("entityRenameUpdates() = %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if gv, ok := got[k]; !ok || gv != v {
+					t.Errorf("updates[%q] = %v, want %v", k, gv, v)
+				}
+			}
+		})
+	}
+}
+
+func TestUnassignedEntities(t *testing.T) {
+	devices := []websocket.Device{
+		{ID: "dev-kitchen", AreaID: strPtr("kitchen")},
+		{ID: "dev-loose"},
+	}
+	entities := []websocket.Entity{
+		{EntityID: "sensor.own_area", AreaID: strPtr("hall"), Platform: "mqtt"},
+		{EntityID: "sensor.inherited", DeviceID: strPtr("dev-kitchen"), Platform: "zha"},
+		{EntityID: "sensor.loose_device", DeviceID: strPtr("dev-loose"), Platform: "zha"},
+		{EntityID: "light.no_device", Platform: "hue"},
+		{EntityID: "sensor.b_no_device", Platform: "template"},
+	}
+
+	got := unassignedEntities(entities, devices, "")
+	var ids []string
+	for _, e := range got {
+		ids = append(ids, e.EntityID)
+	}
+	want := []string{"light.no_device", "sensor.b_no_device", "sensor.loose_device"}
+	if strings.Join(ids, ",") != strings.Join(want, ",") {
+		t.Errorf("unassignedEntities() = %v, want %v", ids, want)
+	}
+
+	got = unassignedEntities(entities, devices, "Sensor")
+	if len(got) != 2 || got[0].EntityID != "sensor.b_no_device" || got[0].Platform != "template" {
+		t.Errorf("unassignedEntities(domain=Sensor) = %+v", got)
+	}
+}
+
+func TestBulkToggleTargets(t *testing.T) {
+	entities := []websocket.Entity{
+		{EntityID: "sensor.b", Platform: "template"},
+		{EntityID: "sensor.a", Platform: "Template"},
+		{EntityID: "sensor.off", Platform: "template", DisabledBy: strPtr("user")},
+		{EntityID: "binary_sensor.c", Platform: "template"},
+		{EntityID: "sensor.mqtt", Platform: "mqtt"},
+	}
+
+	ids := func(list []websocket.Entity) string {
+		var out []string
+		for _, e := range list {
+			out = append(out, e.EntityID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	if got := ids(bulkToggleTargets(entities, "sensor", "template", true)); got != "sensor.a,sensor.b" {
+		t.Errorf("disable targets = %s, want sensor.a,sensor.b", got)
+	}
+	if got := ids(bulkToggleTargets(entities, "", "template", false)); got != "sensor.off" {
+		t.Errorf("enable targets = %s, want sensor.off", got)
+	}
+	if got := ids(bulkToggleTargets(entities, "", "", true)); got != "binary_sensor.c,sensor.a,sensor.b,sensor.mqtt" {
+		t.Errorf("unfiltered disable targets = %s", got)
+	}
+}
+
+func TestMatchesRegistryStatus(t *testing.T) {
+	plain := EntityWithState{EntityID: "sensor.plain"}
+	disabled := EntityWithState{EntityID: "sensor.disabled", DisabledBy: strPtr("user")}
+	hidden := EntityWithState{EntityID: "sensor.hidden", HiddenBy: strPtr("integration")}
+	both := EntityWithState{EntityID: "sensor.both", DisabledBy: strPtr("integration"), HiddenBy: strPtr("user")}
+	all := []EntityWithState{plain, disabled, hidden, both}
+
+	tests := []struct {
+		name                          string
+		disabled, hidden, enabledOnly bool
+		want                          string
+	}{
+		{name: "no flags", want: "sensor.plain,sensor.disabled,sensor.hidden,sensor.both"},
+		{name: "disabled", disabled: true, want: "sensor.disabled,sensor.both"},
+		{name: "hidden", hidden: true, want: "sensor.hidden,sensor.both"},
+		{name: "disabled or hidden", disabled: true, hidden: true, want: "sensor.disabled,sensor.hidden,sensor.both"},
+		{name: "enabled only", enabledOnly: true, want: "sensor.plain,sensor.hidden"},
+		{name: "enabled and hidden", hidden: true, enabledOnly: true, want: "sensor.hidden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range all {
+				if e.matchesRegistryStatus(tt.disabled, tt.hidden, tt.enabledOnly) {
+					got = append(got, e.EntityID)
+				}
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("kept %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
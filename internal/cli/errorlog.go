@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var (
+	errorLogTail int
+	errorLogGrep string
+)
+
+var errorLogCmd = &cobra.Command{
+	Use:   "error-log",
+	Short: "Show the Home Assistant error log",
+	Long: `Show the Home Assistant error log (home-assistant.log).
+
+--grep keeps the lines matching a regular expression; a pattern that is not
+a valid regular expression is matched as a plain substring. --tail keeps the
+last N lines, after --grep has been applied.
+
+Examples:
+  hass-cli error-log                         # Whole log
+  hass-cli error-log --tail 50               # Last 50 lines
+  hass-cli error-log --grep zha              # Lines mentioning zha
+  hass-cli error-log --grep 'ERROR|WARNING' --tail 20`,
+	Args: cobra.NoArgs,
+	RunE: runErrorLog,
+}
+
+func init() {
+	rootCmd.AddCommand(errorLogCmd)
+
+	errorLogCmd.Flags().IntVar(&errorLogTail, "tail", 0, "Only show the last N lines (0 = all)")
+	errorLogCmd.Flags().StringVar(&errorLogGrep, "grep", "", "Only show lines matching this regular expression or substring")
+}
+
+func runErrorLog(cmd *cobra.Command, args []string) error {
+	if errorLogTail < 0 {
+		return fmt.Errorf("--tail must not be negative")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	printInfo("Fetching error log...")
+	log, err := client.GetErrorLog()
+	if err != nil {
+		if api.IsNotFound(err) {
+			return fmt.Errorf("error log is not available")
+		}
+		return fmt.Errorf("failed to get error log: %w", err)
+	}
+
+	lines := filterLogLines(log, errorLogGrep, errorLogTail)
+
+	if jsonOutput {
+		return outputJSON(cmd.OutOrStdout(), lines)
+	}
+
+	for _, line := range lines {
+		fmt.Fprintln(cmd.OutOrStdout(), line)
+	}
+
+	return nil
+}
+
+// filterLogLines splits log into lines, keeps those matching pattern (all
+// if it is empty) and then the last tail of them (all if tail is 0).
+// pattern is a regular expression, or a plain substring if it does not
+// compile as one.
+func filterLogLines(log, pattern string, tail int) []string {
+	lines := strings.Split(strings.TrimRight(log, "\n"), "\n")
+	if log == "" {
+		lines = nil
+	}
+
+	if pattern != "" {
+		match := func(line string) bool { return strings.Contains(line, pattern) }
+		if re, err := regexp.Compile(pattern); err == nil {
+			match = re.MatchString
+		}
+
+		filtered := make([]string, 0, len(lines))
+		for _, line := range lines {
+			if match(line) {
+				filtered = append(filtered, line)
+			}
+		}
+		lines = filtered
+	}
+
+	if tail > 0 && len(lines) > tail {
+		lines = lines[len(lines)-tail:]
+	}
+
+	return lines
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFilterLogLines(t *testing.T) {
+	log := "10:00 INFO started\n10:01 ERROR zha failed\n10:02 WARNING slow (zha)\n10:03 ERROR mqtt failed\n"
+
+	tests := []struct {
+		name    string
+		pattern string
+		tail    int
+		want    []string
+	}{
+		{name: "all", want: []string{"10:00 INFO started", "10:01 ERROR zha failed", "10:02 WARNING slow (zha)", "10:03 ERROR mqtt failed"}},
+		{name: "tail", tail: 2, want: []string{"10:02 WARNING slow (zha)", "10:03 ERROR mqtt failed"}},
+		{name: "tail larger than log", tail: 10, want: []string{"10:00 INFO started", "10:01 ERROR zha failed", "10:02 WARNING slow (zha)", "10:03 ERROR mqtt failed"}},
+		{name: "substring", pattern: "zha", want: []string{"10:01 ERROR zha failed", "10:02 WARNING slow (zha)"}},
+		{name: "regex", pattern: "ERROR|WARNING", tail: 2, want: []string{"10:02 WARNING slow (zha)", "10:03 ERROR mqtt failed"}},
+		{name: "invalid regex as substring", pattern: "(zha", want: []string{"10:02 WARNING slow (zha)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterLogLines(log, tt.pattern, tt.tail)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("filterLogLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := filterLogLines("", "", 0); len(got) != 0 {
+		t.Errorf("filterLogLines(empty) = %q, want none", got)
+	}
+}
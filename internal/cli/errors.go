@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+)
+
+// ExitUnauthorized is the exit code when Home Assistant rejects the token.
+const ExitUnauthorized = 3
+
+// ExitError makes the process exit with Code. A nil Err exits without
+// printing anything, for commands whose output already says what happened.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the process exit code for an error returned by Execute.
+func ExitCode(err error) int {
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return 1
+}
+
+// unauthorizedError replaces the message of an authentication failure with
+// what to do about it. The original error stays available for --json.
+type unauthorizedError struct {
+	err error
+}
+
+func (e *unauthorizedError) Error() string {
+	return "Your token appears to be revoked or invalid. Run 'hass-cli login' to reconfigure."
+}
+
+func (e *unauthorizedError) Unwrap() error {
+	return e.err
+}
+
+// checkUnauthorized turns an error caused by Home Assistant rejecting the
+// token, over REST or WebSocket, into an ExitError with ExitUnauthorized.
+// Other errors are returned unchanged.
+func checkUnauthorized(err error) error {
+	if err == nil || IsSilent(err) {
+		return err
+	}
+	if !api.IsUnauthorized(err) && !errors.Is(err, websocket.ErrAuthInvalid) {
+		return err
+	}
+	return &ExitError{Code: ExitUnauthorized, Err: &unauthorizedError{err: err}}
+}
+
+// IsSilent reports whether err should end the process without an error
+// message.
+func IsSilent(err error) bool {
+	var exitErr *ExitError
+	return errors.As(err, &exitErr) && exitErr.Err == nil
+}
+
+// ErrorDetails is the body of a JSON error report.
+type ErrorDetails struct {
+	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
+	Status  int    `json:"status,omitempty"`
+}
+
+// ReportError prints an error returned by Execute. In JSON mode it is written
+// as {"error": {...}} to stderr, or to stdout with --errors-stdout, so the
+// failure path is as machine-readable as the output.
+func ReportError(err error) {
+	if IsSilent(err) {
+		return
+	}
+
+	if !jsonOutput {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return
+	}
+
+	out := io.Writer(os.Stderr)
+	if errorsToStdout {
+		out = os.Stdout
+	}
+	outputJSON(out, map[string]ErrorDetails{"error": errorDetails(err)})
+}
+
+// errorDetails extracts the code and HTTP status of err from the API and
+// WebSocket error types, when it wraps one.
+func errorDetails(err error) ErrorDetails {
+	details := ErrorDetails{Message: err.Error()}
+
+	var apiErr *api.APIError
+	var wsErr *websocket.ErrorResult
+	switch {
+	case errors.As(err, &apiErr):
+		details.Code = apiErr.Code
+		details.Status = apiErr.StatusCode
+	case errors.As(err, &wsErr):
+		details.Code = wsErr.Code
+	case errors.Is(err, websocket.ErrAuthInvalid):
+		details.Code = "unauthorized"
+	}
+
+	return details
+}
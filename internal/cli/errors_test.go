@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+)
+
+func TestErrorDetails(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorDetails
+	}{
+		{
+			name: "plain error",
+			err:  errors.New("invalid service format"),
+			want: ErrorDetails{Message: "invalid service format"},
+		},
+		{
+			name: "wrapped API error",
+			err:  fmt.Errorf("failed to get state: %w", api.ErrNotFound),
+			want: ErrorDetails{Message: "failed to get state: " + api.ErrNotFound.Error(), Code: "not_found", Status: 404},
+		},
+		{
+			name: "WebSocket error",
+			err:  fmt.Errorf("failed to rename entity: %w", &websocket.ErrorResult{Code: "invalid_format", Message: "bad id"}),
+			want: ErrorDetails{Message: "failed to rename entity: invalid_format: bad id", Code: "invalid_format"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorDetails(tt.err); got != tt.want {
+				t.Errorf("errorDetails() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	if got := ExitCode(errors.New("boom")); got != 1 {
+		t.Errorf("ExitCode(plain) = %d, want 1", got)
+	}
+	if got := ExitCode(fmt.Errorf("wrapped: %w", &ExitError{Code: 3, Err: errors.New("auth")})); got != 3 {
+		t.Errorf("ExitCode(wrapped ExitError) = %d, want 3", got)
+	}
+	if !IsSilent(&ExitError{Code: 1}) {
+		t.Error("IsSilent(ExitError without Err) = false, want true")
+	}
+	if IsSilent(&ExitError{Code: 1, Err: errors.New("boom")}) {
+		t.Error("IsSilent(ExitError with Err) = true, want false")
+	}
+}
+
+func TestCheckUnauthorized(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "REST", err: fmt.Errorf("failed to get states: %w", api.ErrUnauthorized), want: true},
+		{name: "WebSocket", err: fmt.Errorf("failed to connect: %w", fmt.Errorf("%w: Invalid access token", websocket.ErrAuthInvalid)), want: true},
+		{name: "other API error", err: fmt.Errorf("failed: %w", api.ErrNotFound)},
+		{name: "plain", err: errors.New("boom")},
+		{name: "silent", err: &ExitError{Code: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkUnauthorized(tt.err)
+			if !tt.want {
+				if got != tt.err {
+					t.Errorf("checkUnauthorized() = %v, want the error unchanged", got)
+				}
+				return
+			}
+			if code := ExitCode(got); code != ExitUnauthorized {
+				t.Errorf("ExitCode() = %d, want %d", code, ExitUnauthorized)
+			}
+			if !strings.Contains(got.Error(), "hass-cli login") {
+				t.Errorf("message = %q, want a hint to run login", got.Error())
+			}
+			if details := errorDetails(got); details.Code != "unauthorized" {
+				t.Errorf("errorDetails().Code = %q, want unauthorized", details.Code)
+			}
+		})
+	}
+
+	if checkUnauthorized(nil) != nil {
+		t.Error("checkUnauthorized(nil) != nil")
+	}
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// exportAsJSON reports whether an export written to path should be JSON
+// rather than YAML: with --json or a .json extension.
+func exportAsJSON(path string) bool {
+	return jsonOutput || strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+// writeExport writes v to w as JSON or YAML.
+func writeExport(w io.Writer, v interface{}, asJSON bool) error {
+	if asJSON {
+		return outputJSON(w, v)
+	}
+
+	if redactOutput {
+		redacted, err := redactData(v)
+		if err != nil {
+			return err
+		}
+		v = redacted
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// readExport decodes the list in an export file into list, which must be a
+// pointer to a slice. The file is YAML or JSON holding either the bare list
+// or an object with the list under key.
+func readExport(path, key string, list interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	// JSON is valid YAML, so one decoder handles both formats
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	root := doc.Content[0]
+	switch root.Kind {
+	case yaml.SequenceNode:
+		// A bare list
+	case yaml.MappingNode:
+		var found *yaml.Node
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			if root.Content[i].Value == key {
+				found = root.Content[i+1]
+				break
+			}
+		}
+		if found == nil {
+			return nil
+		}
+		root = found
+	default:
+		return fmt.Errorf("failed to parse %s: expected a list or a %q key", path, key)
+	}
+
+	if err := root.Decode(list); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// assignConfigIDs gives items without an ID, or every item when newIDs is
+// set, a timestamp-based ID like the ones the UI creates. IDs count up from
+// now in milliseconds so items imported together never collide.
+func assignConfigIDs[T any](items []T, id func(*T) *string, newIDs bool, now time.Time) {
+	next := now.UnixMilli()
+	for i := range items {
+		p := id(&items[i])
+		if !newIDs && *p != "" {
+			continue
+		}
+		*p = strconv.FormatInt(next, 10)
+		next++
+	}
+}
@@ -0,0 +1,231 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+	"github.com/spf13/cobra"
+)
+
+// voiceAssistants maps the assistant names accepted on the command line to
+// the IDs Home Assistant uses for them, in display order.
+var voiceAssistants = []struct {
+	Name string
+	ID   string
+}{
+	{"conversation", "conversation"},
+	{"alexa", "cloud.alexa"},
+	{"google", "cloud.google_assistant"},
+}
+
+var (
+	exposeAssistants  []string
+	exposeExpose      bool
+	exposeHide        bool
+	exposedAssistants []string
+)
+
+var entitiesExposeCmd = &cobra.Command{
+	Use:   "expose <entity_id> [entity_id...]",
+	Short: "Expose or hide entities from voice assistants",
+	Long: `Control whether entities are exposed to voice assistants.
+
+Assistants are conversation (Assist), alexa and google (via Home Assistant
+Cloud). --assistant can be repeated; it defaults to conversation.
+
+Examples:
+  hass-cli entities expose light.kitchen --expose
+  hass-cli entities expose light.kitchen light.hall --expose --assistant alexa --assistant google
+  hass-cli entities expose lock.front_door --hide`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runEntitiesExpose,
+}
+
+var entitiesExposedCmd = &cobra.Command{
+	Use:   "exposed",
+	Short: "List entities exposed to voice assistants",
+	Long: `List entities exposed to at least one voice assistant, or to the ones given
+with --assistant.
+
+Examples:
+  hass-cli entities exposed
+  hass-cli entities exposed --assistant alexa
+  hass-cli entities exposed --json`,
+	Args: cobra.NoArgs,
+	RunE: runEntitiesExposed,
+}
+
+func init() {
+	entitiesCmd.AddCommand(entitiesExposeCmd)
+	entitiesCmd.AddCommand(entitiesExposedCmd)
+
+	entitiesExposeCmd.Flags().StringSliceVar(&exposeAssistants, "assistant", []string{"conversation"}, "Voice assistant: conversation, alexa, google (repeatable)")
+	entitiesExposeCmd.Flags().BoolVar(&exposeExpose, "expose", false, "Expose the entities")
+	entitiesExposeCmd.Flags().BoolVar(&exposeHide, "hide", false, "Stop exposing the entities")
+
+	entitiesExposedCmd.Flags().StringSliceVar(&exposedAssistants, "assistant", nil, "Only show entities exposed to this assistant (repeatable)")
+}
+
+// EntityExposure is the voice assistant exposure of a single entity.
+type EntityExposure struct {
+	EntityID   string          `json:"entity_id"`
+	Assistants map[string]bool `json:"assistants"`
+}
+
+// resolveAssistants maps assistant names to Home Assistant assistant IDs.
+// Full IDs such as "cloud.alexa" are accepted as well.
+func resolveAssistants(names []string) ([]string, error) {
+	var ids []string
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "assist" {
+			name = "conversation"
+		}
+
+		found := false
+		for _, a := range voiceAssistants {
+			if name == a.Name || name == a.ID {
+				ids = append(ids, a.ID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown assistant: %s (use conversation, alexa or google)", name)
+		}
+	}
+	return ids, nil
+}
+
+// unsupportedExposeError explains an unknown_command error from the expose
+// commands, which only exist in Home Assistant 2023.5 and later.
+func unsupportedExposeError(err error) error {
+	if websocket.IsUnknownCommand(err) {
+		return fmt.Errorf("this Home Assistant version does not support voice assistant exposure (requires 2023.5 or later)")
+	}
+	return err
+}
+
+func runEntitiesExpose(cmd *cobra.Command, args []string) error {
+	if exposeExpose == exposeHide {
+		return fmt.Errorf("specify exactly one of --expose or --hide")
+	}
+
+	assistants, err := resolveAssistants(exposeAssistants)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	printInfo("Connecting to Home Assistant...")
+	client, err := newWSClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer client.Close()
+
+	if err := client.ExposeEntities(assistants, args, exposeExpose); err != nil {
+		return fmt.Errorf("failed to update exposure: %w", unsupportedExposeError(err))
+	}
+
+	action := "Exposed"
+	if exposeHide {
+		action = "Hid"
+	}
+	printSuccess("%s %d entities (%s)", action, len(args), strings.Join(assistants, ", "))
+
+	return nil
+}
+
+func runEntitiesExposed(cmd *cobra.Command, args []string) error {
+	assistants, err := resolveAssistants(exposedAssistants)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	printInfo("Connecting to Home Assistant...")
+	client, err := newWSClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer client.Close()
+
+	printInfo("Fetching exposed entities...")
+	settings, err := client.ListExposedEntities()
+	if err != nil {
+		return fmt.Errorf("failed to get exposed entities: %w", unsupportedExposeError(err))
+	}
+
+	exposed := filterExposed(settings, assistants)
+
+	return outputData(cmd.OutOrStdout(), exposed, exposedTable(exposed))
+}
+
+// filterExposed returns the entities exposed to any of the given assistants,
+// or to any assistant at all when none are given, sorted by entity ID.
+func filterExposed(settings map[string]map[string]bool, assistants []string) []EntityExposure {
+	var exposed []EntityExposure
+	for entityID, flags := range settings {
+		match := false
+		for assistant, on := range flags {
+			if !on {
+				continue
+			}
+			if len(assistants) == 0 {
+				match = true
+				break
+			}
+			for _, a := range assistants {
+				if assistant == a {
+					match = true
+				}
+			}
+		}
+		if match {
+			exposed = append(exposed, EntityExposure{EntityID: entityID, Assistants: flags})
+		}
+	}
+
+	sort.Slice(exposed, func(i, j int) bool {
+		return exposed[i].EntityID < exposed[j].EntityID
+	})
+
+	return exposed
+}
+
+func exposedTable(exposed []EntityExposure) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "ENTITY ID"}, {Header: "ASSIST"}, {Header: "ALEXA"}, {Header: "GOOGLE"}},
+		Noun:    "entities",
+		Empty:   "No exposed entities found",
+	}
+
+	yesNo := func(v bool) string {
+		if v {
+			return "yes"
+		}
+		return "-"
+	}
+
+	for _, e := range exposed {
+		t.addRow(
+			e.EntityID,
+			yesNo(e.Assistants[voiceAssistants[0].ID]),
+			yesNo(e.Assistants[voiceAssistants[1].ID]),
+			yesNo(e.Assistants[voiceAssistants[2].ID]),
+		)
+	}
+
+	return t
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dorinclisu/hass-cli/internal/testutil"
+)
+
+func TestResolveAssistants(t *testing.T) {
+	got, err := resolveAssistants([]string{"Assist", "alexa", "cloud.google_assistant"})
+	if err != nil {
+		t.Fatalf("resolveAssistants() error = %v", err)
+	}
+	want := []string{"conversation", "cloud.alexa", "cloud.google_assistant"}
+	if len(got) != len(want) {
+		t.Fatalf("resolveAssistants() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("resolveAssistants()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	if _, err := resolveAssistants([]string{"siri"}); err == nil {
+		t.Error("resolveAssistants(siri) expected error")
+	}
+}
+
+func TestFilterExposed(t *testing.T) {
+	settings := map[string]map[string]bool{
+		"light.kitchen": {"conversation": true, "cloud.alexa": true},
+		"light.hall":    {"conversation": true},
+		"lock.front":    {"conversation": false},
+		"switch.fan":    {"cloud.alexa": true},
+	}
+
+	tests := []struct {
+		name       string
+		assistants []string
+		want       []string
+	}{
+		{"any assistant", nil, []string{"light.hall", "light.kitchen", "switch.fan"}},
+		{"alexa only", []string{"cloud.alexa"}, []string{"light.kitchen", "switch.fan"}},
+		{"google", []string{"cloud.google_assistant"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterExposed(settings, tt.assistants)
+			if len(got) != len(tt.want) {
+				t.Fatalf("filterExposed() returned %d entities, want %d", len(got), len(tt.want))
+			}
+			for i, e := range got {
+				if e.EntityID != tt.want[i] {
+					t.Errorf("filterExposed()[%d] = %q, want %q", i, e.EntityID, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEntitiesExpose_DefaultAssistant(t *testing.T) {
+	mock := testutil.NewWSMock(t, testToken)
+
+	var got map[string]interface{}
+	mock.Handle("homeassistant/expose_entity", func(msg map[string]interface{}) (interface{}, error) {
+		got = msg
+		return nil, nil
+	})
+
+	defer func() {
+		serverURL, token, configPath = "", "", ""
+		exposeExpose = false
+		rootCmd.SetArgs(nil)
+	}()
+
+	rootCmd.SetArgs([]string{"entities", "expose", "light.kitchen", "--expose",
+		"--url", mock.URL(), "--token", testToken, "--config", filepath.Join(t.TempDir(), "none.yaml")})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("entities expose error = %v", err)
+	}
+
+	if got == nil {
+		t.Fatal("expose_entity was not sent")
+	}
+	assistants, _ := got["assistants"].([]interface{})
+	if len(assistants) != 1 || assistants[0] != "conversation" {
+		t.Errorf("assistants = %v, want [conversation]", got["assistants"])
+	}
+	if got["should_expose"] != true {
+		t.Errorf("should_expose = %v, want true", got["should_expose"])
+	}
+}
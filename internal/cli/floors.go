@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+	"github.com/spf13/cobra"
+)
+
+var floorsCmd = &cobra.Command{
+	Use:   "floors",
+	Short: "List all floors",
+	Long: `List all floors defined in Home Assistant with the number of areas on each.
+
+Floors are listed from the lowest level up; floors without a level come last.
+Use 'hass-cli areas inspect <area>' to see the floor of an area.
+
+Examples:
+  hass-cli floors             # List all floors
+  hass-cli floors --json      # Output as JSON`,
+	Args: cobra.NoArgs,
+	RunE: runFloors,
+}
+
+func init() {
+	rootCmd.AddCommand(floorsCmd)
+}
+
+// FloorWithCounts combines floor info with the number of areas on it.
+type FloorWithCounts struct {
+	FloorID   string   `json:"floor_id"`
+	Name      string   `json:"name"`
+	Level     *int     `json:"level"`
+	Icon      *string  `json:"icon"`
+	Aliases   []string `json:"aliases"`
+	AreaCount int      `json:"area_count"`
+}
+
+func runFloors(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	return withWSClient(cfg, func(client *websocket.Client) error {
+		printInfo("Fetching floors...")
+		floors, err := client.GetFloors()
+		if err != nil {
+			return fmt.Errorf("failed to get floors: %w", err)
+		}
+
+		// Areas are only used for counts
+		printInfo("Fetching areas...")
+		areas, err := client.GetAreas()
+		if err != nil {
+			printInfo("Warning: could not fetch areas: %v", err)
+			areas = []websocket.Area{}
+		}
+
+		result := floorsWithCounts(floors, areas)
+		return outputData(cmd.OutOrStdout(), result, floorsTable(result))
+	})
+}
+
+// floorsWithCounts counts the areas on each floor and sorts the floors by
+// level, then name. Floors without a level sort last.
+func floorsWithCounts(floors []websocket.Floor, areas []websocket.Area) []FloorWithCounts {
+	areaCounts := make(map[string]int)
+	for _, area := range areas {
+		if area.FloorID != nil {
+			areaCounts[*area.FloorID]++
+		}
+	}
+
+	result := []FloorWithCounts{}
+	for _, floor := range floors {
+		result = append(result, FloorWithCounts{
+			FloorID:   floor.FloorID,
+			Name:      floor.Name,
+			Level:     floor.Level,
+			Icon:      floor.Icon,
+			Aliases:   floor.Aliases,
+			AreaCount: areaCounts[floor.FloorID],
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].Level, result[j].Level
+		switch {
+		case a != nil && b != nil && *a != *b:
+			return *a < *b
+		case (a == nil) != (b == nil):
+			return a != nil
+		}
+		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+	})
+
+	return result
+}
+
+func floorsTable(floors []FloorWithCounts) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "FLOOR ID"}, {Header: "NAME"}, {Header: "LEVEL"}, {Header: "AREAS"}},
+		Noun:    "floors",
+		Empty:   "No floors found",
+	}
+
+	for _, f := range floors {
+		level := "-"
+		if f.Level != nil {
+			level = strconv.Itoa(*f.Level)
+		}
+
+		t.addRow(
+			f.FloorID,
+			f.Name,
+			level,
+			strconv.Itoa(f.AreaCount),
+		)
+	}
+
+	return t
+}
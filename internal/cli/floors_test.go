@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+)
+
+func intPtr(i int) *int { return &i }
+
+func TestFloorsWithCounts(t *testing.T) {
+	floors := []websocket.Floor{
+		{FloorID: "attic", Name: "Attic"},
+		{FloorID: "first", Name: "First Floor", Level: intPtr(1)},
+		{FloorID: "basement", Name: "Basement", Level: intPtr(-1)},
+		{FloorID: "annex", Name: "annex"},
+		{FloorID: "ground", Name: "Ground Floor", Level: intPtr(0)},
+	}
+	areas := []websocket.Area{
+		{AreaID: "kitchen", FloorID: strPtr("ground")},
+		{AreaID: "living_room", FloorID: strPtr("ground")},
+		{AreaID: "bedroom", FloorID: strPtr("first")},
+		{AreaID: "garden"},
+	}
+
+	got := floorsWithCounts(floors, areas)
+
+	var order []string
+	counts := make(map[string]int)
+	for _, f := range got {
+		order = append(order, f.FloorID)
+		counts[f.FloorID] = f.AreaCount
+	}
+	if want := "basement,ground,first,annex,attic"; strings.Join(order, ",") != want {
+		t.Errorf("order = %v, want %s", order, want)
+	}
+	if counts["ground"] != 2 || counts["first"] != 1 || counts["attic"] != 0 {
+		t.Errorf("area counts = %v", counts)
+	}
+
+	if got := floorsWithCounts(nil, nil); got == nil || len(got) != 0 {
+		t.Errorf("floorsWithCounts(nil, nil) = %#v, want empty slice", got)
+	}
+}
+
+func TestFindFloorName(t *testing.T) {
+	floors := []websocket.Floor{{FloorID: "ground", Name: "Ground Floor"}}
+
+	if got := findFloorName(floors, "ground"); got == nil || *got != "Ground Floor" {
+		t.Errorf("findFloorName(ground) = %v, want Ground Floor", got)
+	}
+	if got := findFloorName(floors, "roof"); got != nil {
+		t.Errorf("findFloorName(roof) = %q, want nil", *got)
+	}
+}
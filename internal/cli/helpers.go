@@ -3,14 +3,11 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"slices"
 	"sort"
 	"strings"
-	"text/tabwriter"
-	"time"
 
 	"github.com/dorinclisu/hass-cli/internal/api"
-	"github.com/dorinclisu/hass-cli/internal/websocket"
 	"github.com/spf13/cobra"
 )
 
@@ -31,6 +28,7 @@ Examples:
   hass-cli helpers create-button <name>     # Create a button helper
   hass-cli helpers create-number <name>     # Create a number helper
   hass-cli helpers create-text <name>       # Create a text input helper
+  hass-cli helpers apply <manifest.yaml>    # Create helpers from a manifest
   hass-cli helpers delete <helper_id>       # Delete a helper`,
 	RunE: runHelpers,
 }
@@ -119,8 +117,14 @@ var helpersEditSelectCmd = &cobra.Command{
 	Short: "Edit an existing dropdown helper",
 	Long: `Edit an existing input_select helper by updating its options.
 
+--options replaces the whole list. --add-option and --remove-option start
+from the helper's current options instead, appending or removing single
+options; both can be repeated and combined.
+
 Examples:
-  hass-cli helpers edit-select input_select.my_dropdown --options '["new1","new2"]'`,
+  hass-cli helpers edit-select input_select.my_dropdown --options '["new1","new2"]'
+  hass-cli helpers edit-select input_select.my_dropdown --add-option new3
+  hass-cli helpers edit-select input_select.my_dropdown --remove-option new1 --add-option new4`,
 	Args: cobra.ExactArgs(1),
 	RunE: runHelpersEditSelect,
 }
@@ -188,6 +192,9 @@ var (
 	helperHasInitial  bool
 	helperTextMin     int
 	helperTextMax     int
+
+	helperAddOptions    []string
+	helperRemoveOptions []string
 )
 
 func init() {
@@ -228,9 +235,20 @@ func init() {
 	helpersCreateTextCmd.Flags().StringVar(&helperIcon, "icon", "", "Icon (e.g., mdi:text)")
 
 	helpersEditSelectCmd.Flags().StringVar(&helperOptions, "options", "", "JSON array of options")
+	helpersEditSelectCmd.Flags().StringArrayVar(&helperAddOptions, "add-option", nil, "Append an option to the current ones, can be repeated")
+	helpersEditSelectCmd.Flags().StringArrayVar(&helperRemoveOptions, "remove-option", nil, "Remove an option from the current ones, can be repeated")
+	helpersEditSelectCmd.MarkFlagsMutuallyExclusive("options", "add-option")
+	helpersEditSelectCmd.MarkFlagsMutuallyExclusive("options", "remove-option")
 
 	helpersRenameCmd.Flags().StringVar(&helperRenameName, "name", "", "New friendly name")
 	helpersRenameCmd.Flags().StringVar(&helperNewEntityID, "new-id", "", "New entity ID (domain.object_id)")
+
+	for _, cmd := range []*cobra.Command{
+		helpersCreateSelectCmd, helpersCreateBooleanCmd, helpersCreateButtonCmd, helpersCreateNumberCmd,
+		helpersCreateTextCmd, helpersEditSelectCmd, helpersDeleteCmd,
+	} {
+		addReloadFlag(cmd)
+	}
 }
 
 type HelperInfo struct {
@@ -248,7 +266,7 @@ func runHelpers(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	states, err := client.GetStates()
 	if err != nil {
@@ -294,20 +312,18 @@ func runHelpers(cmd *cobra.Command, args []string) error {
 		return helpers[i].EntityID < helpers[j].EntityID
 	})
 
-	if jsonOutput {
-		return outputJSON(helpers)
-	}
-
-	return outputHelpersTable(helpers)
+	return outputData(cmd.OutOrStdout(), helpers, helpersTable(helpers))
 }
 
-func outputHelpersTable(helpers []HelperInfo) error {
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "ENTITY ID\tTYPE\tSTATE\tNAME")
-	fmt.Fprintln(w, "---------\t----\t-----\t----")
+func helpersTable(helpers []HelperInfo) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "ENTITY ID"}, {Header: "TYPE"}, {Header: "STATE"}, {Header: "NAME"}},
+		Noun:    "helpers",
+		Empty:   "No helpers found",
+	}
 
 	for _, h := range helpers {
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+		t.addRow(
 			h.EntityID,
 			h.Type,
 			h.State,
@@ -315,9 +331,7 @@ func outputHelpersTable(helpers []HelperInfo) error {
 		)
 	}
 
-	fmt.Fprintf(w, "\nTotal: %d helpers\n", len(helpers))
-	w.Flush()
-	return nil
+	return t
 }
 
 func runHelpersInspect(cmd *cobra.Command, args []string) error {
@@ -328,14 +342,14 @@ func runHelpersInspect(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	state, err := client.GetState(helperID)
 	if err != nil {
 		return fmt.Errorf("failed to get helper state: %w", err)
 	}
 
-	return outputJSON(state)
+	return outputJSON(cmd.OutOrStdout(), state)
 }
 
 func runHelpersCreateSelect(cmd *cobra.Command, args []string) error {
@@ -356,7 +370,7 @@ func runHelpersCreateSelect(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("at least one option is required")
 	}
 
-	wsClient, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	wsClient, err := newWSClient(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to connect to Home Assistant: %w", err)
 	}
@@ -367,11 +381,9 @@ func runHelpersCreateSelect(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create input_select: %w", err)
 	}
 
-	fmt.Printf("Input select created: %s\n", helper.Name)
-	fmt.Printf("Entity ID: input_select.%s\n", helper.ID)
-	fmt.Printf("\nNote: You may need to reload input_select or restart Home Assistant for the new helper to appear.\n")
-
-	return nil
+	printSuccess("Input select created: %s", helper.Name)
+	printSuccess("Entity ID: input_select.%s", helper.ID)
+	return reloadOrNote(cfg, "input_select", "You may need to reload input_select or restart Home Assistant for the new helper to appear.")
 }
 
 func runHelpersCreateBoolean(cmd *cobra.Command, args []string) error {
@@ -382,7 +394,7 @@ func runHelpersCreateBoolean(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	wsClient, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	wsClient, err := newWSClient(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to connect to Home Assistant: %w", err)
 	}
@@ -393,11 +405,9 @@ func runHelpersCreateBoolean(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create input_boolean: %w", err)
 	}
 
-	fmt.Printf("Input boolean created: %s\n", helper.Name)
-	fmt.Printf("Entity ID: input_boolean.%s\n", helper.ID)
-	fmt.Printf("\nNote: You may need to reload input_boolean or restart Home Assistant for the new helper to appear.\n")
-
-	return nil
+	printSuccess("Input boolean created: %s", helper.Name)
+	printSuccess("Entity ID: input_boolean.%s", helper.ID)
+	return reloadOrNote(cfg, "input_boolean", "You may need to reload input_boolean or restart Home Assistant for the new helper to appear.")
 }
 
 func runHelpersCreateButton(cmd *cobra.Command, args []string) error {
@@ -408,7 +418,7 @@ func runHelpersCreateButton(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	wsClient, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	wsClient, err := newWSClient(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to connect to Home Assistant: %w", err)
 	}
@@ -419,11 +429,9 @@ func runHelpersCreateButton(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create input_button: %w", err)
 	}
 
-	fmt.Printf("Input button created: %s\n", helper.Name)
-	fmt.Printf("Entity ID: input_button.%s\n", helper.ID)
-	fmt.Printf("\nNote: You may need to reload input_button or restart Home Assistant for the new helper to appear.\n")
-
-	return nil
+	printSuccess("Input button created: %s", helper.Name)
+	printSuccess("Entity ID: input_button.%s", helper.ID)
+	return reloadOrNote(cfg, "input_button", "You may need to reload input_button or restart Home Assistant for the new helper to appear.")
 }
 
 func runHelpersCreateNumber(cmd *cobra.Command, args []string) error {
@@ -434,7 +442,7 @@ func runHelpersCreateNumber(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	wsClient, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	wsClient, err := newWSClient(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to connect to Home Assistant: %w", err)
 	}
@@ -450,12 +458,10 @@ func runHelpersCreateNumber(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create input_number: %w", err)
 	}
 
-	fmt.Printf("Input number created: %s\n", helper.Name)
-	fmt.Printf("Entity ID: input_number.%s\n", helper.ID)
-	fmt.Printf("Range: %.2f to %.2f (step: %.2f)\n", helperMin, helperMax, helperStep)
-	fmt.Printf("\nNote: You may need to reload input_number or restart Home Assistant for the new helper to appear.\n")
-
-	return nil
+	printSuccess("Input number created: %s", helper.Name)
+	printSuccess("Entity ID: input_number.%s", helper.ID)
+	printSuccess("Range: %.2f to %.2f (step: %.2f)", helperMin, helperMax, helperStep)
+	return reloadOrNote(cfg, "input_number", "You may need to reload input_number or restart Home Assistant for the new helper to appear.")
 }
 
 func runHelpersCreateText(cmd *cobra.Command, args []string) error {
@@ -466,7 +472,7 @@ func runHelpersCreateText(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	wsClient, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	wsClient, err := newWSClient(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to connect to Home Assistant: %w", err)
 	}
@@ -477,15 +483,13 @@ func runHelpersCreateText(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create input_text: %w", err)
 	}
 
-	fmt.Printf("Input text created: %s\n", helper.Name)
-	fmt.Printf("Entity ID: input_text.%s\n", helper.ID)
-	fmt.Printf("Length: %d to %d characters\n", helperTextMin, helperTextMax)
+	printSuccess("Input text created: %s", helper.Name)
+	printSuccess("Entity ID: input_text.%s", helper.ID)
+	printSuccess("Length: %d to %d characters", helperTextMin, helperTextMax)
 	if helperPattern != "" {
-		fmt.Printf("Pattern: %s\n", helperPattern)
+		printSuccess("Pattern: %s", helperPattern)
 	}
-	fmt.Printf("\nNote: You may need to reload input_text or restart Home Assistant for the new helper to appear.\n")
-
-	return nil
+	return reloadOrNote(cfg, "input_text", "You may need to reload input_text or restart Home Assistant for the new helper to appear.")
 }
 
 func runHelpersEditSelect(cmd *cobra.Command, args []string) error {
@@ -500,11 +504,20 @@ func runHelpersEditSelect(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
-	// Parse options
 	var options []string
-	if err := json.Unmarshal([]byte(helperOptions), &options); err != nil {
+	if len(helperAddOptions) > 0 || len(helperRemoveOptions) > 0 {
+		printInfo("Fetching current options of %s...", helperID)
+		state, err := client.GetState(helperID)
+		if err != nil {
+			return fmt.Errorf("failed to get %s: %w", helperID, err)
+		}
+		options, err = editSelectOptions(stateOptions(state), helperAddOptions, helperRemoveOptions)
+		if err != nil {
+			return err
+		}
+	} else if err := json.Unmarshal([]byte(helperOptions), &options); err != nil {
 		return fmt.Errorf("invalid options JSON: %w", err)
 	}
 
@@ -518,8 +531,40 @@ func runHelpersEditSelect(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to update options: %w", err)
 	}
 
-	fmt.Printf("Input select updated: %s\n", helperID)
-	return nil
+	printSuccess("Input select updated: %s", helperID)
+	return reloadOrNote(cfg, "input_select", "")
+}
+
+// stateOptions returns the options attribute of an input_select state.
+func stateOptions(state *api.State) []string {
+	raw, _ := state.Attributes["options"].([]interface{})
+	options := make([]string, 0, len(raw))
+	for _, o := range raw {
+		options = append(options, fmt.Sprint(o))
+	}
+	return options
+}
+
+// editSelectOptions applies --add-option and --remove-option to the current
+// options. Adding an option that is already present leaves it in place;
+// removing one that is not present is an error, as it is likely a typo.
+func editSelectOptions(current, add, remove []string) ([]string, error) {
+	options := slices.Clone(current)
+
+	for _, o := range remove {
+		i := slices.Index(options, o)
+		if i < 0 {
+			return nil, fmt.Errorf("option not found: %q (current options: %s)", o, strings.Join(current, ", "))
+		}
+		options = slices.Delete(options, i, i+1)
+	}
+	for _, o := range add {
+		if !slices.Contains(options, o) {
+			options = append(options, o)
+		}
+	}
+
+	return options, nil
 }
 
 func runHelpersDelete(cmd *cobra.Command, args []string) error {
@@ -535,7 +580,7 @@ func runHelpersDelete(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	wsClient, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	wsClient, err := newWSClient(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to connect to Home Assistant: %w", err)
 	}
@@ -545,10 +590,8 @@ func runHelpersDelete(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to delete helper: %w", err)
 	}
 
-	fmt.Printf("Helper deleted: %s\n", helperID)
-	fmt.Printf("\nNote: You may need to reload %s or restart Home Assistant for the change to take effect.\n", domain)
-
-	return nil
+	printSuccess("Helper deleted: %s", helperID)
+	return reloadOrNote(cfg, domain, fmt.Sprintf("You may need to reload %s or restart Home Assistant for the change to take effect.", domain))
 }
 
 func runHelpersRename(cmd *cobra.Command, args []string) error {
@@ -578,7 +621,7 @@ func runHelpersRename(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	wsClient, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	wsClient, err := newWSClient(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to connect to Home Assistant: %w", err)
 	}
@@ -598,9 +641,9 @@ func runHelpersRename(cmd *cobra.Command, args []string) error {
 	}
 
 	if helperNewEntityID != "" && helperNewEntityID != helperID {
-		fmt.Printf("Helper entity ID updated: %s -> %s\n", helperID, entity.EntityID)
+		printSuccess("Helper entity ID updated: %s -> %s", helperID, entity.EntityID)
 	} else {
-		fmt.Printf("Helper updated: %s\n", entity.EntityID)
+		printSuccess("Helper updated: %s", entity.EntityID)
 	}
 
 	if helperRenameName != "" {
@@ -608,7 +651,7 @@ func runHelpersRename(cmd *cobra.Command, args []string) error {
 		if entity.Name != nil && *entity.Name != "" {
 			newName = *entity.Name
 		}
-		fmt.Printf("New name: %s\n", newName)
+		printSuccess("New name: %s", newName)
 	}
 
 	return nil
@@ -624,7 +667,7 @@ func runHelpersToggleDisabled(helperID string, disable bool) error {
 		return err
 	}
 
-	wsClient, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	wsClient, err := newWSClient(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to connect to Home Assistant: %w", err)
 	}
@@ -655,7 +698,7 @@ func runHelpersToggleDisabled(helperID string, disable bool) error {
 		status = "disabled"
 	}
 
-	fmt.Printf("Helper %s: %s\n", status, entity.EntityID)
+	printSuccess("Helper %s: %s", status, entity.EntityID)
 	return nil
 }
 
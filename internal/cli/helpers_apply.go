@@ -0,0 +1,384 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var helpersApplyCmd = &cobra.Command{
+	Use:   "apply <manifest.yaml|manifest.json>",
+	Short: "Create helpers declared in a YAML manifest",
+	Long: `Create every helper declared in a manifest file that does not exist yet.
+
+A helper is considered to exist when a helper of the same type has the same
+name (case-insensitive) or the entity ID derived from the name. Existing
+helpers are skipped and never modified, so the command can be re-run safely.
+
+A helper that fails to be created is reported and the remaining ones are
+still created; the command then exits with an error.
+
+The manifest is YAML or JSON, either an object with a "helpers" list as
+below or just the list itself.
+
+Manifest format:
+  helpers:
+    - type: input_boolean        # or: boolean, button, number, select, text
+      name: Guest Mode
+      icon: mdi:account
+    - type: input_select
+      name: House Mode
+      options: [home, away, vacation]
+    - type: input_number
+      name: Volume
+      min: 0
+      max: 100
+      step: 5                    # optional, default 1
+      mode: slider               # optional: slider or box
+      initial: 20                # optional
+    - type: input_text
+      name: Note
+      max: 255                   # optional, default 100
+      mode: text                 # optional: text or password
+      pattern: "^[a-z]+$"        # optional
+    - type: input_button
+      name: Doorbell
+
+Examples:
+  hass-cli helpers apply helpers.yaml
+  hass-cli helpers apply helpers.json
+  hass-cli helpers apply helpers.yaml --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHelpersApply,
+}
+
+func init() {
+	helpersCmd.AddCommand(helpersApplyCmd)
+	addReloadFlag(helpersApplyCmd)
+}
+
+// HelperManifest is the top-level structure of a helpers manifest file.
+type HelperManifest struct {
+	Helpers []HelperSpec `yaml:"helpers"`
+}
+
+// HelperSpec declares a single helper in a manifest.
+type HelperSpec struct {
+	Type    string   `yaml:"type" json:"type"`
+	Name    string   `yaml:"name" json:"name"`
+	Icon    string   `yaml:"icon,omitempty" json:"icon,omitempty"`
+	Options []string `yaml:"options,omitempty" json:"options,omitempty"`
+	Min     *float64 `yaml:"min,omitempty" json:"min,omitempty"`
+	Max     *float64 `yaml:"max,omitempty" json:"max,omitempty"`
+	Step    *float64 `yaml:"step,omitempty" json:"step,omitempty"`
+	Initial *float64 `yaml:"initial,omitempty" json:"initial,omitempty"`
+	Mode    string   `yaml:"mode,omitempty" json:"mode,omitempty"`
+	Pattern string   `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+}
+
+// HelperApplyResult reports what apply did with one manifest entry.
+type HelperApplyResult struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	EntityID string `json:"entity_id"`
+	Status   string `json:"status"` // "created", "skipped" or "failed"
+	Error    string `json:"error,omitempty"`
+}
+
+// normalizeHelperType maps short helper type names to their domain.
+func normalizeHelperType(t string) string {
+	t = strings.ToLower(strings.TrimSpace(t))
+	if t != "" && !strings.HasPrefix(t, "input_") {
+		t = "input_" + t
+	}
+	return t
+}
+
+// validate normalizes the spec's type and checks the fields required for it.
+func (s *HelperSpec) validate() error {
+	s.Type = normalizeHelperType(s.Type)
+
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	switch s.Type {
+	case "input_boolean", "input_button":
+		// Only name and icon apply
+	case "input_select":
+		if len(s.Options) == 0 {
+			return fmt.Errorf("input_select requires at least one option")
+		}
+	case "input_number":
+		if s.Min == nil || s.Max == nil {
+			return fmt.Errorf("input_number requires min and max")
+		}
+		if *s.Min >= *s.Max {
+			return fmt.Errorf("input_number min must be less than max")
+		}
+		if s.Step != nil && *s.Step <= 0 {
+			return fmt.Errorf("input_number step must be positive")
+		}
+		if s.Mode != "" && s.Mode != "slider" && s.Mode != "box" {
+			return fmt.Errorf("input_number mode must be slider or box")
+		}
+	case "input_text":
+		if s.Mode != "" && s.Mode != "text" && s.Mode != "password" {
+			return fmt.Errorf("input_text mode must be text or password")
+		}
+		if s.Min != nil && s.Max != nil && *s.Min > *s.Max {
+			return fmt.Errorf("input_text min must not exceed max")
+		}
+	case "":
+		return fmt.Errorf("type is required")
+	default:
+		return fmt.Errorf("unsupported helper type: %s", s.Type)
+	}
+
+	return nil
+}
+
+// loadHelperManifest reads and validates a manifest file.
+func loadHelperManifest(path string) (*HelperManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	// JSON is valid YAML, so one decoder handles both formats
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	var manifest HelperManifest
+	if len(doc.Content) > 0 && doc.Content[0].Kind == yaml.SequenceNode {
+		// A bare list of helpers
+		if err := doc.Content[0].Decode(&manifest.Helpers); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest: %w", err)
+		}
+	} else if err := doc.Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	if len(manifest.Helpers) == 0 {
+		return nil, fmt.Errorf("manifest declares no helpers")
+	}
+
+	var problems []string
+	for i := range manifest.Helpers {
+		if err := manifest.Helpers[i].validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("  helpers[%d] (%s): %v", i, manifest.Helpers[i].Name, err))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid manifest:\n%s", strings.Join(problems, "\n"))
+	}
+
+	return &manifest, nil
+}
+
+// findExistingHelper returns the entity ID of an existing helper matching spec.
+func findExistingHelper(spec HelperSpec, states []api.State) (string, bool) {
+	slugID := spec.Type + "." + slugify(spec.Name)
+
+	for _, state := range states {
+		if !strings.HasPrefix(state.EntityID, spec.Type+".") {
+			continue
+		}
+		if state.EntityID == slugID {
+			return state.EntityID, true
+		}
+		if name, ok := state.Attributes["friendly_name"].(string); ok && strings.EqualFold(name, spec.Name) {
+			return state.EntityID, true
+		}
+	}
+
+	return "", false
+}
+
+// createHelper creates the helper described by spec.
+func createHelper(client *websocket.Client, spec HelperSpec) (*websocket.HelperItem, error) {
+	floatOr := func(v *float64, def float64) float64 {
+		if v != nil {
+			return *v
+		}
+		return def
+	}
+
+	switch spec.Type {
+	case "input_boolean":
+		return client.CreateInputBoolean(spec.Name, spec.Icon)
+	case "input_button":
+		return client.CreateInputButton(spec.Name, spec.Icon)
+	case "input_select":
+		return client.CreateInputSelect(spec.Name, spec.Options, spec.Icon)
+	case "input_number":
+		mode := spec.Mode
+		if mode == "" {
+			mode = "slider"
+		}
+		return client.CreateInputNumber(spec.Name, *spec.Min, *spec.Max, floatOr(spec.Step, 1), mode, spec.Icon, spec.Initial)
+	case "input_text":
+		mode := spec.Mode
+		if mode == "" {
+			mode = "text"
+		}
+		return client.CreateInputText(spec.Name, int(floatOr(spec.Min, 0)), int(floatOr(spec.Max, 100)), mode, spec.Pattern, spec.Icon)
+	default:
+		return nil, fmt.Errorf("unsupported helper type: %s", spec.Type)
+	}
+}
+
+// applyHelpers creates each helper in specs that has no match in states,
+// printing progress as it goes. A helper that the server refuses is recorded
+// as failed and the rest are still created; only losing the connection for
+// good stops the run.
+func applyHelpers(client *websocket.Client, specs []HelperSpec, states []api.State) ([]HelperApplyResult, error) {
+	var results []HelperApplyResult
+	lost := false // the last create lost the connection
+
+	err := runBulk(client, specs, func(spec HelperSpec) error {
+		if lost {
+			// The create may have reached the server before the connection
+			// dropped, so look again rather than creating a duplicate
+			fresh, err := refreshHelperStates(client, states)
+			if err != nil {
+				return err
+			}
+			states, lost = fresh, false
+		}
+
+		if entityID, ok := findExistingHelper(spec, states); ok {
+			results = append(results, HelperApplyResult{Type: spec.Type, Name: spec.Name, EntityID: entityID, Status: "skipped"})
+			if !jsonOutput {
+				printSuccess("Skipped  %s (already exists)", entityID)
+			}
+			return nil
+		}
+
+		helper, err := createHelper(client, spec)
+		if websocket.IsConnectionError(err) {
+			// Let runBulk reconnect and retry this helper
+			lost = true
+			return err
+		}
+		if err != nil {
+			results = append(results, HelperApplyResult{Type: spec.Type, Name: spec.Name, Status: "failed", Error: err.Error()})
+			if !jsonOutput {
+				printSuccess("Failed   %s %q: %v", spec.Type, spec.Name, err)
+			}
+			return nil
+		}
+
+		entityID := spec.Type + "." + helper.ID
+		// Match the new helper too, so a manifest listing it twice still
+		// creates it only once
+		states = append(states, api.State{EntityID: entityID, Attributes: map[string]interface{}{"friendly_name": spec.Name}})
+		results = append(results, HelperApplyResult{Type: spec.Type, Name: spec.Name, EntityID: entityID, Status: "created"})
+		if !jsonOutput {
+			printSuccess("Created  %s (%s)", entityID, spec.Name)
+		}
+		return nil
+	})
+
+	return results, err
+}
+
+// refreshHelperStates fetches the current states over client. The earlier
+// states are kept too, so helpers created in this run still match even if
+// the server does not list them yet.
+func refreshHelperStates(client *websocket.Client, states []api.State) ([]api.State, error) {
+	current, err := client.GetStates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh states: %w", err)
+	}
+
+	fresh := make([]api.State, 0, len(current)+len(states))
+	for _, state := range current {
+		fresh = append(fresh, api.State{EntityID: state.EntityID, State: state.State, Attributes: state.Attributes})
+	}
+	return append(fresh, states...), nil
+}
+
+func runHelpersApply(cmd *cobra.Command, args []string) error {
+	manifest, err := loadHelperManifest(args[0])
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	restClient := newRESTClient(cfg)
+
+	printInfo("Fetching existing helpers...")
+	states, err := restClient.GetStates()
+	if err != nil {
+		return fmt.Errorf("failed to get states: %w", err)
+	}
+
+	wsClient, err := newWSClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Home Assistant: %w", err)
+	}
+	defer wsClient.Close()
+
+	results, err := applyHelpers(wsClient, manifest.Helpers, states)
+	if err != nil {
+		return err
+	}
+
+	created, failed := 0, 0
+	for _, r := range results {
+		switch r.Status {
+		case "created":
+			created++
+		case "failed":
+			failed++
+		}
+	}
+
+	var failedErr error
+	if failed > 0 {
+		failedErr = fmt.Errorf("failed to create %d of %d helpers", failed, len(results))
+	}
+
+	if jsonOutput {
+		if err := outputJSON(cmd.OutOrStdout(), results); err != nil {
+			return err
+		}
+		return failedErr
+	}
+
+	printFooter(os.Stderr, "Created: %d, Skipped: %d, Failed: %d", created, len(results)-created-failed, failed)
+	if created == 0 {
+		return failedErr
+	}
+	if !reloadAfterChange {
+		printNote("You may need to reload the helper integrations or restart Home Assistant for new helpers to appear.")
+		return failedErr
+	}
+
+	var domains []string
+	for _, r := range results {
+		if r.Status == "created" && !slices.Contains(domains, r.Type) {
+			domains = append(domains, r.Type)
+		}
+	}
+	for _, domain := range domains {
+		if err := reloadOrNote(cfg, domain, ""); err != nil {
+			return err
+		}
+	}
+
+	return failedErr
+}
@@ -0,0 +1,267 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/dorinclisu/hass-cli/internal/testutil"
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+)
+
+func floatPtr(f float64) *float64 { return &f }
+
+func TestHelperSpecValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     HelperSpec
+		wantType string
+		wantErr  bool
+	}{
+		{name: "boolean short type", spec: HelperSpec{Type: "boolean", Name: "Guest Mode"}, wantType: "input_boolean"},
+		{name: "button", spec: HelperSpec{Type: "input_button", Name: "Doorbell"}, wantType: "input_button"},
+		{name: "select with options", spec: HelperSpec{Type: "select", Name: "Mode", Options: []string{"a"}}, wantType: "input_select"},
+		{name: "select without options", spec: HelperSpec{Type: "select", Name: "Mode"}, wantErr: true},
+		{name: "number", spec: HelperSpec{Type: "number", Name: "Vol", Min: floatPtr(0), Max: floatPtr(10)}, wantType: "input_number"},
+		{name: "number missing max", spec: HelperSpec{Type: "number", Name: "Vol", Min: floatPtr(0)}, wantErr: true},
+		{name: "number min above max", spec: HelperSpec{Type: "number", Name: "Vol", Min: floatPtr(5), Max: floatPtr(1)}, wantErr: true},
+		{name: "number bad mode", spec: HelperSpec{Type: "number", Name: "Vol", Min: floatPtr(0), Max: floatPtr(1), Mode: "dial"}, wantErr: true},
+		{name: "text", spec: HelperSpec{Type: "text", Name: "Note"}, wantType: "input_text"},
+		{name: "text bad mode", spec: HelperSpec{Type: "text", Name: "Note", Mode: "secret"}, wantErr: true},
+		{name: "missing name", spec: HelperSpec{Type: "boolean"}, wantErr: true},
+		{name: "missing type", spec: HelperSpec{Name: "X"}, wantErr: true},
+		{name: "unknown type", spec: HelperSpec{Type: "datetime_range", Name: "X"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := tt.spec
+			err := spec.validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("validate() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validate() error = %v", err)
+			}
+			if spec.Type != tt.wantType {
+				t.Errorf("Type = %q, want %q", spec.Type, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestLoadHelperManifest(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.yaml")
+	os.WriteFile(valid, []byte(`helpers:
+  - type: boolean
+    name: Guest Mode
+  - type: input_select
+    name: House Mode
+    options: [home, away]
+`), 0600)
+
+	manifest, err := loadHelperManifest(valid)
+	if err != nil {
+		t.Fatalf("loadHelperManifest() error = %v", err)
+	}
+	if len(manifest.Helpers) != 2 {
+		t.Fatalf("got %d helpers, want 2", len(manifest.Helpers))
+	}
+	if manifest.Helpers[0].Type != "input_boolean" {
+		t.Errorf("helpers[0].Type = %q, want %q", manifest.Helpers[0].Type, "input_boolean")
+	}
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	os.WriteFile(invalid, []byte(`helpers:
+  - type: select
+    name: No Options
+  - type: number
+    name: No Range
+`), 0600)
+
+	_, err = loadHelperManifest(invalid)
+	if err == nil {
+		t.Fatal("loadHelperManifest() expected error")
+	}
+	for _, want := range []string{"helpers[0]", "helpers[1]"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
+
+func TestLoadHelperManifest_JSONList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "helpers.json")
+	os.WriteFile(path, []byte(`[
+  {"type": "button", "name": "Doorbell"},
+  {"type": "input_number", "name": "Volume", "min": 0, "max": 100, "step": 5}
+]`), 0600)
+
+	manifest, err := loadHelperManifest(path)
+	if err != nil {
+		t.Fatalf("loadHelperManifest() error = %v", err)
+	}
+	if len(manifest.Helpers) != 2 {
+		t.Fatalf("got %d helpers, want 2", len(manifest.Helpers))
+	}
+	if h := manifest.Helpers[1]; h.Type != "input_number" || h.Step == nil || *h.Step != 5 {
+		t.Errorf("helpers[1] = %+v", h)
+	}
+}
+
+func TestApplyHelpers_ContinuesAfterFailure(t *testing.T) {
+	mock := testutil.NewWSMock(t, testToken)
+	mock.Handle("input_boolean/create", func(msg map[string]interface{}) (interface{}, error) {
+		if msg["name"] == "Broken" {
+			return nil, errors.New("name already in use")
+		}
+		return map[string]interface{}{"id": strings.ToLower(strings.ReplaceAll(msg["name"].(string), " ", "_"))}, nil
+	})
+
+	client, err := websocket.NewClient(mock.URL(), testToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	specs := []HelperSpec{
+		{Type: "input_boolean", Name: "Broken"},
+		{Type: "input_boolean", Name: "Guest Mode"},
+		{Type: "input_boolean", Name: "Away"},
+	}
+	states := []api.State{{EntityID: "input_boolean.away"}}
+
+	results, err := applyHelpers(client, specs, states)
+	if err != nil {
+		t.Fatalf("applyHelpers() error = %v", err)
+	}
+
+	var got []string
+	for _, r := range results {
+		got = append(got, r.Name+":"+r.Status)
+	}
+	if want := "Broken:failed,Guest Mode:created,Away:skipped"; strings.Join(got, ",") != want {
+		t.Errorf("results = %v, want %s", got, want)
+	}
+	if !strings.Contains(results[0].Error, "name already in use") {
+		t.Errorf("results[0].Error = %q", results[0].Error)
+	}
+	if results[1].EntityID != "input_boolean.guest_mode" {
+		t.Errorf("results[1].EntityID = %q", results[1].EntityID)
+	}
+}
+
+func TestApplyHelpers_DuplicateSpec(t *testing.T) {
+	mock := testutil.NewWSMock(t, testToken)
+	creates := 0
+	mock.Handle("input_boolean/create", func(msg map[string]interface{}) (interface{}, error) {
+		creates++
+		id := "guest_mode"
+		if creates > 1 {
+			id = "guest_mode_2"
+		}
+		return map[string]interface{}{"id": id}, nil
+	})
+
+	client, err := websocket.NewClient(mock.URL(), testToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	specs := []HelperSpec{
+		{Type: "input_boolean", Name: "Guest Mode"},
+		{Type: "input_boolean", Name: "guest mode"},
+	}
+
+	results, err := applyHelpers(client, specs, nil)
+	if err != nil {
+		t.Fatalf("applyHelpers() error = %v", err)
+	}
+	if creates != 1 {
+		t.Errorf("created %d helpers, want 1", creates)
+	}
+	if len(results) != 2 || results[1].Status != "skipped" || results[1].EntityID != "input_boolean.guest_mode" {
+		t.Errorf("results = %+v, want the second entry skipped as input_boolean.guest_mode", results)
+	}
+}
+
+func TestApplyHelpers_DropAfterCreate(t *testing.T) {
+	mock := testutil.NewWSMock(t, testToken)
+	// Each connection is served by its own goroutine, so count atomically
+	var creates atomic.Int32
+	mock.Handle("input_boolean/create", func(msg map[string]interface{}) (interface{}, error) {
+		if creates.Add(1) == 1 {
+			// The helper is created, but the answer never arrives
+			return nil, testutil.ErrDropConnection
+		}
+		return map[string]interface{}{"id": "guest_mode_2"}, nil
+	})
+	mock.Handle("get_states", func(msg map[string]interface{}) (interface{}, error) {
+		var states []interface{}
+		if creates.Load() > 0 {
+			states = append(states, map[string]interface{}{
+				"entity_id":  "input_boolean.guest_mode",
+				"state":      "off",
+				"attributes": map[string]interface{}{"friendly_name": "Guest Mode"},
+			})
+		}
+		return states, nil
+	})
+
+	client, err := websocket.NewClient(mock.URL(), testToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	results, err := applyHelpers(client, []HelperSpec{{Type: "input_boolean", Name: "Guest Mode"}}, nil)
+	if err != nil {
+		t.Fatalf("applyHelpers() error = %v", err)
+	}
+	if n := creates.Load(); n != 1 {
+		t.Errorf("created %d helpers, want 1", n)
+	}
+	if len(results) != 1 || results[0].Status != "skipped" || results[0].EntityID != "input_boolean.guest_mode" {
+		t.Errorf("results = %+v, want input_boolean.guest_mode skipped", results)
+	}
+}
+
+func TestFindExistingHelper(t *testing.T) {
+	states := []api.State{
+		{EntityID: "input_boolean.guest_mode", Attributes: map[string]interface{}{"friendly_name": "Guest Mode"}},
+		{EntityID: "input_select.mode_2", Attributes: map[string]interface{}{"friendly_name": "House Mode"}},
+		{EntityID: "light.guest_mode", Attributes: map[string]interface{}{"friendly_name": "Night Light"}},
+	}
+
+	tests := []struct {
+		name   string
+		spec   HelperSpec
+		wantID string
+		want   bool
+	}{
+		{"match by entity ID", HelperSpec{Type: "input_boolean", Name: "guest mode!"}, "input_boolean.guest_mode", true},
+		{"match by friendly name", HelperSpec{Type: "input_select", Name: "house mode"}, "input_select.mode_2", true},
+		{"same name other type", HelperSpec{Type: "input_button", Name: "Guest Mode"}, "", false},
+		{"other domain ignored", HelperSpec{Type: "input_boolean", Name: "Night Light"}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotID, got := findExistingHelper(tt.spec, states)
+			if got != tt.want || gotID != tt.wantID {
+				t.Errorf("findExistingHelper() = (%q, %v), want (%q, %v)", gotID, got, tt.wantID, tt.want)
+			}
+		})
+	}
+}
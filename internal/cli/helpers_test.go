@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+)
+
+func TestEditSelectOptions(t *testing.T) {
+	current := []string{"home", "away", "vacation"}
+
+	tests := []struct {
+		name    string
+		add     []string
+		remove  []string
+		want    string
+		wantErr bool
+	}{
+		{name: "add", add: []string{"guest"}, want: "home,away,vacation,guest"},
+		{name: "add existing", add: []string{"away"}, want: "home,away,vacation"},
+		{name: "remove", remove: []string{"away"}, want: "home,vacation"},
+		{name: "remove and add", remove: []string{"vacation"}, add: []string{"vacation", "night"}, want: "home,away,vacation,night"},
+		{name: "remove missing", remove: []string{"Away"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := editSelectOptions(current, tt.add, tt.remove)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("editSelectOptions() = %v, expected error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("editSelectOptions() error = %v", err)
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("editSelectOptions() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if strings.Join(current, ",") != "home,away,vacation" {
+		t.Errorf("editSelectOptions() modified the current options: %v", current)
+	}
+}
+
+func TestStateOptions(t *testing.T) {
+	state := &api.State{Attributes: map[string]interface{}{"options": []interface{}{"low", "high"}}}
+	if got := stateOptions(state); strings.Join(got, ",") != "low,high" {
+		t.Errorf("stateOptions() = %v, want [low high]", got)
+	}
+	if got := stateOptions(&api.State{}); len(got) != 0 {
+		t.Errorf("stateOptions() without options = %v, want none", got)
+	}
+}
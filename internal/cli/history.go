@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyStart string
+	historyEnd   string
+	historyDays  float64
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history <entity_id> [entity_id...]",
+	Short: "Show the state history of entities",
+	Long: `Show the state changes of one or more entities over a period of time.
+
+The period defaults to the last 24 hours. --start and --end accept RFC3339
+timestamps or local dates and times ("2024-01-15", "2024-01-15 08:00");
+--days sets the length of the period ending at --end (default: now).
+
+Examples:
+  hass-cli history light.kitchen                              # Last 24 hours
+  hass-cli history sensor.temperature --days 7
+  hass-cli history light.kitchen light.hall --start 2024-01-15 --end 2024-01-16
+  hass-cli history binary_sensor.door --start "2024-01-15 08:00" --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runHistory,
+}
+
+func init() {
+	historyCmd.Flags().StringVar(&historyStart, "start", "", "Start of the period (default: 24 hours before --end)")
+	historyCmd.Flags().StringVar(&historyEnd, "end", "", "End of the period (default: now)")
+	historyCmd.Flags().Float64Var(&historyDays, "days", 0, "Length of the period in days, ending at --end")
+
+	rootCmd.AddCommand(historyCmd)
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	start, end, err := timeRange(historyStart, historyEnd, historyDays, time.Now())
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	printInfo("Fetching history from %s to %s...", displayTime(start).Format(time.RFC3339), displayTime(end).Format(time.RFC3339))
+	history, err := client.GetHistory(args, start, end)
+	if err != nil {
+		if api.IsNotFound(err) {
+			return fmt.Errorf("history is not available (is the history integration enabled?)")
+		}
+		return fmt.Errorf("failed to get history: %w", err)
+	}
+
+	// Home Assistant leaves out unknown entities instead of failing
+	found := make(map[string]bool)
+	for _, states := range history {
+		found[states[0].EntityID] = true
+	}
+	for _, entityID := range args {
+		if !found[entityID] {
+			fmt.Fprintf(os.Stderr, "Warning: no history for %s (unknown entity?)\n", entityID)
+		}
+	}
+
+	return outputData(cmd.OutOrStdout(), history, historyTable(history))
+}
+
+func historyTable(history [][]api.State) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "ENTITY ID"}, {Header: "CHANGED"}, {Header: "STATE", Width: 30}},
+		Noun:    "state changes",
+		Empty:   "No history found",
+	}
+
+	for _, states := range history {
+		for _, s := range states {
+			t.addRow(
+				s.EntityID,
+				formatTime(s.LastChanged),
+				s.State,
+			)
+		}
+	}
+
+	return t
+}
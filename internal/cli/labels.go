@@ -0,0 +1,280 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+	"github.com/spf13/cobra"
+)
+
+var labelsCmd = &cobra.Command{
+	Use:   "labels",
+	Short: "List all labels",
+	Long: `List the labels defined in Home Assistant.
+
+Labels tag entities, devices and areas. Use 'hass-cli entities --label' and
+'hass-cli devices --label' to list what carries a label.
+
+Examples:
+  hass-cli labels                                    # List all labels
+  hass-cli labels --json                             # Output as JSON
+  hass-cli labels assign battery -e sensor.door_battery
+  hass-cli labels unassign battery --device 3f2a`,
+	Args: cobra.NoArgs,
+	RunE: runLabels,
+}
+
+var labelsAssignCmd = &cobra.Command{
+	Use:   "assign <label> --entity <entity_id> | --device <device_id>",
+	Short: "Add a label to entities or devices",
+	Long: `Add a label, given by ID or name, to entities and devices.
+
+Labels already on an entity or device are kept. Devices can be given by a
+unique prefix of their ID.
+
+Examples:
+  hass-cli labels assign battery -e sensor.door_battery -e sensor.window_battery
+  hass-cli labels assign "Needs Attention" --device 3f2a`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLabelsAssign,
+}
+
+var labelsUnassignCmd = &cobra.Command{
+	Use:   "unassign <label> --entity <entity_id> | --device <device_id>",
+	Short: "Remove a label from entities or devices",
+	Long: `Remove a label, given by ID or name, from entities and devices. Their
+other labels are kept.
+
+Examples:
+  hass-cli labels unassign battery -e sensor.door_battery
+  hass-cli labels unassign "Needs Attention" --device 3f2a`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLabelsUnassign,
+}
+
+var (
+	labelEntities []string
+	labelDevices  []string
+)
+
+func init() {
+	rootCmd.AddCommand(labelsCmd)
+	labelsCmd.AddCommand(labelsAssignCmd)
+	labelsCmd.AddCommand(labelsUnassignCmd)
+
+	for _, cmd := range []*cobra.Command{labelsAssignCmd, labelsUnassignCmd} {
+		cmd.Flags().StringArrayVarP(&labelEntities, "entity", "e", nil, "Entity ID (repeatable)")
+		cmd.Flags().StringArrayVarP(&labelDevices, "device", "D", nil, "Device ID or unique prefix (repeatable)")
+	}
+}
+
+func runLabels(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	return withWSClient(cfg, func(client *websocket.Client) error {
+		printInfo("Fetching labels...")
+		labels, err := client.GetLabels()
+		if err != nil {
+			return fmt.Errorf("failed to get labels: %w", err)
+		}
+
+		sort.Slice(labels, func(i, j int) bool {
+			return strings.ToLower(labels[i].Name) < strings.ToLower(labels[j].Name)
+		})
+
+		return outputData(cmd.OutOrStdout(), labels, labelsTable(labels))
+	})
+}
+
+func labelsTable(labels []websocket.Label) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "LABEL ID"}, {Header: "NAME"}, {Header: "COLOR"}, {Header: "ICON"}},
+		Noun:    "labels",
+		Empty:   "No labels found",
+	}
+
+	orDash := func(s *string) string {
+		if s == nil || *s == "" {
+			return "-"
+		}
+		return *s
+	}
+
+	for _, l := range labels {
+		t.addRow(
+			l.LabelID,
+			l.Name,
+			orDash(l.Color),
+			orDash(l.Icon),
+		)
+	}
+
+	return t
+}
+
+// fetchLabelIDs resolves the --label values of a listing to label IDs. It
+// returns nil without a round trip when no labels were given.
+func fetchLabelIDs(client *websocket.Client, args []string) ([]string, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	printInfo("Fetching labels...")
+	labels, err := client.GetLabels()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels: %w", err)
+	}
+	return resolveLabelIDs(labels, args)
+}
+
+// resolveLabelIDs maps each of args, a label ID or name (case-insensitive),
+// to its label ID.
+func resolveLabelIDs(labels []websocket.Label, args []string) ([]string, error) {
+	ids := make([]string, 0, len(args))
+	for _, arg := range args {
+		found := ""
+		for _, l := range labels {
+			if l.LabelID == arg || strings.EqualFold(l.Name, arg) {
+				found = l.LabelID
+				break
+			}
+		}
+		if found == "" {
+			return nil, fmt.Errorf("label not found: %s (see 'hass-cli labels')", arg)
+		}
+		ids = append(ids, found)
+	}
+	return ids, nil
+}
+
+// hasAnyLabel reports whether labels contains one of want. No wanted labels
+// matches everything.
+func hasAnyLabel(labels, want []string) bool {
+	if len(want) == 0 {
+		return true
+	}
+	for _, w := range want {
+		if slices.Contains(labels, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// withLabel returns labels with id added, and whether it was missing.
+func withLabel(labels []string, id string) ([]string, bool) {
+	if slices.Contains(labels, id) {
+		return labels, false
+	}
+	return append(slices.Clone(labels), id), true
+}
+
+// withoutLabel returns labels with id removed, and whether it was present.
+// The result is never nil, so it clears the labels when sent as an update.
+func withoutLabel(labels []string, id string) ([]string, bool) {
+	out := []string{}
+	for _, l := range labels {
+		if l != id {
+			out = append(out, l)
+		}
+	}
+	return out, len(out) != len(labels)
+}
+
+func runLabelsAssign(cmd *cobra.Command, args []string) error {
+	return runLabelChange(args[0], true)
+}
+
+func runLabelsUnassign(cmd *cobra.Command, args []string) error {
+	return runLabelChange(args[0], false)
+}
+
+func runLabelChange(label string, assign bool) error {
+	if len(labelEntities) == 0 && len(labelDevices) == 0 {
+		return fmt.Errorf("specify at least one --entity or --device")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	return withWSClient(cfg, func(client *websocket.Client) error {
+		ids, err := fetchLabelIDs(client, []string{label})
+		if err != nil {
+			return err
+		}
+		return changeLabel(client, ids[0], labelEntities, labelDevices, assign)
+	})
+}
+
+// changeLabel adds labelID to, or with assign unset removes it from, each
+// entity and device. The registries replace the whole labels list on update,
+// so every item's current labels are read first and sent back with the
+// change.
+func changeLabel(client *websocket.Client, labelID string, entityIDs, deviceIDs []string, assign bool) error {
+	change, verb := withLabel, "Added"
+	if !assign {
+		change, verb = withoutLabel, "Removed"
+	}
+
+	for _, entityID := range entityIDs {
+		printInfo("Fetching entity %s...", entityID)
+		entity, err := client.GetEntity(entityID)
+		if err != nil {
+			return fmt.Errorf("failed to get entity %s: %w", entityID, err)
+		}
+
+		labels, changed := change(entity.Labels, labelID)
+		if !changed {
+			printSuccess("Unchanged %s (label %s %s)", entityID, labelID, labelState(assign))
+			continue
+		}
+		if _, err := client.UpdateEntity(entityID, map[string]interface{}{"labels": labels}); err != nil {
+			return fmt.Errorf("failed to update entity %s: %w", entityID, err)
+		}
+		printSuccess("%s label %s: %s", verb, labelID, entityID)
+	}
+
+	if len(deviceIDs) == 0 {
+		return nil
+	}
+
+	printInfo("Fetching devices...")
+	devices, err := client.GetDevices()
+	if err != nil {
+		return fmt.Errorf("failed to get devices: %w", err)
+	}
+	for _, deviceID := range deviceIDs {
+		device, err := matchDevice(devices, deviceID)
+		if err != nil {
+			return err
+		}
+
+		labels, changed := change(device.Labels, labelID)
+		if !changed {
+			printSuccess("Unchanged %s (label %s %s)", device.DisplayName(), labelID, labelState(assign))
+			continue
+		}
+		if _, err := client.UpdateDevice(device.ID, map[string]interface{}{"labels": labels}); err != nil {
+			return fmt.Errorf("failed to update device %s: %w", device.ID, err)
+		}
+		printSuccess("%s label %s: %s (%s)", verb, labelID, device.ID, device.DisplayName())
+	}
+
+	return nil
+}
+
+// labelState describes why a label change had nothing to do.
+func labelState(assign bool) string {
+	if assign {
+		return "already assigned"
+	}
+	return "not assigned"
+}
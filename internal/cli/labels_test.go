@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dorinclisu/hass-cli/internal/testutil"
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+)
+
+func TestResolveLabelIDs(t *testing.T) {
+	labels := []websocket.Label{
+		{LabelID: "battery", Name: "Battery"},
+		{LabelID: "needs_attention", Name: "Needs Attention"},
+	}
+
+	got, err := resolveLabelIDs(labels, []string{"battery", "needs attention"})
+	if err != nil {
+		t.Fatalf("resolveLabelIDs() error = %v", err)
+	}
+	if strings.Join(got, ",") != "battery,needs_attention" {
+		t.Errorf("resolveLabelIDs() = %v, want [battery needs_attention]", got)
+	}
+
+	if _, err := resolveLabelIDs(labels, []string{"outdoor"}); err == nil || !strings.Contains(err.Error(), "label not found: outdoor") {
+		t.Errorf("resolveLabelIDs(outdoor) error = %v, want label not found", err)
+	}
+}
+
+func TestHasAnyLabel(t *testing.T) {
+	tests := []struct {
+		labels, want []string
+		match        bool
+	}{
+		{labels: nil, want: nil, match: true},
+		{labels: []string{"battery"}, want: nil, match: true},
+		{labels: nil, want: []string{"battery"}, match: false},
+		{labels: []string{"outdoor", "battery"}, want: []string{"battery"}, match: true},
+		{labels: []string{"outdoor"}, want: []string{"battery", "outdoor"}, match: true},
+		{labels: []string{"outdoor"}, want: []string{"battery"}, match: false},
+	}
+
+	for _, tt := range tests {
+		if got := hasAnyLabel(tt.labels, tt.want); got != tt.match {
+			t.Errorf("hasAnyLabel(%v, %v) = %v, want %v", tt.labels, tt.want, got, tt.match)
+		}
+	}
+}
+
+func TestWithLabel(t *testing.T) {
+	current := []string{"outdoor"}
+
+	got, changed := withLabel(current, "battery")
+	if !changed || strings.Join(got, ",") != "outdoor,battery" {
+		t.Errorf("withLabel(battery) = %v, %v", got, changed)
+	}
+	if len(current) != 1 {
+		t.Errorf("withLabel() modified its input: %v", current)
+	}
+	if got, changed := withLabel(current, "outdoor"); changed || len(got) != 1 {
+		t.Errorf("withLabel(outdoor) = %v, %v, want unchanged", got, changed)
+	}
+
+	got, changed = withoutLabel([]string{"outdoor", "battery"}, "outdoor")
+	if !changed || strings.Join(got, ",") != "battery" {
+		t.Errorf("withoutLabel(outdoor) = %v, %v", got, changed)
+	}
+	if got, changed := withoutLabel([]string{"battery"}, "battery"); !changed || got == nil || len(got) != 0 {
+		t.Errorf("withoutLabel(last label) = %#v, %v, want empty non-nil slice", got, changed)
+	}
+	if _, changed := withoutLabel(nil, "battery"); changed {
+		t.Error("withoutLabel(nil) reported a change")
+	}
+}
+
+func TestChangeLabel_KeepsOtherLabels(t *testing.T) {
+	var mu sync.Mutex
+	updates := make(map[string][]string)
+	record := func(key string, msg map[string]interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		var labels []string
+		for _, l := range msg["labels"].([]interface{}) {
+			labels = append(labels, l.(string))
+		}
+		updates[key] = labels
+	}
+
+	mock := testutil.NewWSMock(t, testToken)
+	mock.Handle("config/entity_registry/get", func(msg map[string]interface{}) (interface{}, error) {
+		switch msg["entity_id"] {
+		case "sensor.door":
+			return map[string]interface{}{"entity_id": "sensor.door", "labels": []string{"outdoor"}}, nil
+		case "sensor.tagged":
+			return map[string]interface{}{"entity_id": "sensor.tagged", "labels": []string{"battery"}}, nil
+		}
+		return nil, &testutil.WSError{Code: "not_found", Message: "Entity not found"}
+	})
+	mock.Handle("config/entity_registry/update", func(msg map[string]interface{}) (interface{}, error) {
+		record(msg["entity_id"].(string), msg)
+		return map[string]interface{}{"entity_entry": map[string]interface{}{"entity_id": msg["entity_id"]}}, nil
+	})
+	mock.Handle("config/device_registry/list", func(msg map[string]interface{}) (interface{}, error) {
+		return []map[string]interface{}{{"id": "dev123", "name": "Hub", "labels": []string{"battery", "attic"}}}, nil
+	})
+	mock.Handle("config/device_registry/update", func(msg map[string]interface{}) (interface{}, error) {
+		record(msg["device_id"].(string), msg)
+		return map[string]interface{}{"id": msg["device_id"]}, nil
+	})
+
+	client, err := websocket.NewClient(mock.URL(), testToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	if err := changeLabel(client, "battery", []string{"sensor.door", "sensor.tagged"}, nil, true); err != nil {
+		t.Fatalf("changeLabel(assign) error = %v", err)
+	}
+	if got := updates["sensor.door"]; !slices.Equal(got, []string{"outdoor", "battery"}) {
+		t.Errorf("sensor.door labels = %v, want [outdoor battery]", got)
+	}
+	if _, ok := updates["sensor.tagged"]; ok {
+		t.Error("sensor.tagged was updated although it already had the label")
+	}
+
+	if err := changeLabel(client, "battery", nil, []string{"dev1"}, false); err != nil {
+		t.Fatalf("changeLabel(unassign) error = %v", err)
+	}
+	if got := updates["dev123"]; !slices.Equal(got, []string{"attic"}) {
+		t.Errorf("dev123 labels = %v, want [attic]", got)
+	}
+
+	if err := changeLabel(client, "battery", []string{"sensor.missing"}, nil, true); err == nil {
+		t.Error("changeLabel() with a missing entity succeeded")
+	}
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logbookStart string
+	logbookEnd   string
+	logbookDays  float64
+)
+
+var logbookCmd = &cobra.Command{
+	Use:   "logbook [entity_id]",
+	Short: "Show the logbook",
+	Long: `Show logbook entries, like the Logbook panel of the Home Assistant frontend.
+
+The period defaults to the last 24 hours and accepts the same --start, --end
+and --days flags as 'hass-cli history'.
+
+Examples:
+  hass-cli logbook                             # Everything in the last 24 hours
+  hass-cli logbook light.kitchen               # One entity
+  hass-cli logbook --days 7
+  hass-cli logbook binary_sensor.door --start 2024-01-15 --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLogbook,
+}
+
+func init() {
+	logbookCmd.Flags().StringVar(&logbookStart, "start", "", "Start of the period (default: 24 hours before --end)")
+	logbookCmd.Flags().StringVar(&logbookEnd, "end", "", "End of the period (default: now)")
+	logbookCmd.Flags().Float64Var(&logbookDays, "days", 0, "Length of the period in days, ending at --end")
+
+	rootCmd.AddCommand(logbookCmd)
+}
+
+func runLogbook(cmd *cobra.Command, args []string) error {
+	start, end, err := timeRange(logbookStart, logbookEnd, logbookDays, time.Now())
+	if err != nil {
+		return err
+	}
+
+	entityID := ""
+	if len(args) > 0 {
+		entityID = args[0]
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	printInfo("Fetching logbook...")
+	entries, err := client.GetLogbook(start, end, entityID)
+	if err != nil {
+		if api.IsNotFound(err) {
+			return fmt.Errorf("logbook is not available (is the logbook integration enabled?)")
+		}
+		return fmt.Errorf("failed to get logbook: %w", err)
+	}
+
+	return outputData(cmd.OutOrStdout(), entries, logbookTable(entries))
+}
+
+func logbookTable(entries []api.LogbookEntry) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "TIME"}, {Header: "NAME", Width: 30}, {Header: "MESSAGE", Width: 50}},
+		Noun:    "entries",
+		Empty:   "No logbook entries found",
+	}
+
+	for _, e := range entries {
+		t.addRow(
+			formatTime(e.When),
+			e.Name,
+			e.Message,
+		)
+	}
+
+	return t
+}
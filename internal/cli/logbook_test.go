@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+)
+
+func TestLogbookTable(t *testing.T) {
+	displayLocation = time.UTC
+	defer func() { displayLocation = time.Local }()
+
+	entries := []api.LogbookEntry{
+		{When: "2024-01-15T18:30:00.123456+00:00", Name: "Kitchen", Message: "turned on", EntityID: "light.kitchen"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeTable(&buf, logbookTable(entries)); err != nil {
+		t.Fatalf("writeTable() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{"2024-01-15 18:30:00", "Kitchen", "turned on", "Total: 1 entries"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestLogbookTable_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeTable(&buf, logbookTable(nil)); err != nil {
+		t.Fatalf("writeTable() error = %v", err)
+	}
+	if got := buf.String(); got != "No logbook entries found\n" {
+		t.Errorf("output = %q, want %q", got, "No logbook entries found\n")
+	}
+}
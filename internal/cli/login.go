@@ -77,7 +77,7 @@ func runLogin(cmd *cobra.Command, args []string) error {
 
 	// Test the connection
 	printInfo("Testing connection to %s...", url)
-	client := api.NewClient(url, tkn, time.Duration(timeout)*time.Second)
+	client := api.NewClientWithOptions(url, tkn, time.Duration(timeout)*time.Second, restOptions()...)
 	if err := client.CheckConnection(); err != nil {
 		if api.IsUnauthorized(err) {
 			return fmt.Errorf("authentication failed: invalid token")
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// parsePatterns lowercases entity patterns and checks that each is a valid
+// glob, so a typo is reported instead of silently matching nothing.
+func parsePatterns(args []string) ([]string, error) {
+	patterns := make([]string, 0, len(args))
+	for _, arg := range args {
+		pattern := strings.ToLower(arg)
+		if _, err := path.Match(globSafe(pattern), ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", arg, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// matchesPatterns checks if an entity ID matches any of the patterns, which
+// must already be lowercase. Patterns are globs: * matches any run of
+// characters, ? a single character and [...] a character class.
+func matchesPatterns(entityID string, patterns []string) bool {
+	entityLower := strings.ToLower(entityID)
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(globSafe(pattern), globSafe(entityLower)); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// globSafe hides slashes from path.Match, whose * and ? never match "/".
+// Pattern and name are rewritten alike, so a literal "/" in a pattern still
+// matches one in a name such as "Lounge/TV".
+func globSafe(s string) string {
+	return strings.ReplaceAll(s, "/", "\x00")
+}
+
+// closestEntityID returns the entity ID in known nearest to entityID by edit
+// distance, for "did you mean" hints. Nothing is suggested when even the
+// closest ID differs in more than a third of entityID's characters.
+func closestEntityID(entityID string, known []string) (string, bool) {
+	maxDistance := len(entityID) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	best, bestDistance := "", maxDistance+1
+	for _, id := range known {
+		if d := editDistance(strings.ToLower(entityID), strings.ToLower(id)); d < bestDistance || (d == bestDistance && id < best) {
+			best, bestDistance = id, d
+		}
+	}
+	return best, bestDistance <= maxDistance
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
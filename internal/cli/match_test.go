@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestMatchesPatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		entityID string
+		patterns []string
+		want     bool
+	}{
+		{
+			name:     "exact match",
+			entityID: "light.living_room",
+			patterns: []string{"light.living_room"},
+			want:     true,
+		},
+		{
+			name:     "no match",
+			entityID: "light.living_room",
+			patterns: []string{"light.bedroom"},
+			want:     false,
+		},
+		{
+			name:     "wildcard prefix match",
+			entityID: "light.living_room",
+			patterns: []string{"light.*"},
+			want:     true,
+		},
+		{
+			name:     "wildcard no match",
+			entityID: "sensor.temperature",
+			patterns: []string{"light.*"},
+			want:     false,
+		},
+		{
+			name:     "multiple patterns first matches",
+			entityID: "light.living_room",
+			patterns: []string{"light.*", "sensor.*"},
+			want:     true,
+		},
+		{
+			name:     "multiple patterns second matches",
+			entityID: "sensor.temperature",
+			patterns: []string{"light.*", "sensor.*"},
+			want:     true,
+		},
+		{
+			name:     "multiple patterns none match",
+			entityID: "switch.outlet",
+			patterns: []string{"light.*", "sensor.*"},
+			want:     false,
+		},
+		{
+			name:     "case insensitive",
+			entityID: "Light.Living_Room",
+			patterns: []string{"light.living_room"},
+			want:     true,
+		},
+		{
+			name:     "empty patterns",
+			entityID: "light.living_room",
+			patterns: []string{},
+			want:     false,
+		},
+		{
+			name:     "wildcard all match",
+			entityID: "anything.at_all",
+			patterns: []string{"*"},
+			want:     true,
+		},
+		{
+			name:     "partial domain wildcard",
+			entityID: "light.living_room",
+			patterns: []string{"li*"},
+			want:     true,
+		},
+		{
+			name:     "wildcard on both sides",
+			entityID: "sensor.kitchen_temperature_2",
+			patterns: []string{"*temperature*"},
+			want:     true,
+		},
+		{
+			name:     "wildcard suffix",
+			entityID: "sensor.phone_battery",
+			patterns: []string{"*_battery"},
+			want:     true,
+		},
+		{
+			name:     "single character wildcard",
+			entityID: "light.lamp_2",
+			patterns: []string{"light.lamp_?"},
+			want:     true,
+		},
+		{
+			name:     "character class",
+			entityID: "light.lamp_3",
+			patterns: []string{"light.lamp_[12]"},
+			want:     false,
+		},
+		{
+			name:     "wildcard across slash",
+			entityID: "Lounge/TV",
+			patterns: []string{"*tv*"},
+			want:     true,
+		},
+		{
+			name:     "single character wildcard matches slash",
+			entityID: "AC/DC Speaker",
+			patterns: []string{"ac?dc*"},
+			want:     true,
+		},
+		{
+			name:     "literal slash",
+			entityID: "Lounge/TV",
+			patterns: []string{"lounge/*"},
+			want:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := matchesPatterns(tt.entityID, tt.patterns)
+			if got != tt.want {
+				t.Errorf("matchesPatterns(%q, %v) = %v, want %v", tt.entityID, tt.patterns, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePatterns(t *testing.T) {
+	got, err := parsePatterns([]string{"Light.*", "*Temp*"})
+	if err != nil {
+		t.Fatalf("parsePatterns() error = %v", err)
+	}
+	if len(got) != 2 || got[0] != "light.*" || got[1] != "*temp*" {
+		t.Errorf("parsePatterns() = %v, want lowercased patterns", got)
+	}
+
+	if _, err := parsePatterns([]string{"light.[kitchen"}); err == nil {
+		t.Error("parsePatterns() expected error for unterminated character class")
+	}
+}
+
+func TestEntityWithStateMatches(t *testing.T) {
+	entity := EntityWithState{
+		EntityID:     "sensor.outdoor_1",
+		OriginalName: strPtr("Garden Temperature"),
+	}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		regex    string
+		want     bool
+	}{
+		{name: "no filters", want: true},
+		{name: "glob on entity ID", patterns: []string{"sensor.outdoor*"}, want: true},
+		{name: "glob on name", patterns: []string{"*temperature*"}, want: true},
+		{name: "glob no match", patterns: []string{"*humidity*"}, want: false},
+		{name: "regex on entity ID", regex: `^sensor\.outdoor_\d$`, want: true},
+		{name: "regex ignores name", regex: `Garden`, want: false},
+		{name: "glob and regex", patterns: []string{"*temperature*"}, regex: `_1$`, want: true},
+		{name: "glob matches but regex does not", patterns: []string{"*temperature*"}, regex: `_2$`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var re *regexp.Regexp
+			if tt.regex != "" {
+				re = regexp.MustCompile(tt.regex)
+			}
+			if got := entity.matches(tt.patterns, re); got != tt.want {
+				t.Errorf("matches(%v, %q) = %v, want %v", tt.patterns, tt.regex, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClosestEntityID(t *testing.T) {
+	known := []string{"light.living_room", "light.kitchen", "sensor.living_room_temperature", "switch.fan"}
+
+	tests := []struct {
+		entityID string
+		want     string
+		wantOK   bool
+	}{
+		{"light.livingroom", "light.living_room", true},
+		{"Light.Kitchn", "light.kitchen", true},
+		{"switch.fna", "switch.fan", true},
+		{"light.garage", "", false},
+		{"x", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entityID, func(t *testing.T) {
+			got, ok := closestEntityID(tt.entityID, known)
+			if ok != tt.wantOK || (ok && got != tt.want) {
+				t.Errorf("closestEntityID(%q) = %q, %v, want %q, %v", tt.entityID, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"light.livingroom", "light.living_room", 1},
+		{"température", "temperature", 1},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
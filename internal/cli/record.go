@@ -0,0 +1,237 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+	"github.com/spf13/cobra"
+)
+
+// RecordedEvent is one line of a recording file.
+//
+// Recordings are NDJSON: one JSON object per line, in the order the events
+// were received. OffsetMS is the time since the recording started and is
+// what replay uses to reproduce the original timing. Event is the event
+// object exactly as sent by Home Assistant (event_type, data, origin,
+// time_fired, context).
+type RecordedEvent struct {
+	RecordedAt string          `json:"recorded_at"`
+	OffsetMS   int64           `json:"offset_ms"`
+	Event      json.RawMessage `json:"event"`
+}
+
+var (
+	recordOutput    string
+	recordEventType string
+	replaySpeed     float64
+)
+
+var recordCmd = &cobra.Command{
+	Use:   "record",
+	Short: "Record events to an NDJSON file",
+	Long: `Subscribe to the Home Assistant event bus and append every event to a file
+as NDJSON (one JSON object per line). Press Ctrl+C to stop recording.
+
+Each line has the form:
+  {"recorded_at": "<RFC3339 time>", "offset_ms": <ms since start>, "event": {...}}
+
+where "event" is the raw event object sent by Home Assistant.
+
+Examples:
+  hass-cli record -o session.ndjson                       # Record all events
+  hass-cli record -o states.ndjson --event-type state_changed`,
+	Args: cobra.NoArgs,
+	RunE: runRecord,
+}
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <file>",
+	Short: "Replay events from an NDJSON recording",
+	Long: `Print events from a file written by 'hass-cli record' as if they were live,
+keeping the original spacing between events.
+
+Examples:
+  hass-cli replay session.ndjson                # Replay in real time
+  hass-cli replay session.ndjson --speed 10     # Replay 10x faster
+  hass-cli replay session.ndjson --speed 0      # Print everything immediately
+  hass-cli replay session.ndjson --json         # Output raw events`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReplay,
+}
+
+func init() {
+	recordCmd.Flags().StringVarP(&recordOutput, "output", "o", "", "File to append events to (required)")
+	recordCmd.Flags().StringVar(&recordEventType, "event-type", "", "Only record events of this type (default: all events)")
+	recordCmd.MarkFlagRequired("output")
+
+	replayCmd.Flags().Float64Var(&replaySpeed, "speed", 1, "Playback speed multiplier (0 = no delay)")
+
+	rootCmd.AddCommand(recordCmd)
+	rootCmd.AddCommand(replayCmd)
+}
+
+func runRecord(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(recordOutput, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %w", err)
+	}
+	defer file.Close()
+
+	printInfo("Connecting to Home Assistant...")
+	client, err := newWSClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer client.Close()
+
+	printInfo("Subscribing to events...")
+	if _, err := client.SubscribeEvents(recordEventType); err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	printSuccess("Recording events to %s... (press Ctrl+C to stop)", recordOutput)
+
+	// Handle Ctrl+C
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	// The reader sends at most one error and stops sending events once
+	// runRecord returns, so it never blocks after Ctrl+C or a write error.
+	// Closing the client then fails its ReadEvent and lets it exit.
+	eventChan := make(chan *websocket.EventMessage, 1)
+	errChan := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		for {
+			event, err := client.ReadEvent()
+			if err != nil {
+				errChan <- err
+				return
+			}
+			select {
+			case eventChan <- event:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	start := time.Now()
+	count := 0
+
+	for {
+		select {
+		case <-sigChan:
+			printSuccess("\nRecorded %d events to %s", count, recordOutput)
+			return nil
+
+		case err := <-errChan:
+			return fmt.Errorf("connection error after %d events: %w", count, err)
+
+		case event := <-eventChan:
+			var frame struct {
+				Event json.RawMessage `json:"event"`
+			}
+			if err := json.Unmarshal(event.Raw, &frame); err != nil {
+				continue
+			}
+			if redactOutput {
+				redacted, err := redactData(frame.Event)
+				if err != nil {
+					return fmt.Errorf("failed to redact event: %w", err)
+				}
+				if frame.Event, err = json.Marshal(redacted); err != nil {
+					return fmt.Errorf("failed to encode event: %w", err)
+				}
+			}
+
+			now := time.Now()
+			line, err := json.Marshal(RecordedEvent{
+				RecordedAt: now.Format(time.RFC3339Nano),
+				OffsetMS:   now.Sub(start).Milliseconds(),
+				Event:      frame.Event,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to encode event: %w", err)
+			}
+
+			if _, err := file.Write(append(line, '\n')); err != nil {
+				return fmt.Errorf("failed to write event: %w", err)
+			}
+			count++
+		}
+	}
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	if replaySpeed < 0 {
+		return fmt.Errorf("--speed must not be negative")
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	// Events with large attribute sets can exceed the default 64KB line limit
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	var lastOffset int64
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var rec RecordedEvent
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return fmt.Errorf("line %d: invalid record: %w", lineNum, err)
+		}
+
+		if replaySpeed > 0 && rec.OffsetMS > lastOffset {
+			delay := time.Duration(float64(rec.OffsetMS-lastOffset)/replaySpeed) * time.Millisecond
+			time.Sleep(delay)
+		}
+		lastOffset = rec.OffsetMS
+
+		if jsonOutput {
+			outputJSON(cmd.OutOrStdout(), rec.Event)
+			continue
+		}
+
+		var event websocket.EventData
+		if err := json.Unmarshal(rec.Event, &event); err != nil {
+			return fmt.Errorf("line %d: invalid event: %w", lineNum, err)
+		}
+
+		if event.EventType == "state_changed" {
+			printStateChange(cmd.OutOrStdout(), &event)
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "[%s] %s\n", formatEventTime(event.TimeFired), event.EventType)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read recording: %w", err)
+	}
+
+	return nil
+}
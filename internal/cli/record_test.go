@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dorinclisu/hass-cli/internal/testutil"
+)
+
+func TestRecord_WritesEvents(t *testing.T) {
+	mock := testutil.NewWSMock(t, testToken)
+
+	var subscribed map[string]interface{}
+	mock.Handle("subscribe_events", func(msg map[string]interface{}) (interface{}, error) {
+		subscribed = msg
+		sub := &testutil.WSSubscription{Drop: true}
+		for _, entityID := range []string{"light.kitchen", "light.hall", "switch.fan"} {
+			sub.Events = append(sub.Events, map[string]interface{}{
+				"event_type": "state_changed",
+				"time_fired": "2024-01-01T12:00:00+00:00",
+				"data":       map[string]interface{}{"entity_id": entityID},
+			})
+		}
+		return sub, nil
+	})
+
+	defer func() {
+		serverURL, token, configPath = "", "", ""
+		recordOutput, recordEventType = "", ""
+		rootCmd.SetArgs(nil)
+	}()
+
+	path := filepath.Join(t.TempDir(), "session.ndjson")
+	rootCmd.SetArgs([]string{"record", "-o", path, "--event-type", "state_changed",
+		"--url", mock.URL(), "--token", testToken, "--config", filepath.Join(t.TempDir(), "none.yaml")})
+
+	// The mock drops the connection after the last event, which ends the recording
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "after 3 events") {
+		t.Fatalf("record error = %v, want a connection error after 3 events", err)
+	}
+	if subscribed["event_type"] != "state_changed" {
+		t.Errorf("subscribed event_type = %v, want state_changed", subscribed["event_type"])
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer f.Close()
+
+	var entityIDs []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec RecordedEvent
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("invalid record %q: %v", scanner.Text(), err)
+		}
+		if rec.RecordedAt == "" || rec.OffsetMS < 0 {
+			t.Errorf("record timing = %q, %d", rec.RecordedAt, rec.OffsetMS)
+		}
+
+		var event struct {
+			EventType string `json:"event_type"`
+			Data      struct {
+				EntityID string `json:"entity_id"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Event, &event); err != nil {
+			t.Fatalf("invalid event %s: %v", rec.Event, err)
+		}
+		if event.EventType != "state_changed" {
+			t.Errorf("event_type = %q, want state_changed", event.EventType)
+		}
+		entityIDs = append(entityIDs, event.Data.EntityID)
+	}
+
+	if got := strings.Join(entityIDs, ","); got != "light.kitchen,light.hall,switch.fan" {
+		t.Errorf("recorded entities = %s, want light.kitchen,light.hall,switch.fan", got)
+	}
+}
@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var (
+	recorderUsageHours  float64
+	recorderUsageTop    int
+	recorderUsageDomain string
+)
+
+// recorderUsageBatch is how many entities are requested per history call,
+// keeping the URL of each request short.
+const recorderUsageBatch = 50
+
+var recorderCmd = &cobra.Command{
+	Use:   "recorder",
+	Short: "Inspect the recorder database",
+	Long: `Inspect what the recorder stores in the Home Assistant database.
+
+Examples:
+  hass-cli recorder usage                 # Busiest entities in the last 24 hours
+  hass-cli recorder usage --hours 6 --top 50`,
+}
+
+var recorderUsageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show which entities write the most states",
+	Long: `Show the entities that recorded the most state changes over a sample
+window, as a measure of how much of the recorder database each one fills.
+
+Every recorded change is a row in the states table, so the busiest entities
+are the best candidates to exclude from the recorder. The STATISTICS column
+marks entities with a state_class, whose long-term statistics are kept even
+when their states are purged.
+
+Fetching the history of every entity takes a while on large installations;
+use --domain or a shorter --hours to speed it up.
+
+Examples:
+  hass-cli recorder usage
+  hass-cli recorder usage --hours 6 --top 50
+  hass-cli recorder usage -d sensor --json`,
+	Args: cobra.NoArgs,
+	RunE: runRecorderUsage,
+}
+
+func init() {
+	rootCmd.AddCommand(recorderCmd)
+	recorderCmd.AddCommand(recorderUsageCmd)
+
+	recorderUsageCmd.Flags().Float64Var(&recorderUsageHours, "hours", 24, "Length of the sample window in hours, ending now")
+	recorderUsageCmd.Flags().IntVar(&recorderUsageTop, "top", 20, "Number of entities to show (0 = all)")
+	recorderUsageCmd.Flags().StringVarP(&recorderUsageDomain, "domain", "d", "", "Only include entities of this domain")
+}
+
+// RecorderUsage is how many state changes an entity recorded in the window.
+type RecorderUsage struct {
+	EntityID   string  `json:"entity_id"`
+	Changes    int     `json:"changes"`
+	PerHour    float64 `json:"per_hour"`
+	Statistics bool    `json:"statistics"`
+}
+
+func runRecorderUsage(cmd *cobra.Command, args []string) error {
+	if recorderUsageHours <= 0 {
+		return fmt.Errorf("--hours must be positive")
+	}
+	if recorderUsageTop < 0 {
+		return fmt.Errorf("--top must not be negative")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	printInfo("Fetching states...")
+	states, err := client.GetStates()
+	if err != nil {
+		return fmt.Errorf("failed to get states: %w", err)
+	}
+
+	var entityIDs []string
+	statistics := make(map[string]bool)
+	for _, state := range states {
+		if recorderUsageDomain != "" && !inDomain(state.EntityID, recorderUsageDomain) {
+			continue
+		}
+		entityIDs = append(entityIDs, state.EntityID)
+		if _, ok := state.Attributes["state_class"]; ok {
+			statistics[state.EntityID] = true
+		}
+	}
+
+	end := time.Now()
+	window := time.Duration(recorderUsageHours * float64(time.Hour))
+	start := end.Add(-window)
+
+	var history [][]api.State
+	for i := 0; i < len(entityIDs); i += recorderUsageBatch {
+		batch := entityIDs[i:min(i+recorderUsageBatch, len(entityIDs))]
+		printInfo("Fetching history for entities %d-%d of %d...", i+1, i+len(batch), len(entityIDs))
+		part, err := client.GetHistory(batch, start, end)
+		if err != nil {
+			if api.IsNotFound(err) {
+				return fmt.Errorf("history is not available (is the history integration enabled?)")
+			}
+			return fmt.Errorf("failed to get history: %w", err)
+		}
+		history = append(history, part...)
+	}
+
+	usage := recorderUsage(history, start, window, statistics)
+	if recorderUsageTop > 0 && len(usage) > recorderUsageTop {
+		usage = usage[:recorderUsageTop]
+	}
+
+	return outputData(cmd.OutOrStdout(), usage, recorderUsageTable(usage))
+}
+
+// recorderUsage counts the changes each entity recorded since start, busiest
+// first. The history of an entity begins with its state at start, which was
+// recorded earlier and is not counted.
+func recorderUsage(history [][]api.State, start time.Time, window time.Duration, statistics map[string]bool) []RecorderUsage {
+	var usage []RecorderUsage
+	for _, states := range history {
+		changes := 0
+		for _, state := range states {
+			changed, err := time.Parse(time.RFC3339, state.LastChanged)
+			if err == nil && !changed.Before(start) {
+				changes++
+			}
+		}
+		if changes == 0 {
+			continue
+		}
+
+		entityID := states[0].EntityID
+		usage = append(usage, RecorderUsage{
+			EntityID:   entityID,
+			Changes:    changes,
+			PerHour:    float64(changes) / window.Hours(),
+			Statistics: statistics[entityID],
+		})
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Changes != usage[j].Changes {
+			return usage[i].Changes > usage[j].Changes
+		}
+		return usage[i].EntityID < usage[j].EntityID
+	})
+
+	return usage
+}
+
+func recorderUsageTable(usage []RecorderUsage) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "ENTITY ID"}, {Header: "CHANGES"}, {Header: "PER HOUR"}, {Header: "STATISTICS"}},
+		Noun:    "entities",
+		Empty:   "No state changes recorded in the window",
+	}
+
+	for _, u := range usage {
+		stats := "-"
+		if u.Statistics {
+			stats = "yes"
+		}
+		t.addRow(
+			u.EntityID,
+			fmt.Sprintf("%d", u.Changes),
+			strings.TrimSuffix(fmt.Sprintf("%.1f", u.PerHour), ".0"),
+			stats,
+		)
+	}
+
+	return t
+}
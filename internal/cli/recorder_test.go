@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+)
+
+func TestRecorderUsage(t *testing.T) {
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	history := [][]api.State{
+		{
+			{EntityID: "sensor.power", LastChanged: "2024-01-14T23:59:00+00:00"},
+			{EntityID: "sensor.power", LastChanged: "2024-01-15T01:00:00+00:00"},
+			{EntityID: "sensor.power", LastChanged: "2024-01-15T02:00:00.5+00:00"},
+			{EntityID: "sensor.power", LastChanged: "2024-01-15T03:00:00+00:00"},
+		},
+		{
+			{EntityID: "light.hall", LastChanged: "2024-01-15T00:00:00+00:00"},
+		},
+		{
+			{EntityID: "light.attic", LastChanged: "2024-01-10T00:00:00+00:00"},
+		},
+		{
+			{EntityID: "light.kitchen", LastChanged: "2024-01-14T00:00:00+00:00"},
+			{EntityID: "light.kitchen", LastChanged: "2024-01-15T05:00:00+00:00"},
+		},
+	}
+
+	got := recorderUsage(history, start, 2*time.Hour, map[string]bool{"sensor.power": true})
+	want := []RecorderUsage{
+		{EntityID: "sensor.power", Changes: 3, PerHour: 1.5, Statistics: true},
+		{EntityID: "light.hall", Changes: 1, PerHour: 0.5},
+		{EntityID: "light.kitchen", Changes: 1, PerHour: 0.5},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("recorderUsage() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("recorderUsage()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// redactedValue replaces the value of every sensitive key when --redact is set.
+const redactedValue = "**REDACTED**"
+
+// defaultRedactKeys are the keys masked by --redact. Extra keys can be added
+// with --redact-keys.
+var defaultRedactKeys = []string{
+	"latitude",
+	"longitude",
+	"access_token",
+	"password",
+	"api_key",
+	"code",
+}
+
+// isRedactedKey reports whether values stored under key should be masked.
+func isRedactedKey(key string) bool {
+	for _, k := range defaultRedactKeys {
+		if strings.EqualFold(key, k) {
+			return true
+		}
+	}
+	for _, k := range redactExtraKeys {
+		if strings.EqualFold(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactData returns a copy of data with sensitive keys masked at any depth.
+// The data is round-tripped through JSON so structs, maps and slices are
+// handled uniformly.
+func redactData(data interface{}) (interface{}, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil, err
+	}
+
+	return redactValue(generic), nil
+}
+
+// redactValue walks a decoded JSON value and masks sensitive map keys. Maps
+// and slices are copied, so v itself is left unchanged.
+func redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(val))
+		for k, child := range val {
+			if isRedactedKey(k) {
+				masked[k] = redactedValue
+				continue
+			}
+			masked[k] = redactValue(child)
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(val))
+		for i, child := range val {
+			masked[i] = redactValue(child)
+		}
+		return masked
+	default:
+		return v
+	}
+}
+
+// redactTable returns a copy of t with sensitive values masked: whole columns
+// named after a sensitive key, e.g. ATTRIBUTES.LATITUDE from --fields, and
+// sensitive keys inside cells holding JSON objects or lists.
+func redactTable(t *tableData) *tableData {
+	masked := *t
+	masked.Rows = make([][]string, len(t.Rows))
+
+	redactColumn := make([]bool, len(t.Columns))
+	for i, col := range t.Columns {
+		key := strings.ToLower(col.Header)
+		if dot := strings.LastIndex(key, "."); dot >= 0 {
+			key = key[dot+1:]
+		}
+		redactColumn[i] = isRedactedKey(strings.ReplaceAll(key, " ", "_"))
+	}
+
+	for r, row := range t.Rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			switch {
+			case i < len(redactColumn) && redactColumn[i]:
+				cells[i] = redactedValue
+			default:
+				cells[i] = redactCell(cell)
+			}
+		}
+		masked.Rows[r] = cells
+	}
+
+	return &masked
+}
+
+// redactCell masks sensitive keys in a cell holding a JSON object or list and
+// returns any other cell as it is.
+func redactCell(cell string) string {
+	if !strings.HasPrefix(cell, "{") && !strings.HasPrefix(cell, "[") {
+		return cell
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(cell), &v); err != nil {
+		return cell
+	}
+	data, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return cell
+	}
+	return string(data)
+}
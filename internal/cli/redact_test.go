@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRedactData(t *testing.T) {
+	type location struct {
+		Name      string  `json:"name"`
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+	}
+
+	input := map[string]interface{}{
+		"entity_id": "device_tracker.phone",
+		"attributes": map[string]interface{}{
+			"Access_Token":  "secret",
+			"friendly_name": "Phone",
+			"zones": []interface{}{
+				location{Name: "Home", Latitude: 52.1, Longitude: 4.3},
+			},
+		},
+	}
+
+	got, err := redactData(input)
+	if err != nil {
+		t.Fatalf("redactData() error = %v", err)
+	}
+
+	attrs := got.(map[string]interface{})["attributes"].(map[string]interface{})
+	if attrs["Access_Token"] != redactedValue {
+		t.Errorf("Access_Token = %v, want %q", attrs["Access_Token"], redactedValue)
+	}
+	if attrs["friendly_name"] != "Phone" {
+		t.Errorf("friendly_name = %v, want %q", attrs["friendly_name"], "Phone")
+	}
+
+	zone := attrs["zones"].([]interface{})[0].(map[string]interface{})
+	if zone["latitude"] != redactedValue || zone["longitude"] != redactedValue {
+		t.Errorf("zone coordinates not redacted: %v", zone)
+	}
+	if zone["name"] != "Home" {
+		t.Errorf("zone name = %v, want %q", zone["name"], "Home")
+	}
+
+	// The input must not be modified
+	if input["attributes"].(map[string]interface{})["Access_Token"] != "secret" {
+		t.Error("redactData() modified its input")
+	}
+}
+
+func TestRedactExtraKeys(t *testing.T) {
+	old := redactExtraKeys
+	redactExtraKeys = []string{"ssid"}
+	defer func() { redactExtraKeys = old }()
+
+	got := redactValue(map[string]interface{}{"ssid": "home-wifi", "state": "on"}).(map[string]interface{})
+	if got["ssid"] != redactedValue {
+		t.Errorf("ssid = %v, want %q", got["ssid"], redactedValue)
+	}
+	if got["state"] != "on" {
+		t.Errorf("state = %v, want %q", got["state"], "on")
+	}
+}
+
+func TestRedactValue_CopiesInput(t *testing.T) {
+	attrs := map[string]interface{}{"latitude": 52.1, "zones": []interface{}{map[string]interface{}{"code": "1234"}}}
+
+	got := redactValue(attrs).(map[string]interface{})
+	if got["latitude"] != redactedValue {
+		t.Errorf("latitude = %v, want %q", got["latitude"], redactedValue)
+	}
+	if attrs["latitude"] != 52.1 {
+		t.Errorf("redactValue() modified its input: latitude = %v", attrs["latitude"])
+	}
+	if zone := attrs["zones"].([]interface{})[0].(map[string]interface{}); zone["code"] != "1234" {
+		t.Errorf("redactValue() modified its input: code = %v", zone["code"])
+	}
+}
+
+func TestOutputData_Redact(t *testing.T) {
+	oldRedact, oldFormat := redactOutput, outputFormat
+	redactOutput = true
+	defer func() { redactOutput, outputFormat = oldRedact, oldFormat }()
+
+	table := &tableData{
+		Columns: []tableColumn{{Header: "ENTITY ID"}, {Header: "ATTRIBUTES.LATITUDE"}, {Header: "ATTRIBUTES"}},
+	}
+	table.addRow("device_tracker.phone", "52.1", `{"friendly_name":"Phone","longitude":4.3}`)
+
+	for _, format := range []string{"table", "tsv", "csv"} {
+		outputFormat = format
+		var buf bytes.Buffer
+		if err := outputData(&buf, nil, table); err != nil {
+			t.Fatalf("outputData(%s) error = %v", format, err)
+		}
+		out := buf.String()
+		if strings.Contains(out, "52.1") || strings.Contains(out, "4.3") {
+			t.Errorf("outputData(%s) leaked coordinates:\n%s", format, out)
+		}
+		if !strings.Contains(out, "device_tracker.phone") || !strings.Contains(out, "Phone") {
+			t.Errorf("outputData(%s) masked too much:\n%s", format, out)
+		}
+	}
+
+	if table.Rows[0][1] != "52.1" {
+		t.Error("outputData() modified the table")
+	}
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/dorinclisu/hass-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var reloadAfterChange bool
+
+var reloadCmd = &cobra.Command{
+	Use:   "reload <domain>...",
+	Short: "Reload automations, scripts, scenes or helpers",
+	Long: `Reload the configuration of one or more domains without restarting
+Home Assistant.
+
+Supported domains are automation, script, scene and the input_* helper
+domains. Plural names (automations, scripts, scenes) and short helper names
+(boolean, select, ...) are accepted, and "helpers" reloads every input_*
+domain.
+
+Create, edit and delete commands accept --reload to do this automatically
+after the change.
+
+Examples:
+  hass-cli reload automations
+  hass-cli reload scripts scenes
+  hass-cli reload input_select
+  hass-cli reload helpers`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runReload,
+}
+
+func init() {
+	rootCmd.AddCommand(reloadCmd)
+}
+
+// addReloadFlag registers --reload on a command that changes the
+// configuration of a reloadable domain.
+func addReloadFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&reloadAfterChange, "reload", false, "Reload the domain afterwards so the change takes effect immediately")
+}
+
+// resolveReloadDomains maps a domain name given on the command line to the
+// domains to reload.
+func resolveReloadDomains(name string) ([]string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	switch name {
+	case "automations":
+		name = "automation"
+	case "scripts":
+		name = "script"
+	case "scenes":
+		name = "scene"
+	case "helpers":
+		var domains []string
+		for _, domain := range api.ReloadableDomains {
+			if strings.HasPrefix(domain, "input_") {
+				domains = append(domains, domain)
+			}
+		}
+		return domains, nil
+	}
+
+	if slices.Contains(api.ReloadableDomains, name) {
+		return []string{name}, nil
+	}
+	if helper := normalizeHelperType(name); slices.Contains(api.ReloadableDomains, helper) {
+		return []string{helper}, nil
+	}
+
+	return nil, fmt.Errorf("domain %q cannot be reloaded (supported: %s, helpers)", name, strings.Join(api.ReloadableDomains, ", "))
+}
+
+func runReload(cmd *cobra.Command, args []string) error {
+	var domains []string
+	for _, arg := range args {
+		resolved, err := resolveReloadDomains(arg)
+		if err != nil {
+			return err
+		}
+		for _, domain := range resolved {
+			if !slices.Contains(domains, domain) {
+				domains = append(domains, domain)
+			}
+		}
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+	for _, domain := range domains {
+		printInfo("Reloading %s...", domain)
+		if err := client.ReloadDomain(domain); err != nil {
+			return fmt.Errorf("failed to reload %s: %w", domain, err)
+		}
+		printSuccess("Reloaded %s", domain)
+	}
+
+	return nil
+}
+
+// reloadOrNote finishes a command that changed the configuration of domain.
+// With --reload the domain is reloaded; otherwise note, if any, is printed
+// as a hint that a reload is needed.
+func reloadOrNote(cfg *config.Config, domain, note string) error {
+	if !reloadAfterChange {
+		if note != "" {
+			printNote("%s", note)
+		}
+		return nil
+	}
+
+	printInfo("Reloading %s...", domain)
+	if err := newRESTClient(cfg).ReloadDomain(domain); err != nil {
+		return fmt.Errorf("change applied, but failed to reload %s: %w", domain, err)
+	}
+	printSuccess("Reloaded %s", domain)
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestResolveReloadDomains(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "automation", want: []string{"automation"}},
+		{name: "automations", want: []string{"automation"}},
+		{name: "Scripts", want: []string{"script"}},
+		{name: "scenes", want: []string{"scene"}},
+		{name: "input_select", want: []string{"input_select"}},
+		{name: "boolean", want: []string{"input_boolean"}},
+		{name: "helpers", want: []string{"input_boolean", "input_button", "input_datetime", "input_number", "input_select", "input_text"}},
+		{name: "light", wantErr: true},
+		{name: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveReloadDomains(tt.name)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("resolveReloadDomains(%q) = %v, expected error", tt.name, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveReloadDomains(%q) error = %v", tt.name, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("resolveReloadDomains(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,20 +2,57 @@
 package cli
 
 import (
+	"bufio"
+	"crypto/tls"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/dorinclisu/hass-cli/internal/config"
+	"github.com/dorinclisu/hass-cli/internal/websocket"
 	"github.com/spf13/cobra"
 )
 
 var (
 	// Global flags
-	jsonOutput bool
-	configPath string
-	serverURL  string
-	token      string
-	timeout    int
-	verbose    bool
+	jsonOutput      bool
+	outputFormat    string
+	tsvOutput       bool
+	compactJSON     bool
+	errorsToStdout  bool
+	redactOutput    bool
+	redactExtraKeys []string
+	configPath      string
+	serverURL       string
+	token           string
+	timeout         int
+	retries         int
+	headerFlags     []string
+	minTLS          string
+	insecure        bool
+	verbose         bool
+	quiet           bool
+	timezone        string
+	useUTC          bool
+	outputFile      string
+
+	// displayLocation is the zone timestamps are rendered in
+	displayLocation = time.Local
+
+	// extraHeaders are the --header values, added to every REST request
+	extraHeaders http.Header
+
+	// tlsConfig is the TLS configuration for REST and WebSocket connections,
+	// or nil for the defaults
+	tlsConfig *tls.Config
+
+	// outputFileHandle is the writer for --output-file, closed when the
+	// command finishes
+	outputFileHandle *lazyFile
 
 	// Version is set from main
 	version = "dev"
@@ -34,11 +71,32 @@ Get started by running:
   hass-cli login --url http://your-ha-instance:8123 --token YOUR_TOKEN`,
 	SilenceUsage:  true,
 	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := resolveOutputFormat(); err != nil {
+			return err
+		}
+		headers, err := parseHeaders(headerFlags)
+		if err != nil {
+			return err
+		}
+		extraHeaders = headers
+		if err := resolveTLSConfig(); err != nil {
+			return err
+		}
+		if err := resolveDisplayLocation(); err != nil {
+			return err
+		}
+		return resolveOutputFile(cmd)
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	if closeErr := closeOutputFile(err == nil); err == nil {
+		err = closeErr
+	}
+	return checkUnauthorized(err)
 }
 
 // SetVersion sets the version string for the CLI.
@@ -49,11 +107,25 @@ func SetVersion(v string) {
 func init() {
 	// Global flags
 	rootCmd.PersistentFlags().BoolVarP(&jsonOutput, "json", "j", false, "Output in JSON format")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", "table", "Output format for lists: table, json, tsv, csv")
+	rootCmd.PersistentFlags().BoolVar(&tsvOutput, "tsv", false, "Output lists as tab-separated values (same as --format tsv)")
+	rootCmd.PersistentFlags().BoolVar(&compactJSON, "compact", false, "Emit single-line JSON (use with --json)")
+	rootCmd.PersistentFlags().BoolVar(&errorsToStdout, "errors-stdout", false, "Write JSON error reports to stdout instead of stderr (use with --json)")
+	rootCmd.PersistentFlags().BoolVar(&redactOutput, "redact", false, "Mask sensitive values (tokens, passwords, coordinates) in output")
+	rootCmd.PersistentFlags().StringSliceVar(&redactExtraKeys, "redact-keys", nil, "Additional keys to mask with --redact (comma-separated)")
 	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "Path to config file (default: ~/.config/hass-cli/config.yaml)")
 	rootCmd.PersistentFlags().StringVar(&serverURL, "url", "", "Home Assistant server URL (overrides config)")
 	rootCmd.PersistentFlags().StringVar(&token, "token", "", "Access token (overrides config)")
 	rootCmd.PersistentFlags().IntVar(&timeout, "timeout", 30, "Request timeout in seconds")
+	rootCmd.PersistentFlags().IntVar(&retries, "retries", 2, "Retries for read requests failing with a gateway error or timeout")
+	rootCmd.PersistentFlags().StringArrayVar(&headerFlags, "header", nil, "Extra HTTP header for REST requests ('Key: Value'), can be repeated")
+	rootCmd.PersistentFlags().StringVar(&minTLS, "min-tls", "", "Minimum TLS version for https/wss connections: 1.0, 1.1, 1.2 or 1.3")
+	rootCmd.PersistentFlags().BoolVar(&insecure, "insecure", false, "Skip TLS certificate verification, e.g. for self-signed certificates")
+	rootCmd.PersistentFlags().StringVar(&outputFile, "output-file", "", "Write command output (JSON, tables) to this file instead of stdout")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Omit reload notes and table totals, for scripts parsing the output")
+	rootCmd.PersistentFlags().StringVar(&timezone, "timezone", "", "Time zone for displayed timestamps (UTC or IANA name, default: local)")
+	rootCmd.PersistentFlags().BoolVar(&useUTC, "utc", false, "Display timestamps in UTC (same as --timezone UTC)")
 
 	// Add version command
 	rootCmd.AddCommand(versionCmd)
@@ -63,23 +135,389 @@ var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print the version number",
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("hass-cli version %s\n", version)
+		fmt.Fprintf(cmd.OutOrStdout(), "hass-cli version %s\n", version)
 	},
 }
 
+// resolveOutputFormat reconciles --format with the --json and --tsv
+// shorthands. Afterwards outputFormat holds the effective format and
+// jsonOutput is set whenever it is "json".
+func resolveOutputFormat() error {
+	format := strings.ToLower(outputFormat)
+
+	switch format {
+	case "table", "json", "tsv", "csv":
+	default:
+		return fmt.Errorf("invalid --format %q: use table, json, tsv or csv", outputFormat)
+	}
+
+	for flag, enabled := range map[string]bool{"json": jsonOutput, "tsv": tsvOutput} {
+		if !enabled {
+			continue
+		}
+		if format != "table" && format != flag {
+			return fmt.Errorf("--%s cannot be combined with --format %s", flag, format)
+		}
+		format = flag
+	}
+
+	if jsonOutput && tsvOutput {
+		return fmt.Errorf("--json cannot be combined with --tsv")
+	}
+
+	outputFormat = format
+	jsonOutput = format == "json"
+	return nil
+}
+
+// tlsVersions maps --min-tls values to their crypto/tls constants.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// parseTLSVersion parses a TLS version such as "1.3" (a "TLS" prefix is
+// allowed, e.g. "TLS1.3").
+func parseTLSVersion(s string) (uint16, error) {
+	v := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "TLS")
+	version, ok := tlsVersions[strings.TrimSpace(v)]
+	if !ok {
+		return 0, fmt.Errorf("invalid TLS version %q: use 1.0, 1.1, 1.2 or 1.3", s)
+	}
+	return version, nil
+}
+
+// resolveTLSConfig builds tlsConfig from the TLS flags. It stays nil when
+// none are set, so the Go defaults apply.
+func resolveTLSConfig() error {
+	tlsConfig = nil
+	if minTLS == "" && !insecure {
+		return nil
+	}
+
+	cfg := &tls.Config{}
+	if minTLS != "" {
+		version, err := parseTLSVersion(minTLS)
+		if err != nil {
+			return fmt.Errorf("invalid --min-tls: %w", err)
+		}
+		cfg.MinVersion = version
+	}
+	if insecure {
+		cfg.InsecureSkipVerify = true
+		fmt.Fprintln(os.Stderr, "Warning: --insecure skips TLS certificate verification; the connection is not protected against impersonation.")
+	}
+
+	tlsConfig = cfg
+	return nil
+}
+
+// resolveOutputFile points the command output at --output-file when it is
+// given. Only data written through the command's output goes there; progress,
+// success messages and errors still go to the terminal.
+func resolveOutputFile(cmd *cobra.Command) error {
+	if outputFile == "" {
+		return nil
+	}
+
+	outputFileHandle = &lazyFile{path: outputFile}
+	cmd.Root().SetOut(outputFileHandle)
+	return nil
+}
+
+// closeOutputFile closes the file opened for --output-file, if any. A command
+// that succeeded without writing anything still leaves an empty file behind.
+func closeOutputFile(succeeded bool) error {
+	if outputFileHandle == nil {
+		return nil
+	}
+
+	f := outputFileHandle
+	outputFileHandle = nil
+	rootCmd.SetOut(nil)
+
+	if f.file == nil {
+		if !succeeded {
+			return nil
+		}
+		if _, err := f.open(); err != nil {
+			return err
+		}
+	}
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// lazyFile creates its file on the first write, so a command that fails
+// before producing any output leaves an existing file untouched.
+type lazyFile struct {
+	path string
+	file *os.File
+}
+
+func (l *lazyFile) Write(p []byte) (int, error) {
+	f, err := l.open()
+	if err != nil {
+		return 0, err
+	}
+	return f.Write(p)
+}
+
+func (l *lazyFile) open() (*os.File, error) {
+	if l.file == nil {
+		f, err := os.Create(l.path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create output file: %w", err)
+		}
+		l.file = f
+	}
+	return l.file, nil
+}
+
+// resolveDisplayLocation sets displayLocation from --timezone / --utc.
+func resolveDisplayLocation() error {
+	if useUTC && timezone != "" && !strings.EqualFold(timezone, "UTC") {
+		return fmt.Errorf("--utc cannot be combined with --timezone %s", timezone)
+	}
+
+	switch {
+	case useUTC:
+		displayLocation = time.UTC
+	case timezone != "":
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return fmt.Errorf("invalid --timezone: %w", err)
+		}
+		displayLocation = loc
+	default:
+		displayLocation = time.Local
+	}
+
+	return nil
+}
+
+// displayTime converts t to the zone timestamps are displayed in.
+func displayTime(t time.Time) time.Time {
+	return t.In(displayLocation)
+}
+
+// loadConfig loads the configuration and applies overrides. The server URL
+// and token are taken from, in order of precedence: the --url/--token flags,
+// the HASS_URL/HASS_TOKEN environment variables, then the config file. No
+// config file is needed when both are given by flags or the environment.
+func loadConfig() (*config.Config, error) {
+	url := serverURL
+	if url == "" {
+		url = os.Getenv("HASS_URL")
+	}
+	tok := token
+	if tok == "" {
+		tok = os.Getenv("HASS_TOKEN")
+	}
+
+	var cfg *config.Config
+	var err error
+
+	// Load from file
+	if configPath != "" {
+		cfg, err = config.LoadFrom(configPath)
+	} else {
+		cfg, err = config.Load()
+	}
+
+	// If config doesn't exist but URL and token are provided, create a temporary config
+	if err == config.ErrNotConfigured && url != "" && tok != "" {
+		cfg = &config.Config{
+			Server: config.ServerConfig{
+				URL:   url,
+				Token: tok,
+			},
+			Defaults: config.DefaultsConfig{
+				Output:  "human",
+				Timeout: timeout,
+			},
+		}
+		err = nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply overrides
+	if url != "" {
+		cfg.Server.URL = url
+	}
+	if tok != "" {
+		cfg.Server.Token = tok
+	}
+
+	// Validate
+	if !cfg.IsConfigured() {
+		return nil, config.ErrNotConfigured
+	}
+
+	applyConfigDefaults(cfg)
+	return cfg, nil
+}
+
+// applyConfigDefaults uses defaults.timeout and defaults.output from the
+// config file for whatever was not given on the command line.
+func applyConfigDefaults(cfg *config.Config) {
+	flags := rootCmd.PersistentFlags()
+	if !flags.Changed("timeout") && cfg.Defaults.Timeout > 0 {
+		timeout = cfg.Defaults.Timeout
+	}
+	if !flags.Changed("format") && !flags.Changed("json") && !flags.Changed("tsv") {
+		switch cfg.Defaults.Output {
+		case "json", "tsv", "csv":
+			outputFormat = cfg.Defaults.Output
+			jsonOutput = outputFormat == "json"
+		}
+	}
+}
+
+// entityArg returns the entity ID given on the command line, falling back
+// to defaults.entity from the config file for commands that accept it.
+func entityArg(cfg *config.Config, args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+	if cfg.Defaults.Entity == "" {
+		return "", fmt.Errorf("no entity ID given and defaults.entity is not set in the config file")
+	}
+	return cfg.Defaults.Entity, nil
+}
+
+// newRESTClient creates a REST API client for the configured server,
+// honoring --timeout, --retries, --header and --min-tls.
+func newRESTClient(cfg *config.Config) *api.Client {
+	return newRESTClientWithTimeout(cfg, time.Duration(timeout)*time.Second)
+}
+
+// newRESTClientWithTimeout is newRESTClient with a request timeout other
+// than --timeout, for commands whose requests are known to run long.
+func newRESTClientWithTimeout(cfg *config.Config, requestTimeout time.Duration) *api.Client {
+	opts := append([]api.ClientOption{api.WithRetry(retries, 500*time.Millisecond)}, restOptions()...)
+	return api.NewClientWithOptions(cfg.Server.URL, cfg.Server.Token, requestTimeout, opts...)
+}
+
+// restOptions returns the REST client options for --header and --min-tls.
+func restOptions() []api.ClientOption {
+	opts := []api.ClientOption{api.WithHeaders(extraHeaders)}
+	if tlsConfig != nil {
+		opts = append(opts, api.WithTLSConfig(tlsConfig))
+	}
+	return opts
+}
+
+// newWSClient connects a WebSocket client to the configured server,
+// honoring --timeout and --min-tls. Commands may pass extra options.
+func newWSClient(cfg *config.Config, extra ...websocket.ClientOption) (*websocket.Client, error) {
+	opts := extra
+	if tlsConfig != nil {
+		opts = append(opts, websocket.WithTLSConfig(tlsConfig))
+	}
+	return websocket.NewClientWithOptions(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second, opts...)
+}
+
+// withWSClient connects a WebSocket client to the configured server, runs fn
+// with it and closes the connection afterwards.
+func withWSClient(cfg *config.Config, fn func(*websocket.Client) error) error {
+	printInfo("Connecting to Home Assistant...")
+	client, err := newWSClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer client.Close()
+
+	return fn(client)
+}
+
+// parseHeaders parses --header values of the form "Key: Value". The
+// Authorization header is reserved for the access token.
+func parseHeaders(values []string) (http.Header, error) {
+	headers := http.Header{}
+	for _, v := range values {
+		key, value, ok := strings.Cut(v, ":")
+		key = strings.TrimSpace(key)
+		if !ok || !validHeaderName(key) {
+			return nil, fmt.Errorf("invalid --header %q (expected 'Key: Value')", v)
+		}
+		value = strings.TrimSpace(value)
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("invalid --header %q: value must be a single line", v)
+		}
+		if strings.EqualFold(key, "Authorization") {
+			return nil, fmt.Errorf("--header cannot set Authorization; use --token")
+		}
+		headers.Add(key, value)
+	}
+	return headers, nil
+}
+
+// validHeaderName reports whether name is a valid HTTP header field name.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// confirm asks a yes/no question on stdin and reports whether the user agreed.
+// The prompt goes to stderr so it never mixes with command output.
+func confirm(prompt string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", prompt)
+
+	reader := bufio.NewReader(os.Stdin)
+	input, _ := reader.ReadString('\n')
+
+	answer := strings.ToLower(strings.TrimSpace(input))
+	return answer == "y" || answer == "yes"
+}
+
 // printError prints an error message to stderr.
 func printError(format string, args ...interface{}) {
 	fmt.Fprintf(os.Stderr, "Error: "+format+"\n", args...)
 }
 
-// printSuccess prints a success message.
+// printSuccess prints a success or status message to stderr, leaving stdout
+// for command output.
 func printSuccess(format string, args ...interface{}) {
-	fmt.Printf(format+"\n", args...)
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+}
+
+// printNote prints a "Note:" trailer such as a reminder to reload, unless
+// --quiet is given.
+func printNote(format string, args ...interface{}) {
+	if !quiet {
+		fmt.Fprintf(os.Stderr, "\nNote: "+format+"\n", args...)
+	}
+}
+
+// printFooter writes a summary line after a listing to out, preceded by a
+// blank line, unless --quiet is given.
+func printFooter(out io.Writer, format string, args ...interface{}) {
+	if !quiet {
+		fmt.Fprintf(out, "\n"+format+"\n", args...)
+	}
 }
 
-// printInfo prints an info message (only in verbose mode).
+// printInfo prints an info message to stderr (only in verbose mode).
 func printInfo(format string, args ...interface{}) {
 	if verbose {
-		fmt.Printf(format+"\n", args...)
+		fmt.Fprintf(os.Stderr, format+"\n", args...)
 	}
 }
@@ -0,0 +1,349 @@
+package cli
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/dorinclisu/hass-cli/internal/config"
+	"github.com/dorinclisu/hass-cli/internal/testutil"
+)
+
+func TestLoadConfig_Precedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	fileCfg := &config.Config{Server: config.ServerConfig{URL: "http://file:8123", Token: "file-token"}}
+	if err := fileCfg.SaveTo(path); err != nil {
+		t.Fatalf("SaveTo() error = %v", err)
+	}
+
+	defer func() { configPath, serverURL, token = "", "", "" }()
+
+	tests := []struct {
+		name      string
+		envURL    string
+		envToken  string
+		flagURL   string
+		flagToken string
+		wantURL   string
+		wantToken string
+	}{
+		{name: "config file", wantURL: "http://file:8123", wantToken: "file-token"},
+		{name: "env overrides file", envURL: "http://env:8123", envToken: "env-token", wantURL: "http://env:8123", wantToken: "env-token"},
+		{name: "env token only", envToken: "env-token", wantURL: "http://file:8123", wantToken: "env-token"},
+		{
+			name:      "flags override env",
+			envURL:    "http://env:8123",
+			envToken:  "env-token",
+			flagURL:   "http://flag:8123",
+			flagToken: "flag-token",
+			wantURL:   "http://flag:8123",
+			wantToken: "flag-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HASS_URL", tt.envURL)
+			t.Setenv("HASS_TOKEN", tt.envToken)
+			configPath, serverURL, token = path, tt.flagURL, tt.flagToken
+
+			cfg, err := loadConfig()
+			if err != nil {
+				t.Fatalf("loadConfig() error = %v", err)
+			}
+			if cfg.Server.URL != tt.wantURL {
+				t.Errorf("URL = %q, want %q", cfg.Server.URL, tt.wantURL)
+			}
+			if cfg.Server.Token != tt.wantToken {
+				t.Errorf("Token = %q, want %q", cfg.Server.Token, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_EnvWithoutFile(t *testing.T) {
+	configPath = filepath.Join(t.TempDir(), "missing.yaml")
+	defer func() { configPath = "" }()
+
+	t.Run("both set", func(t *testing.T) {
+		t.Setenv("HASS_URL", "http://env:8123")
+		t.Setenv("HASS_TOKEN", "env-token")
+
+		cfg, err := loadConfig()
+		if err != nil {
+			t.Fatalf("loadConfig() error = %v", err)
+		}
+		if cfg.Server.URL != "http://env:8123" || cfg.Server.Token != "env-token" {
+			t.Errorf("Server = %+v, want env values", cfg.Server)
+		}
+	})
+
+	t.Run("token only", func(t *testing.T) {
+		t.Setenv("HASS_URL", "")
+		t.Setenv("HASS_TOKEN", "env-token")
+
+		if _, err := loadConfig(); err != config.ErrNotConfigured {
+			t.Errorf("loadConfig() error = %v, want ErrNotConfigured", err)
+		}
+	})
+}
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := parseHeaders([]string{"X-Forwarded-For: 10.0.0.5", "accept:application/json", "X-Empty:", "X-Trace: a", "X-Trace: b"})
+	if err != nil {
+		t.Fatalf("parseHeaders() error = %v", err)
+	}
+	if v := headers.Get("X-Forwarded-For"); v != "10.0.0.5" {
+		t.Errorf("X-Forwarded-For = %q, want %q", v, "10.0.0.5")
+	}
+	if v := headers.Get("Accept"); v != "application/json" {
+		t.Errorf("Accept = %q, want %q", v, "application/json")
+	}
+	if v, ok := headers["X-Empty"]; !ok || v[0] != "" {
+		t.Errorf("X-Empty = %v, want empty value", v)
+	}
+	if v := headers.Values("X-Trace"); len(v) != 2 {
+		t.Errorf("X-Trace = %v, want 2 values", v)
+	}
+
+	for _, bad := range []string{"NoColon", ": value", "Bad Name: x", "X-Multi: a\nb", "Authorization: Bearer x"} {
+		if _, err := parseHeaders([]string{bad}); err == nil {
+			t.Errorf("parseHeaders(%q) expected error", bad)
+		}
+	}
+}
+
+func TestEntityArg(t *testing.T) {
+	cfg := &config.Config{Defaults: config.DefaultsConfig{Entity: "sensor.outdoor_temperature"}}
+
+	if got, err := entityArg(cfg, []string{"light.kitchen"}); err != nil || got != "light.kitchen" {
+		t.Errorf("entityArg() with argument = %q, %v; want light.kitchen", got, err)
+	}
+	if got, err := entityArg(cfg, nil); err != nil || got != "sensor.outdoor_temperature" {
+		t.Errorf("entityArg() without argument = %q, %v; want defaults.entity", got, err)
+	}
+	if _, err := entityArg(&config.Config{}, nil); err == nil {
+		t.Error("entityArg() expected error when defaults.entity is not set")
+	}
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    uint16
+		wantErr bool
+	}{
+		{input: "1.2", want: tls.VersionTLS12},
+		{input: "1.3", want: tls.VersionTLS13},
+		{input: "TLS1.3", want: tls.VersionTLS13},
+		{input: "tls 1.0", want: tls.VersionTLS10},
+		{input: "1.4", wantErr: true},
+		{input: "13", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseTLSVersion(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseTLSVersion(%q) = %x, expected error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTLSVersion(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseTLSVersion(%q) = %x, want %x", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveTLSConfig(t *testing.T) {
+	defer func() { minTLS, insecure, tlsConfig = "", false, nil }()
+
+	if err := resolveTLSConfig(); err != nil || tlsConfig != nil {
+		t.Fatalf("resolveTLSConfig() without flags = %v, %v, want nil config", tlsConfig, err)
+	}
+
+	insecure = true
+	if err := resolveTLSConfig(); err != nil {
+		t.Fatalf("resolveTLSConfig() error = %v", err)
+	}
+	if tlsConfig == nil || !tlsConfig.InsecureSkipVerify || tlsConfig.MinVersion != 0 {
+		t.Errorf("resolveTLSConfig() with --insecure = %+v", tlsConfig)
+	}
+
+	minTLS = "1.3"
+	if err := resolveTLSConfig(); err != nil {
+		t.Fatalf("resolveTLSConfig() error = %v", err)
+	}
+	if !tlsConfig.InsecureSkipVerify || tlsConfig.MinVersion != tls.VersionTLS13 {
+		t.Errorf("resolveTLSConfig() with --insecure --min-tls 1.3 = %+v", tlsConfig)
+	}
+}
+
+func TestResolveOutputFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.json")
+	outputFile = path
+	defer func() { outputFile = "" }()
+
+	if err := resolveOutputFile(stateGetCmd); err != nil {
+		t.Fatalf("resolveOutputFile() error = %v", err)
+	}
+	if err := outputJSON(stateGetCmd.OutOrStdout(), map[string]string{"state": "on"}); err != nil {
+		t.Fatalf("outputJSON() error = %v", err)
+	}
+	if err := closeOutputFile(true); err != nil {
+		t.Fatalf("closeOutputFile() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"state": "on"`) {
+		t.Errorf("output file = %q, want the JSON output", data)
+	}
+	if stateGetCmd.OutOrStdout() != os.Stdout {
+		t.Error("closeOutputFile() left the output redirected")
+	}
+}
+
+func TestOutputFile_HumanOutput(t *testing.T) {
+	mock := testutil.NewRESTMock(t, testToken)
+	mock.HandleJSON(http.MethodGet, "/api/states/light.kitchen", http.StatusOK, api.State{
+		EntityID: "light.kitchen",
+		State:    "on",
+	})
+
+	defer func() {
+		serverURL, token, configPath, outputFile = "", "", "", ""
+		rootCmd.SetArgs(nil)
+	}()
+
+	path := filepath.Join(t.TempDir(), "out.txt")
+	base := []string{"--url", mock.URL(), "--token", testToken, "--config", filepath.Join(t.TempDir(), "none.yaml"), "--output-file", path}
+
+	rootCmd.SetArgs(append(base, "state", "get", "light.kitchen"))
+	stdout := captureStdout(t, func() {
+		if err := Execute(); err != nil {
+			t.Errorf("state get error = %v", err)
+		}
+	})
+	if strings.TrimSpace(stdout) != "" {
+		t.Errorf("state get wrote to stdout:\n%s", stdout)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !strings.Contains(string(data), "light.kitchen") {
+		t.Errorf("output file = %q, want the state", data)
+	}
+
+	// A failing command leaves the previous output alone
+	rootCmd.SetArgs(append(base, "state", "get", "light.missing"))
+	if err := Execute(); err == nil {
+		t.Fatal("state get light.missing expected error")
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(after) != string(data) {
+		t.Errorf("failed command changed the output file to %q", after)
+	}
+}
+
+// captureStdout returns what fn writes to os.Stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Pipe() error = %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- data
+	}()
+
+	fn()
+	w.Close()
+	return string(<-done)
+}
+
+func TestVerboseJSON_StdoutOnlyHasData(t *testing.T) {
+	mock := testutil.NewRESTMock(t, testToken)
+	mock.HandleJSON(http.MethodGet, "/api/states/light.kitchen", http.StatusOK, api.State{
+		EntityID:   "light.kitchen",
+		State:      "on",
+		Attributes: map[string]interface{}{"brightness": 200.0},
+	})
+	mock.HandleJSON(http.MethodPost, "/api/states/light.kitchen", http.StatusOK, api.State{EntityID: "light.kitchen", State: "off"})
+	mock.HandleJSON(http.MethodPost, "/api/services/light/turn_on", http.StatusOK, []api.State{{EntityID: "light.kitchen", State: "on"}})
+	mock.HandleJSON(http.MethodPost, "/api/config/scene/config/*", http.StatusOK, map[string]string{"result": "ok"})
+
+	defer func() {
+		jsonOutput, verbose, serverURL, token, configPath = false, false, "", "", ""
+		outputFormat = "table"
+		stateForce, stateNoValidate, callNoValidate = false, false, false
+		sceneEntities, callEntityIDs = []string{}, nil
+		rootCmd.SetArgs(nil)
+	}()
+
+	run := func(args ...string) string {
+		// Flag variables keep their values between runs of rootCmd
+		jsonOutput, outputFormat, callEntityIDs = false, "table", nil
+		base := []string{"--verbose", "--url", mock.URL(), "--token", testToken, "--config", filepath.Join(t.TempDir(), "none.yaml")}
+		rootCmd.SetArgs(append(base, args...))
+		return captureStdout(t, func() {
+			if err := rootCmd.Execute(); err != nil {
+				t.Errorf("%v: error = %v", args, err)
+			}
+		})
+	}
+
+	out := run("--json", "state", "get", "light.kitchen")
+	var state api.State
+	if err := json.Unmarshal([]byte(out), &state); err != nil {
+		t.Errorf("state get stdout is not JSON: %v\n%s", err, out)
+	} else if state.EntityID != "light.kitchen" {
+		t.Errorf("state get entity_id = %q", state.EntityID)
+	}
+
+	if out := run("--json", "scenes", "create", "Movie Night", "-e", "light.kitchen"); strings.TrimSpace(out) != "" {
+		t.Errorf("scenes create wrote to stdout:\n%s", out)
+	}
+
+	for _, args := range [][]string{
+		{"state", "set", "light.kitchen", "off", "--force", "--no-validate"},
+		{"call", "light.turn_on", "-e", "light.kitchen", "--no-validate"},
+	} {
+		out := run(append([]string{"--json"}, args...)...)
+		var v interface{}
+		if err := json.Unmarshal([]byte(out), &v); err != nil {
+			t.Errorf("%v stdout is not JSON: %v\n%s", args, err, out)
+		}
+
+		// Without --json the data stays on stdout but the status line does not
+		out = run(args...)
+		if strings.Contains(out, "successfully") || !strings.Contains(out, "light.kitchen") {
+			t.Errorf("%v stdout = %q, want the data without the status line", args, out)
+		}
+	}
+}
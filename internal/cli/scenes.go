@@ -2,11 +2,9 @@ package cli
 
 import (
 	"fmt"
-	"os"
 	"sort"
 	"strconv"
 	"strings"
-	"text/tabwriter"
 	"time"
 
 	"github.com/dorinclisu/hass-cli/internal/api"
@@ -19,14 +17,17 @@ var scenesCmd = &cobra.Command{
 	Long: `List and manage Home Assistant scenes.
 
 Scenes capture the state of multiple entities and can be activated to restore
-those states. Use 'hass-cli call scene.turn_on -e scene.<name>' to activate.
+those states. Use 'hass-cli scenes apply <scene>' to activate one.
 
 Examples:
   hass-cli scenes                        # List all scenes
   hass-cli scenes --json                 # Output as JSON
   hass-cli scenes inspect <scene_id>     # Show scene configuration
+  hass-cli scenes apply "Movie Night"    # Activate a scene
   hass-cli scenes create "Movie Night"   # Create scene from current states
-  hass-cli scenes delete <scene_id>      # Delete a scene`,
+  hass-cli scenes delete <scene_id>      # Delete a scene
+  hass-cli scenes export -o scenes.yaml  # Export all scenes to a file
+  hass-cli scenes import scenes.yaml     # Recreate scenes from a file`,
 	RunE: runScenes,
 }
 
@@ -73,6 +74,21 @@ Examples:
 	RunE: runScenesDelete,
 }
 
+var scenesApplyCmd = &cobra.Command{
+	Use:   "apply <scene>",
+	Short: "Activate a scene",
+	Long: `Activate a scene, given its config ID, entity ID or name.
+
+The name is matched case-insensitively against the scene's friendly name.
+
+Examples:
+  hass-cli scenes apply "Movie Night"
+  hass-cli scenes apply scene.movie_night
+  hass-cli scenes apply 1767672291452 --transition 2`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScenesApply,
+}
+
 var scenesAddEntityCmd = &cobra.Command{
 	Use:   "add-entity <scene_id> <entity_id>",
 	Short: "Add an entity to a scene",
@@ -96,8 +112,9 @@ Examples:
 }
 
 var (
-	sceneEntities []string
-	sceneIcon     string
+	sceneEntities   []string
+	sceneIcon       string
+	sceneTransition float64
 )
 
 func init() {
@@ -105,11 +122,19 @@ func init() {
 	scenesCmd.AddCommand(scenesInspectCmd)
 	scenesCmd.AddCommand(scenesCreateCmd)
 	scenesCmd.AddCommand(scenesDeleteCmd)
+	scenesCmd.AddCommand(scenesApplyCmd)
 	scenesCmd.AddCommand(scenesAddEntityCmd)
 	scenesCmd.AddCommand(scenesRemoveEntityCmd)
 
 	scenesCreateCmd.Flags().StringArrayVarP(&sceneEntities, "entity", "e", []string{}, "Entity to include in scene (can be specified multiple times)")
 	scenesCreateCmd.Flags().StringVar(&sceneIcon, "icon", "", "Icon for the scene (e.g., mdi:movie)")
+
+	scenesApplyCmd.Flags().Float64Var(&sceneTransition, "transition", 0, "Transition time in seconds for lights that support it")
+
+	addReloadFlag(scenesCreateCmd)
+	addReloadFlag(scenesDeleteCmd)
+	addReloadFlag(scenesAddEntityCmd)
+	addReloadFlag(scenesRemoveEntityCmd)
 }
 
 // SceneInfo combines scene entity info with config details.
@@ -128,7 +153,7 @@ func runScenes(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	printInfo("Fetching scenes...")
 	states, err := client.GetStates()
@@ -136,7 +161,12 @@ func runScenes(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get states: %w", err)
 	}
 
-	// Filter for scene entities
+	scenes := sceneInfos(states)
+	return outputData(cmd.OutOrStdout(), scenes, scenesTable(scenes))
+}
+
+// sceneInfos collects the scene entities among states, sorted by name.
+func sceneInfos(states []api.State) []SceneInfo {
 	var scenes []SceneInfo
 	for _, state := range states {
 		if !strings.HasPrefix(state.EntityID, "scene.") {
@@ -176,29 +206,51 @@ func runScenes(cmd *cobra.Command, args []string) error {
 		return strings.ToLower(scenes[i].Name) < strings.ToLower(scenes[j].Name)
 	})
 
-	if jsonOutput {
-		return outputJSON(scenes)
-	}
-
-	return outputScenesTable(scenes)
+	return scenes
 }
 
-func outputScenesTable(scenes []SceneInfo) error {
-	if len(scenes) == 0 {
-		fmt.Println("No scenes found")
-		return nil
+// resolveScene finds the scene that arg names: a config ID, an entity ID
+// (with or without the scene. prefix) or a friendly name.
+func resolveScene(scenes []SceneInfo, arg string) (*SceneInfo, error) {
+	entityID := arg
+	if !strings.HasPrefix(entityID, "scene.") {
+		entityID = "scene." + entityID
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "ENTITY ID\tNAME\tCONFIG ID\tICON")
-	fmt.Fprintln(w, "---------\t----\t---------\t----")
+	for i, s := range scenes {
+		if s.ConfigID == arg || s.EntityID == entityID {
+			return &scenes[i], nil
+		}
+	}
 
-	for _, s := range scenes {
-		name := s.Name
-		if len(name) > 30 {
-			name = name[:27] + "..."
+	var matches []*SceneInfo
+	for i, s := range scenes {
+		if strings.EqualFold(s.Name, strings.TrimSpace(arg)) {
+			matches = append(matches, &scenes[i])
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("scene not found: %s", arg)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, m := range matches {
+			ids[i] = m.EntityID
 		}
+		return nil, fmt.Errorf("%q matches several scenes: %s", arg, strings.Join(ids, ", "))
+	}
+}
 
+func scenesTable(scenes []SceneInfo) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "ENTITY ID"}, {Header: "NAME", Width: 30}, {Header: "CONFIG ID"}, {Header: "ICON"}},
+		Noun:    "scenes",
+		Empty:   "No scenes found",
+	}
+
+	for _, s := range scenes {
 		configID := s.ConfigID
 		if configID == "" {
 			configID = "-"
@@ -209,18 +261,15 @@ func outputScenesTable(scenes []SceneInfo) error {
 			icon = "-"
 		}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+		t.addRow(
 			s.EntityID,
-			name,
+			s.Name,
 			configID,
 			icon,
 		)
 	}
 
-	w.Flush()
-	fmt.Printf("\nTotal: %d scenes\n", len(scenes))
-
-	return nil
+	return t
 }
 
 func runScenesInspect(cmd *cobra.Command, args []string) error {
@@ -231,7 +280,7 @@ func runScenesInspect(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	printInfo("Fetching scene configuration...")
 	config, err := client.GetSceneConfig(sceneID)
@@ -240,13 +289,13 @@ func runScenesInspect(cmd *cobra.Command, args []string) error {
 		if strings.HasPrefix(sceneID, "scene.") {
 			state, stateErr := client.GetState(sceneID)
 			if stateErr == nil {
-				return outputJSON(state)
+				return outputJSON(cmd.OutOrStdout(), state)
 			}
 		}
 		return fmt.Errorf("failed to get scene: %w", err)
 	}
 
-	return outputJSON(config)
+	return outputJSON(cmd.OutOrStdout(), config)
 }
 
 func runScenesCreate(cmd *cobra.Command, args []string) error {
@@ -261,7 +310,7 @@ func runScenesCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	// Generate a unique ID based on timestamp
 	sceneID := strconv.FormatInt(time.Now().UnixMilli(), 10)
@@ -305,11 +354,9 @@ func runScenesCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create scene: %w", err)
 	}
 
-	fmt.Printf("Scene created: %s (ID: %s)\n", name, sceneID)
-	fmt.Printf("Entity ID will be: scene.%s\n", slugify(name))
-	fmt.Println("\nNote: You may need to reload scenes or restart Home Assistant for the new scene to appear.")
-
-	return nil
+	printSuccess("Scene created: %s (ID: %s)", name, sceneID)
+	printSuccess("Entity ID will be: scene.%s", slugify(name))
+	return reloadOrNote(cfg, "scene", "You may need to reload scenes or restart Home Assistant for the new scene to appear.")
 }
 
 func runScenesDelete(cmd *cobra.Command, args []string) error {
@@ -320,16 +367,51 @@ func runScenesDelete(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	printInfo("Deleting scene %s...", sceneID)
 	if err := client.DeleteScene(sceneID); err != nil {
 		return fmt.Errorf("failed to delete scene: %w", err)
 	}
 
-	fmt.Printf("Scene deleted: %s\n", sceneID)
-	fmt.Println("\nNote: You may need to reload scenes or restart Home Assistant for the change to take effect.")
+	printSuccess("Scene deleted: %s", sceneID)
+	return reloadOrNote(cfg, "scene", "You may need to reload scenes or restart Home Assistant for the change to take effect.")
+}
+
+func runScenesApply(cmd *cobra.Command, args []string) error {
+	if sceneTransition < 0 {
+		return fmt.Errorf("--transition must not be negative")
+	}
 
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	printInfo("Fetching scenes...")
+	states, err := client.GetStates()
+	if err != nil {
+		return fmt.Errorf("failed to get states: %w", err)
+	}
+
+	scene, err := resolveScene(sceneInfos(states), args[0])
+	if err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{"entity_id": scene.EntityID}
+	if cmd.Flags().Changed("transition") {
+		data["transition"] = sceneTransition
+	}
+
+	printInfo("Activating %s...", scene.EntityID)
+	if _, err := client.CallService("scene", "turn_on", data); err != nil {
+		return fmt.Errorf("failed to activate scene: %w", err)
+	}
+
+	printSuccess("Activated scene %s", scene.EntityID)
 	return nil
 }
 
@@ -342,7 +424,7 @@ func runScenesAddEntity(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	// Get existing scene config
 	printInfo("Fetching scene configuration...")
@@ -383,9 +465,9 @@ func runScenesAddEntity(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to update scene: %w", err)
 	}
 
-	fmt.Printf("Added %s to scene %s\n", entityID, config.Name)
+	printSuccess("Added %s to scene %s", entityID, config.Name)
 
-	return nil
+	return reloadOrNote(cfg, "scene", "")
 }
 
 func runScenesRemoveEntity(cmd *cobra.Command, args []string) error {
@@ -397,7 +479,7 @@ func runScenesRemoveEntity(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	// Get existing scene config
 	printInfo("Fetching scene configuration...")
@@ -419,9 +501,9 @@ func runScenesRemoveEntity(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to update scene: %w", err)
 	}
 
-	fmt.Printf("Removed %s from scene %s\n", entityID, config.Name)
+	printSuccess("Removed %s from scene %s", entityID, config.Name)
 
-	return nil
+	return reloadOrNote(cfg, "scene", "")
 }
 
 // slugify converts a name to a slug suitable for entity IDs.
@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var scenesExportCmd = &cobra.Command{
+	Use:   "export [scene]",
+	Short: "Export scene configurations to a YAML or JSON file",
+	Long: `Export the configuration of one scene, or of every scene, to a file that
+'hass-cli scenes import' can recreate them from.
+
+The scene is given by config ID, entity ID or name. Without one, every scene
+created in the UI is exported; scenes defined in YAML have no config ID and
+are skipped.
+
+The file is written as JSON when --output ends in .json or --json is given,
+and as YAML otherwise. Without --output it is written to stdout.
+
+Examples:
+  hass-cli scenes export -o scenes.yaml
+  hass-cli scenes export "Movie Night" -o movie_night.json
+  hass-cli scenes export 1767672291452`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runScenesExport,
+}
+
+var scenesImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Create scenes from an exported YAML or JSON file",
+	Long: `Create the scenes in a file written by 'hass-cli scenes export'.
+
+Each scene keeps its config ID, so importing into the instance it was exported
+from overwrites the scenes with those IDs. Use --new-ids to give every scene a
+fresh ID instead, e.g. to duplicate scenes or to import them into another
+instance. Scenes without an ID always get a new one.
+
+The file is YAML or JSON, either an object with a "scenes" list or just the
+list itself.
+
+Examples:
+  hass-cli scenes import scenes.yaml
+  hass-cli scenes import movie_night.json --new-ids --reload`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScenesImport,
+}
+
+var (
+	sceneExportOutput string
+	sceneImportNewIDs bool
+)
+
+func init() {
+	scenesCmd.AddCommand(scenesExportCmd)
+	scenesCmd.AddCommand(scenesImportCmd)
+
+	scenesExportCmd.Flags().StringVarP(&sceneExportOutput, "output", "o", "", "File to write the scenes to (default: stdout)")
+	scenesImportCmd.Flags().BoolVar(&sceneImportNewIDs, "new-ids", false, "Give every imported scene a new timestamp-based ID")
+
+	addReloadFlag(scenesImportCmd)
+}
+
+// SceneFile is the top-level structure of a scenes export file.
+type SceneFile struct {
+	Scenes []api.SceneConfig `json:"scenes" yaml:"scenes"`
+}
+
+func runScenesExport(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	printInfo("Fetching scenes...")
+	states, err := client.GetStates()
+	if err != nil {
+		return fmt.Errorf("failed to get states: %w", err)
+	}
+
+	scenes := sceneInfos(states)
+	if len(args) == 1 {
+		scene, err := resolveScene(scenes, args[0])
+		if err != nil {
+			return err
+		}
+		if scene.ConfigID == "" {
+			return fmt.Errorf("%s has no config ID; only scenes created in the UI can be exported", scene.EntityID)
+		}
+		scenes = []SceneInfo{*scene}
+	}
+
+	file := SceneFile{Scenes: []api.SceneConfig{}}
+	for _, scene := range scenes {
+		if scene.ConfigID == "" {
+			printInfo("Skipping %s (no config ID)", scene.EntityID)
+			continue
+		}
+
+		printInfo("Fetching configuration of %s...", scene.EntityID)
+		config, err := client.GetSceneConfig(scene.ConfigID)
+		if err != nil {
+			return fmt.Errorf("failed to get scene %s: %w", scene.ConfigID, err)
+		}
+		file.Scenes = append(file.Scenes, *config)
+	}
+
+	asJSON := exportAsJSON(sceneExportOutput)
+
+	if sceneExportOutput == "" {
+		return writeExport(cmd.OutOrStdout(), &file, asJSON)
+	}
+
+	out, err := os.Create(sceneExportOutput)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer out.Close()
+
+	if err := writeExport(out, &file, asJSON); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	printSuccess("Exported %d scenes to %s", len(file.Scenes), sceneExportOutput)
+	return nil
+}
+
+// loadSceneFile reads and validates a scenes export file.
+func loadSceneFile(path string) (*SceneFile, error) {
+	var file SceneFile
+	if err := readExport(path, "scenes", &file.Scenes); err != nil {
+		return nil, err
+	}
+
+	if len(file.Scenes) == 0 {
+		return nil, fmt.Errorf("scenes file declares no scenes")
+	}
+
+	var problems []string
+	for i, scene := range file.Scenes {
+		switch {
+		case strings.TrimSpace(scene.Name) == "":
+			problems = append(problems, fmt.Sprintf("  scenes[%d]: name is required", i))
+		case len(scene.Entities) == 0:
+			problems = append(problems, fmt.Sprintf("  scenes[%d] (%s): at least one entity is required", i, scene.Name))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid scenes file:\n%s", strings.Join(problems, "\n"))
+	}
+
+	return &file, nil
+}
+
+func runScenesImport(cmd *cobra.Command, args []string) error {
+	file, err := loadSceneFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	assignConfigIDs(file.Scenes, func(s *api.SceneConfig) *string { return &s.ID }, sceneImportNewIDs, time.Now())
+
+	for i := range file.Scenes {
+		scene := &file.Scenes[i]
+		printInfo("Creating scene '%s'...", scene.Name)
+		if err := client.CreateScene(scene.ID, scene); err != nil {
+			return fmt.Errorf("failed to create scene %q: %w", scene.Name, err)
+		}
+		printSuccess("Scene imported: %s (ID: %s)", scene.Name, scene.ID)
+	}
+
+	printFooter(os.Stderr, "Imported: %d", len(file.Scenes))
+	return reloadOrNote(cfg, "scene", "You may need to reload scenes or restart Home Assistant for the imported scenes to appear.")
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+)
+
+func TestSceneFile_RoundTrip(t *testing.T) {
+	file := &SceneFile{Scenes: []api.SceneConfig{{
+		ID:   "1767672291452",
+		Name: "Movie Night",
+		Icon: "mdi:movie",
+		Entities: map[string]map[string]interface{}{
+			"light.living_room": {"state": "on", "brightness": 80},
+		},
+	}}}
+
+	for _, asJSON := range []bool{false, true} {
+		var buf bytes.Buffer
+		if err := writeExport(&buf, file, asJSON); err != nil {
+			t.Fatalf("writeExport(json=%v) error = %v", asJSON, err)
+		}
+
+		path := filepath.Join(t.TempDir(), "scenes")
+		if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := loadSceneFile(path)
+		if err != nil {
+			t.Fatalf("loadSceneFile(json=%v) error = %v\n%s", asJSON, err, buf.String())
+		}
+		if len(got.Scenes) != 1 {
+			t.Fatalf("got %d scenes, want 1", len(got.Scenes))
+		}
+		scene := got.Scenes[0]
+		if scene.ID != "1767672291452" || scene.Name != "Movie Night" || scene.Icon != "mdi:movie" {
+			t.Errorf("scene = %+v", scene)
+		}
+		if state := scene.Entities["light.living_room"]["state"]; state != "on" {
+			t.Errorf("light.living_room state = %v, want on", state)
+		}
+	}
+}
+
+func TestLoadSceneFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+		wantErr string
+	}{
+		{name: "bare list", content: "- name: Relax\n  entities:\n    light.bedroom: {state: off}\n", want: 1},
+		{name: "json list", content: `[{"name": "Relax", "entities": {"light.bedroom": {"state": "off"}}}]`, want: 1},
+		{name: "no scenes", content: "scenes: []\n", wantErr: "no scenes"},
+		{name: "missing name", content: "scenes:\n  - entities:\n      light.bedroom: {state: off}\n", wantErr: "name is required"},
+		{name: "missing entities", content: "scenes:\n  - name: Empty\n", wantErr: "at least one entity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "scenes.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := loadSceneFile(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("loadSceneFile() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadSceneFile() error = %v", err)
+			}
+			if len(got.Scenes) != tt.want {
+				t.Errorf("got %d scenes, want %d", len(got.Scenes), tt.want)
+			}
+		})
+	}
+}
+
+func TestAssignConfigIDs(t *testing.T) {
+	now := time.UnixMilli(1767672291452)
+	sceneID := func(s *api.SceneConfig) *string { return &s.ID }
+	ids := func(scenes []api.SceneConfig) string {
+		var out []string
+		for _, s := range scenes {
+			out = append(out, s.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	scenes := []api.SceneConfig{{ID: "1"}, {}, {ID: "2"}, {}}
+	assignConfigIDs(scenes, sceneID, false, now)
+	if got := ids(scenes); got != "1,1767672291452,2,1767672291453" {
+		t.Errorf("assignConfigIDs(newIDs=false) = %s", got)
+	}
+
+	assignConfigIDs(scenes, sceneID, true, now)
+	if got := ids(scenes); got != "1767672291452,1767672291453,1767672291454,1767672291455" {
+		t.Errorf("assignConfigIDs(newIDs=true) = %s", got)
+	}
+}
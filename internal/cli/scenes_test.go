@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -81,3 +82,43 @@ func TestSlugify(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveScene(t *testing.T) {
+	scenes := []SceneInfo{
+		{EntityID: "scene.movie_night", Name: "Movie Night", ConfigID: "1767672291452"},
+		{EntityID: "scene.relax", Name: "Relax"},
+		{EntityID: "scene.relax_2", Name: "relax"},
+	}
+
+	tests := []struct {
+		arg     string
+		want    string
+		wantErr string
+	}{
+		{arg: "1767672291452", want: "scene.movie_night"},
+		{arg: "scene.movie_night", want: "scene.movie_night"},
+		{arg: "movie_night", want: "scene.movie_night"},
+		{arg: "movie night", want: "scene.movie_night"},
+		{arg: "relax_2", want: "scene.relax_2"},
+		{arg: "Relax", wantErr: "several scenes"},
+		{arg: "Dinner", wantErr: "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got, err := resolveScene(scenes, tt.arg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("resolveScene(%q) error = %v, want %q", tt.arg, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveScene(%q) error = %v", tt.arg, err)
+			}
+			if got.EntityID != tt.want {
+				t.Errorf("resolveScene(%q) = %s, want %s", tt.arg, got.EntityID, tt.want)
+			}
+		})
+	}
+}
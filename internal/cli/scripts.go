@@ -3,10 +3,8 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"sort"
 	"strings"
-	"text/tabwriter"
 	"time"
 
 	"github.com/dorinclisu/hass-cli/internal/api"
@@ -27,6 +25,7 @@ Examples:
   hass-cli scripts --json                 # Output as JSON
   hass-cli scripts inspect <script_id>    # Show script configuration
   hass-cli scripts create <name>          # Create a new script
+  hass-cli scripts add-step <id> <json>   # Append a step to the sequence
   hass-cli scripts run <script_id>        # Trigger a script
   hass-cli scripts debug <script_id>      # Show execution traces
   hass-cli scripts delete <script_id>     # Delete a script`,
@@ -73,7 +72,9 @@ Use flags to update specific properties, or provide a complete sequence via --se
 Examples:
   hass-cli scripts edit hello_world --alias "Hello World Updated"
   hass-cli scripts edit hello_world --description "Updated description"
-  hass-cli scripts edit hello_world --sequence '[{"service":"light.turn_on"}]'`,
+  hass-cli scripts edit hello_world --sequence '[{"service":"light.turn_on"}]'
+  hass-cli scripts edit hello_world --sequence '[...]' --diff      # Review, then confirm
+  hass-cli scripts edit hello_world --sequence '[...]' --dry-run   # Only show the changes`,
 	Args: cobra.ExactArgs(1),
 	RunE: runScriptsEdit,
 }
@@ -111,10 +112,14 @@ var scriptsDebugCmd = &cobra.Command{
 
 This shows the history of script executions with timing and step information.
 Use --run-id to see details of a specific execution.
+Runs are listed newest first, up to --limit (default 20).
 
 Examples:
-  hass-cli scripts debug hello_world              # List all traces
-  hass-cli scripts debug hello_world --run-id <id>  # Show specific trace`,
+  hass-cli scripts debug hello_world              # List recent traces
+  hass-cli scripts debug hello_world --limit 0    # List every stored trace
+  hass-cli scripts debug hello_world --run-id <id>  # Show specific trace
+  hass-cli scripts debug hello_world --run-id <id> --pretty  # As a step tree
+  hass-cli scripts debug hello_world --since 30m    # Runs in the last 30 minutes`,
 	Args: cobra.ExactArgs(1),
 	RunE: runScriptsDebug,
 }
@@ -164,12 +169,20 @@ func init() {
 	scriptsEditCmd.Flags().StringVar(&scriptIcon, "icon", "", "New icon")
 	scriptsEditCmd.Flags().StringVar(&scriptMode, "mode", "", "New mode: single, restart, queued, parallel")
 	scriptsEditCmd.Flags().StringVar(&scriptSequence, "sequence", "", "New JSON array of actions")
+	addEditReviewFlags(scriptsEditCmd)
 
 	// Run flags
 	scriptsRunCmd.Flags().StringVar(&scriptRunData, "data", "", "JSON data to pass to the script")
 
 	// Debug flags
 	scriptsDebugCmd.Flags().StringVar(&scriptRunID, "run-id", "", "Specific run ID to inspect")
+	addTraceTimeFlags(scriptsDebugCmd)
+	addTraceLimitFlag(scriptsDebugCmd)
+	addTracePrettyFlag(scriptsDebugCmd)
+
+	addReloadFlag(scriptsCreateCmd)
+	addReloadFlag(scriptsEditCmd)
+	addReloadFlag(scriptsDeleteCmd)
 }
 
 // ScriptInfo combines script entity info with config details.
@@ -189,7 +202,7 @@ func runScripts(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	printInfo("Fetching scripts...")
 	states, err := client.GetStates()
@@ -245,52 +258,37 @@ func runScripts(cmd *cobra.Command, args []string) error {
 		return strings.ToLower(scripts[i].Name) < strings.ToLower(scripts[j].Name)
 	})
 
-	if jsonOutput {
-		return outputJSON(scripts)
-	}
-
-	return outputScriptsTable(scripts)
+	return outputData(cmd.OutOrStdout(), scripts, scriptsTable(scripts))
 }
 
-func outputScriptsTable(scripts []ScriptInfo) error {
-	if len(scripts) == 0 {
-		fmt.Println("No scripts found")
-		return nil
+func scriptsTable(scripts []ScriptInfo) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "ENTITY ID"}, {Header: "NAME", Width: 30}, {Header: "STATE"}, {Header: "MODE"}, {Header: "LAST TRIGGERED"}},
+		Noun:    "scripts",
+		Empty:   "No scripts found",
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "ENTITY ID\tNAME\tSTATE\tMODE\tLAST TRIGGERED")
-	fmt.Fprintln(w, "---------\t----\t-----\t----\t--------------")
-
 	for _, s := range scripts {
-		name := s.Name
-		if len(name) > 30 {
-			name = name[:27] + "..."
-		}
-
 		lastTriggered := s.LastTriggered
 		if lastTriggered != "" {
 			// Parse and format the timestamp
 			if t, err := time.Parse(time.RFC3339, lastTriggered); err == nil {
-				lastTriggered = t.Local().Format("2006-01-02 15:04:05")
+				lastTriggered = displayTime(t).Format("2006-01-02 15:04:05")
 			}
 		} else {
 			lastTriggered = "-"
 		}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+		t.addRow(
 			s.EntityID,
-			name,
+			s.Name,
 			s.State,
 			s.Mode,
 			lastTriggered,
 		)
 	}
 
-	w.Flush()
-	fmt.Printf("\nTotal: %d scripts\n", len(scripts))
-
-	return nil
+	return t
 }
 
 func runScriptsInspect(cmd *cobra.Command, args []string) error {
@@ -301,7 +299,7 @@ func runScriptsInspect(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	printInfo("Fetching script configuration...")
 	config, err := client.GetScriptConfig(scriptID)
@@ -310,13 +308,13 @@ func runScriptsInspect(cmd *cobra.Command, args []string) error {
 		if strings.HasPrefix(args[0], "script.") {
 			state, stateErr := client.GetState(args[0])
 			if stateErr == nil {
-				return outputJSON(state)
+				return outputJSON(cmd.OutOrStdout(), state)
 			}
 		}
 		return fmt.Errorf("failed to get script: %w", err)
 	}
 
-	return outputJSON(config)
+	return outputJSON(cmd.OutOrStdout(), config)
 }
 
 func runScriptsCreate(cmd *cobra.Command, args []string) error {
@@ -327,7 +325,7 @@ func runScriptsCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	// Parse sequence if provided
 	var sequence []map[string]interface{}
@@ -360,11 +358,9 @@ func runScriptsCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create script: %w", err)
 	}
 
-	fmt.Printf("Script created: %s\n", name)
-	fmt.Printf("Entity ID: script.%s\n", scriptID)
-	fmt.Println("\nNote: You may need to reload scripts or restart Home Assistant for the new script to appear.")
-
-	return nil
+	printSuccess("Script created: %s", name)
+	printSuccess("Entity ID: script.%s", scriptID)
+	return reloadOrNote(cfg, "script", "You may need to reload scripts or restart Home Assistant for the new script to appear.")
 }
 
 func runScriptsEdit(cmd *cobra.Command, args []string) error {
@@ -375,7 +371,7 @@ func runScriptsEdit(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	// Get existing config
 	printInfo("Fetching current script configuration...")
@@ -384,6 +380,8 @@ func runScriptsEdit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get script: %w", err)
 	}
 
+	before := *config
+
 	// Apply updates
 	if scriptAlias != "" {
 		config.Alias = scriptAlias
@@ -405,14 +403,19 @@ func runScriptsEdit(cmd *cobra.Command, args []string) error {
 		config.Sequence = sequence
 	}
 
+	apply, err := reviewEdit(cmd.OutOrStdout(), before, config)
+	if err != nil || !apply {
+		return err
+	}
+
 	printInfo("Updating script...")
 	if err := client.UpdateScript(scriptID, config); err != nil {
 		return fmt.Errorf("failed to update script: %w", err)
 	}
 
-	fmt.Printf("Script updated: %s\n", config.Alias)
+	printSuccess("Script updated: %s", config.Alias)
 
-	return nil
+	return reloadOrNote(cfg, "script", "")
 }
 
 func runScriptsRename(cmd *cobra.Command, args []string) error {
@@ -424,7 +427,7 @@ func runScriptsRename(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	// Get existing config
 	printInfo("Fetching current script configuration...")
@@ -441,7 +444,7 @@ func runScriptsRename(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to rename script: %w", err)
 	}
 
-	fmt.Printf("Script renamed: '%s' -> '%s'\n", oldName, newName)
+	printSuccess("Script renamed: '%s' -> '%s'", oldName, newName)
 
 	return nil
 }
@@ -454,7 +457,7 @@ func runScriptsRun(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	// Parse data if provided
 	var data map[string]interface{}
@@ -484,7 +487,7 @@ func runScriptsDebug(cmd *cobra.Command, args []string) error {
 	}
 
 	printInfo("Connecting to Home Assistant...")
-	wsClient, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	wsClient, err := newWSClient(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to connect: %w", err)
 	}
@@ -498,7 +501,7 @@ func runScriptsDebug(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to get trace: %w", err)
 		}
 
-		return outputJSON(trace)
+		return outputTrace(cmd.OutOrStdout(), trace)
 	}
 
 	// List all traces
@@ -508,57 +511,40 @@ func runScriptsDebug(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to list traces: %w", err)
 	}
 
-	if jsonOutput {
-		return outputJSON(traces)
+	traces, err = applyTraceTimeFlags(traces)
+	if err != nil {
+		return err
 	}
+	traces = applyTraceLimit(traces)
 
-	return outputTracesTable(traces)
+	return outputData(cmd.OutOrStdout(), traces, tracesTable(traces))
 }
 
-func outputTracesTable(traces []websocket.TraceSummary) error {
-	if len(traces) == 0 {
-		fmt.Println("No traces found")
-		return nil
+// tracesTable lists script or automation traces.
+func tracesTable(traces []websocket.TraceSummary) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "RUN ID"}, {Header: "STATE"}, {Header: "RESULT"}, {Header: "STARTED"}, {Header: "DURATION"}},
+		Noun:    "traces",
+		Empty:   "No traces found",
+		Hint:    "Use --run-id <id> to see detailed trace information",
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "RUN ID\tSTATE\tRESULT\tSTARTED\tDURATION")
-	fmt.Fprintln(w, "------\t-----\t------\t-------\t--------")
-
-	for _, t := range traces {
-		started := t.Timestamp.Start
-		if s, err := time.Parse(time.RFC3339, t.Timestamp.Start); err == nil {
-			started = s.Local().Format("2006-01-02 15:04:05")
-		}
-
-		duration := ""
-		if t.Timestamp.Start != "" && t.Timestamp.Finish != "" {
-			start, err1 := time.Parse(time.RFC3339, t.Timestamp.Start)
-			finish, err2 := time.Parse(time.RFC3339, t.Timestamp.Finish)
-			if err1 == nil && err2 == nil {
-				d := finish.Sub(start)
-				if d < time.Second {
-					duration = fmt.Sprintf("%dms", d.Milliseconds())
-				} else {
-					duration = d.Round(time.Millisecond).String()
-				}
-			}
+	for _, tr := range traces {
+		started := tr.Timestamp.Start
+		if s, err := time.Parse(time.RFC3339, tr.Timestamp.Start); err == nil {
+			started = displayTime(s).Format("2006-01-02 15:04:05")
 		}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-			t.RunID,
-			t.State,
-			t.ScriptExecution,
+		t.addRow(
+			tr.RunID,
+			tr.State,
+			tr.ScriptExecution,
 			started,
-			duration,
+			traceDuration(tr.Timestamp),
 		)
 	}
 
-	w.Flush()
-	fmt.Printf("\nTotal: %d traces\n", len(traces))
-	fmt.Println("\nUse --run-id <id> to see detailed trace information")
-
-	return nil
+	return t
 }
 
 func runScriptsDelete(cmd *cobra.Command, args []string) error {
@@ -569,7 +555,7 @@ func runScriptsDelete(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	printInfo("Deleting script '%s'...", scriptID)
 	if err := client.DeleteScript(scriptID); err != nil {
@@ -577,9 +563,7 @@ func runScriptsDelete(cmd *cobra.Command, args []string) error {
 	}
 
 	printSuccess("Script deleted: %s", scriptID)
-	fmt.Println("\nNote: You may need to reload scripts or restart Home Assistant for the change to take effect.")
-
-	return nil
+	return reloadOrNote(cfg, "script", "You may need to reload scripts or restart Home Assistant for the change to take effect.")
 }
 
 // normalizeScriptID extracts the script ID from an entity ID if needed.
@@ -2,11 +2,8 @@ package cli
 
 import (
 	"fmt"
-	"os"
 	"sort"
 	"strings"
-	"text/tabwriter"
-	"time"
 
 	"github.com/dorinclisu/hass-cli/internal/api"
 	"github.com/spf13/cobra"
@@ -61,7 +58,7 @@ func runServices(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	printInfo("Fetching services...")
 	services, err := client.GetServices()
@@ -95,46 +92,25 @@ func runServices(cmd *cobra.Command, args []string) error {
 		return items[i].Service < items[j].Service
 	})
 
-	if jsonOutput {
-		return outputJSON(items)
-	}
-
-	return outputServicesTable(items)
+	return outputData(cmd.OutOrStdout(), items, servicesTable(items))
 }
 
-func outputServicesTable(services []ServiceListItem) error {
-	if len(services) == 0 {
-		fmt.Println("No services found")
-		return nil
+func servicesTable(services []ServiceListItem) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "SERVICE"}, {Header: "NAME", Width: 25}, {Header: "DESCRIPTION", Width: 50}},
+		Noun:    "services",
+		Empty:   "No services found",
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "SERVICE\tNAME\tDESCRIPTION")
-	fmt.Fprintln(w, "-------\t----\t-----------")
-
 	for _, s := range services {
-		name := s.Name
-		if len(name) > 25 {
-			name = name[:22] + "..."
-		}
-
-		desc := s.Description
-		if len(desc) > 50 {
-			desc = desc[:47] + "..."
-		}
-
-		fmt.Fprintf(w, "%s.%s\t%s\t%s\n",
-			s.Domain,
-			s.Service,
-			name,
-			desc,
+		t.addRow(
+			s.Domain+"."+s.Service,
+			s.Name,
+			s.Description,
 		)
 	}
 
-	w.Flush()
-	fmt.Printf("\nTotal: %d services\n", len(services))
-
-	return nil
+	return t
 }
 
 // ServiceDetail contains detailed service info.
@@ -162,7 +138,7 @@ func runServicesInspect(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	printInfo("Fetching service details...")
 	services, err := client.GetServices()
@@ -190,40 +166,41 @@ func runServicesInspect(cmd *cobra.Command, args []string) error {
 	}
 
 	if jsonOutput {
-		return outputJSON(detail)
+		return outputJSON(cmd.OutOrStdout(), detail)
 	}
 
 	// Human-readable output
-	fmt.Printf("Service:       %s.%s\n", domain, service)
-	fmt.Printf("Name:          %s\n", svcInfo.Name)
-	fmt.Printf("Description:   %s\n", svcInfo.Description)
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Service:       %s.%s\n", domain, service)
+	fmt.Fprintf(out, "Name:          %s\n", svcInfo.Name)
+	fmt.Fprintf(out, "Description:   %s\n", svcInfo.Description)
 
 	if svcInfo.Target != nil {
-		fmt.Println("\nTarget:")
+		fmt.Fprintln(out, "\nTarget:")
 		if len(svcInfo.Target.Entity) > 0 {
-			fmt.Println("  - Entities")
+			fmt.Fprintln(out, "  - Entities")
 		}
 		if len(svcInfo.Target.Device) > 0 {
-			fmt.Println("  - Devices")
+			fmt.Fprintln(out, "  - Devices")
 		}
 		if len(svcInfo.Target.Area) > 0 {
-			fmt.Println("  - Areas")
+			fmt.Fprintln(out, "  - Areas")
 		}
 	}
 
 	if len(svcInfo.Fields) > 0 {
-		fmt.Println("\nFields:")
+		fmt.Fprintln(out, "\nFields:")
 		for name, field := range svcInfo.Fields {
 			required := ""
 			if field.Required {
 				required = " (required)"
 			}
-			fmt.Printf("  %s%s\n", name, required)
+			fmt.Fprintf(out, "  %s%s\n", name, required)
 			if field.Description != "" {
-				fmt.Printf("    %s\n", field.Description)
+				fmt.Fprintf(out, "    %s\n", field.Description)
 			}
 			if field.Example != nil {
-				fmt.Printf("    Example: %v\n", field.Example)
+				fmt.Fprintf(out, "    Example: %v\n", field.Example)
 			}
 		}
 	}
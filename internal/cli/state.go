@@ -3,10 +3,14 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/dorinclisu/hass-cli/internal/config"
+	"github.com/dorinclisu/hass-cli/internal/websocket"
 	"github.com/spf13/cobra"
 )
 
@@ -17,19 +21,33 @@ var stateCmd = &cobra.Command{
 
 Examples:
   hass-cli state get light.living_room       # Get entity state
-  hass-cli state set light.living_room on    # Set entity state`,
+  hass-cli state set light.living_room on    # Set entity state
+  hass-cli state snapshot -d sensor          # Save sensor states as JSON`,
 }
 
 var stateGetCmd = &cobra.Command{
-	Use:   "get <entity_id>",
+	Use:   "get [entity_id]",
 	Short: "Get the current state of an entity",
 	Long: `Get the current state and attributes of an entity.
 
+--for prints how long the entity has been in its current state instead.
+--for-gt additionally sets the exit status: 0 if it has been in that state
+for longer than the given duration, 1 otherwise.
+
+--watch prints the current state and then every change of it until Ctrl+C,
+like 'hass-cli watch <entity_id>'. With --json each line is a compact state
+object.
+
+Without an entity ID, defaults.entity from the config file is used.
+
 Examples:
   hass-cli state get light.living_room
   hass-cli state get sensor.temperature
-  hass-cli state get light.living_room --json`,
-	Args: cobra.ExactArgs(1),
+  hass-cli state get light.living_room --json
+  hass-cli state get sensor.power --watch
+  hass-cli state get binary_sensor.front_door --for
+  hass-cli state get binary_sensor.front_door --for-gt 10m && echo "open too long"`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runStateGet,
 }
 
@@ -40,6 +58,10 @@ var stateSetCmd = &cobra.Command{
 in Home Assistant and does NOT communicate with the actual device.
 
 To control a device (e.g., turn on a light), use 'hass-cli call' instead.
+Entities that belong to a device are refused unless --force is given, since
+their integration overwrites the state on its next update. Entities that do
+not exist yet are refused unless --no-validate is given, so a typo is not
+mistaken for a new entity.
 
 This command is useful for:
 - Creating custom sensor entities
@@ -47,32 +69,102 @@ This command is useful for:
 - Setting states for template entities
 
 Examples:
-  hass-cli state set sensor.custom_value 42
+  hass-cli state set sensor.custom_value 42 --no-validate   # Create the entity
   hass-cli state set sensor.custom_value 42 --attr unit_of_measurement=°C
   hass-cli state set input_text.note "Hello World"`,
 	Args: cobra.ExactArgs(2),
 	RunE: runStateSet,
 }
 
-var stateAttributes []string
+var stateDeleteCmd = &cobra.Command{
+	Use:   "delete <entity_id>",
+	Short: "Remove the state of an entity",
+	Long: `Remove an entity's state object from Home Assistant.
+
+This is meant for states created with 'hass-cli state set' or through the
+API. It does not remove the entity from the registry, and an integration
+that provides the entity writes its state again on the next update.
+
+Examples:
+  hass-cli state delete sensor.custom_value`,
+	Args: cobra.ExactArgs(1),
+	RunE: runStateDelete,
+}
+
+var stateSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save the current states of entities to a JSON file",
+	Long: `Save the current state of every entity, or of the entities in the given
+domains, as a JSON array sorted by entity ID. The output is written to stdout
+unless --output is given.
+
+Examples:
+  hass-cli state snapshot -o states.json                 # All entities
+  hass-cli state snapshot -d sensor -o sensors.json      # One domain
+  hass-cli state snapshot -d light -d switch -o before.json`,
+	Args: cobra.NoArgs,
+	RunE: runStateSnapshot,
+}
+
+var (
+	stateAttributes     []string
+	stateForce          bool
+	stateNoValidate     bool
+	stateFor            bool
+	stateForGT          string
+	stateWatch          bool
+	stateSnapshotDomain []string
+	stateSnapshotOutput string
+)
 
 func init() {
 	rootCmd.AddCommand(stateCmd)
 	stateCmd.AddCommand(stateGetCmd)
 	stateCmd.AddCommand(stateSetCmd)
+	stateCmd.AddCommand(stateDeleteCmd)
+	stateCmd.AddCommand(stateSnapshotCmd)
+
+	stateGetCmd.Flags().BoolVar(&stateFor, "for", false, "Show how long the entity has been in its current state")
+	stateGetCmd.Flags().BoolVar(&stateWatch, "watch", false, "Keep running and print each change of the state")
+	stateGetCmd.Flags().StringVar(&stateForGT, "for-gt", "", "Exit 0 only if the current state has lasted longer than this (e.g., 10m, 2h, 1d); implies --for")
 
 	stateSetCmd.Flags().StringArrayVar(&stateAttributes, "attr", []string{}, "Set attribute (key=value), can be specified multiple times")
+	stateSetCmd.Flags().BoolVar(&stateForce, "force", false, "Set the state even if the entity belongs to a device")
+	stateSetCmd.Flags().BoolVar(&stateNoValidate, "no-validate", false, "Set the state even if the entity does not exist yet, e.g. to create it")
+
+	stateSnapshotCmd.Flags().StringSliceVarP(&stateSnapshotDomain, "domain", "d", nil, "Only include entities of this domain (repeatable)")
+	stateSnapshotCmd.Flags().StringVarP(&stateSnapshotOutput, "output", "o", "", "File to write the snapshot to (default: stdout)")
 }
 
 func runStateGet(cmd *cobra.Command, args []string) error {
-	entityID := args[0]
+	var threshold time.Duration
+	if stateForGT != "" {
+		d, err := parseAge(stateForGT)
+		if err != nil {
+			return fmt.Errorf("invalid --for-gt: %w", err)
+		}
+		threshold = d
+		stateFor = true
+	}
+	if stateWatch && stateFor {
+		return fmt.Errorf("--watch cannot be combined with --for or --for-gt")
+	}
 
 	cfg, err := loadConfig()
 	if err != nil {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	entityID, err := entityArg(cfg, args)
+	if err != nil {
+		return err
+	}
+
+	if stateWatch {
+		return runStateGetWatch(cmd, cfg, entityID)
+	}
+
+	client := newRESTClient(cfg)
 
 	printInfo("Fetching state for %s...", entityID)
 	state, err := client.GetState(entityID)
@@ -80,26 +172,165 @@ func runStateGet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get state: %w", err)
 	}
 
+	if stateFor {
+		return outputStateFor(cmd, state, threshold)
+	}
+
+	return printState(cmd, state)
+}
+
+// printState prints an entity's state and attributes, or the state object
+// with --json.
+func printState(cmd *cobra.Command, state *api.State) error {
 	if jsonOutput {
-		return outputJSON(state)
+		return outputJSON(cmd.OutOrStdout(), state)
 	}
 
 	// Human-readable output
-	fmt.Printf("Entity:        %s\n", state.EntityID)
-	fmt.Printf("State:         %s\n", state.State)
-	fmt.Printf("Last Changed:  %s\n", formatTime(state.LastChanged))
-	fmt.Printf("Last Updated:  %s\n", formatTime(state.LastUpdated))
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Entity:        %s\n", state.EntityID)
+	if unit := stateUnit(state.Attributes); unit != "" {
+		fmt.Fprintf(out, "State:         %s %s\n", state.State, unit)
+	} else {
+		fmt.Fprintf(out, "State:         %s\n", state.State)
+	}
+	fmt.Fprintf(out, "Last Changed:  %s\n", formatTime(state.LastChanged))
+	fmt.Fprintf(out, "Last Updated:  %s\n", formatTime(state.LastUpdated))
 
 	if len(state.Attributes) > 0 {
-		fmt.Println("\nAttributes:")
-		for key, value := range state.Attributes {
-			fmt.Printf("  %s: %v\n", key, value)
+		attrs := state.Attributes
+		if redactOutput {
+			attrs = redactValue(attrs).(map[string]interface{})
+		}
+		fmt.Fprintln(out, "\nAttributes:")
+		for key, value := range attrs {
+			fmt.Fprintf(out, "  %s: %v\n", key, value)
+		}
+	}
+
+	return nil
+}
+
+// runStateGetWatch prints the current state of entityID, then each change
+// of it until interrupted.
+func runStateGetWatch(cmd *cobra.Command, cfg *config.Config, entityID string) error {
+	// One state object per line, like watch --jsonl, so the output can be
+	// read line by line while it is still running
+	if jsonOutput {
+		compactJSON = true
+	}
+
+	patterns, err := parsePatterns([]string{entityID})
+	if err != nil {
+		return err
+	}
+
+	printInfo("Connecting to Home Assistant...")
+	wsClient, err := newWSClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer wsClient.Close()
+
+	// Subscribe before fetching the current state so that no change is
+	// missed in between
+	printInfo("Subscribing to state changes...")
+	stream, err := watchStateChanges(wsClient, patterns)
+	if err != nil {
+		return err
+	}
+
+	printInfo("Fetching state for %s...", entityID)
+	state, err := newRESTClient(cfg).GetState(entityID)
+	if err != nil {
+		return fmt.Errorf("failed to get state: %w", err)
+	}
+	if err := printState(cmd, state); err != nil {
+		return err
+	}
+	if !jsonOutput {
+		fmt.Fprintln(cmd.OutOrStdout())
+	}
+
+	return followStateChanges(wsClient, patterns, stream, func(event *websocket.EventData) {
+		if !jsonOutput {
+			printStateChange(cmd.OutOrStdout(), event)
+			return
+		}
+		newState := event.Data.NewState
+		if newState == nil {
+			newState = &websocket.StateObject{EntityID: event.Data.EntityID, State: "unavailable"}
 		}
+		outputJSON(cmd.OutOrStdout(), newState)
+	})
+}
+
+// StateDuration reports how long an entity has been in its current state.
+type StateDuration struct {
+	EntityID   string `json:"entity_id"`
+	State      string `json:"state"`
+	Since      string `json:"since"`
+	ForSeconds int64  `json:"for_seconds"`
+}
+
+// outputStateFor prints how long state has lasted. With a threshold (from
+// --for-gt) it returns a silent exit status 1 unless it has lasted longer.
+func outputStateFor(cmd *cobra.Command, state *api.State, threshold time.Duration) error {
+	since, err := time.Parse(time.RFC3339, state.LastChanged)
+	if err != nil {
+		return fmt.Errorf("%s has no last_changed time", state.EntityID)
 	}
+	elapsed := time.Since(since)
 
+	if jsonOutput {
+		err = outputJSON(cmd.OutOrStdout(), StateDuration{
+			EntityID:   state.EntityID,
+			State:      state.State,
+			Since:      state.LastChanged,
+			ForSeconds: int64(elapsed.Seconds()),
+		})
+	} else {
+		_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s has been %s for %s (since %s)\n",
+			state.EntityID, state.State, formatDuration(elapsed), displayTime(since).Format("2006-01-02 15:04:05"))
+	}
+	if err != nil {
+		return err
+	}
+
+	if stateForGT != "" && elapsed <= threshold {
+		return &ExitError{Code: 1}
+	}
 	return nil
 }
 
+// formatDuration renders d in its two largest units, e.g. "2d 3h", "12m 30s".
+func formatDuration(d time.Duration) string {
+	units := []struct {
+		suffix string
+		size   time.Duration
+	}{
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	}
+
+	for i, u := range units {
+		if d < u.size {
+			continue
+		}
+		out := fmt.Sprintf("%d%s", d/u.size, u.suffix)
+		if i+1 < len(units) {
+			next := units[i+1]
+			if n := (d % u.size) / next.size; n > 0 {
+				out += fmt.Sprintf(" %d%s", n, next.suffix)
+			}
+		}
+		return out
+	}
+	return "0s"
+}
+
 func runStateSet(cmd *cobra.Command, args []string) error {
 	entityID := args[0]
 	newState := args[1]
@@ -131,7 +362,26 @@ func runStateSet(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
+
+	if !stateNoValidate {
+		if err := checkEntitiesExist(client, []string{entityID}); err != nil {
+			return err
+		}
+	}
+
+	if !stateForce {
+		printInfo("Checking entity registry for %s...", entityID)
+		wsClient, err := newWSClient(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w (use --force to skip the device check)", err)
+		}
+		err = checkNotDeviceBacked(wsClient, entityID)
+		wsClient.Close()
+		if err != nil {
+			return err
+		}
+	}
 
 	printInfo("Setting state for %s to %s...", entityID, newState)
 	state, err := client.SetState(entityID, newState, attrs)
@@ -140,21 +390,154 @@ func runStateSet(cmd *cobra.Command, args []string) error {
 	}
 
 	if jsonOutput {
-		return outputJSON(state)
+		return outputJSON(cmd.OutOrStdout(), state)
+	}
+
+	printSuccess("State set successfully")
+	fmt.Fprintf(cmd.OutOrStdout(), "Entity:        %s\n", state.EntityID)
+	fmt.Fprintf(cmd.OutOrStdout(), "State:         %s\n", state.State)
+
+	return nil
+}
+
+func runStateDelete(cmd *cobra.Command, args []string) error {
+	entityID := args[0]
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("State set successfully\n")
-	fmt.Printf("Entity:        %s\n", state.EntityID)
-	fmt.Printf("State:         %s\n", state.State)
+	client := newRESTClient(cfg)
 
+	printInfo("Deleting state for %s...", entityID)
+	if err := client.DeleteState(entityID); err != nil {
+		if api.IsNotFound(err) {
+			return fmt.Errorf("entity %s has no state: %w", entityID, err)
+		}
+		return fmt.Errorf("failed to delete state: %w", err)
+	}
+
+	printSuccess("State deleted: %s", entityID)
 	return nil
 }
 
+func runStateSnapshot(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	printInfo("Fetching states...")
+	states, err := client.GetStates()
+	if err != nil {
+		return fmt.Errorf("failed to get states: %w", err)
+	}
+
+	states = filterStatesByDomain(states, stateSnapshotDomain)
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].EntityID < states[j].EntityID
+	})
+
+	if stateSnapshotOutput == "" {
+		return outputJSON(cmd.OutOrStdout(), states)
+	}
+
+	file, err := os.Create(stateSnapshotOutput)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer file.Close()
+
+	if err := outputJSON(file, states); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	printSuccess("Saved %d states to %s", len(states), stateSnapshotOutput)
+	return nil
+}
+
+// filterStatesByDomain keeps the states whose entity ID is in one of the
+// given domains. No domains means no filtering.
+func filterStatesByDomain(states []api.State, domains []string) []api.State {
+	if len(domains) == 0 {
+		return states
+	}
+
+	var filtered []api.State
+	for _, state := range states {
+		for _, domain := range domains {
+			if strings.HasPrefix(state.EntityID, strings.TrimSuffix(domain, ".")+".") {
+				filtered = append(filtered, state)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// checkEntitiesExist makes sure every entity ID has a state, so a typo is
+// reported instead of silently doing nothing. Unknown IDs get the closest
+// known entity ID as a suggestion.
+func checkEntitiesExist(client *api.Client, entityIDs []string) error {
+	printInfo("Checking that %s exists...", strings.Join(entityIDs, ", "))
+	states, err := client.GetStates()
+	if err != nil {
+		return fmt.Errorf("failed to check entities: %w (use --no-validate to skip the check)", err)
+	}
+
+	known := make([]string, 0, len(states))
+	exists := make(map[string]bool, len(states))
+	for _, s := range states {
+		known = append(known, s.EntityID)
+		exists[s.EntityID] = true
+	}
+
+	for _, id := range entityIDs {
+		if exists[id] {
+			continue
+		}
+		if suggestion, ok := closestEntityID(id, known); ok {
+			return fmt.Errorf("entity not found: %s, did you mean %s? (use --no-validate to skip the check)", id, suggestion)
+		}
+		return fmt.Errorf("entity not found: %s (use --no-validate to skip the check)", id)
+	}
+	return nil
+}
+
+// checkNotDeviceBacked refuses to let state set touch an entity that belongs
+// to a device. Entities missing from the registry (e.g. ones created by
+// state set itself) and registry entries without a device are allowed.
+func checkNotDeviceBacked(client *websocket.Client, entityID string) error {
+	entity, err := client.GetEntity(entityID)
+	if err != nil {
+		if websocket.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to check entity registry: %w (use --force to skip the device check)", err)
+	}
+
+	if entity.DeviceID == nil || *entity.DeviceID == "" {
+		return nil
+	}
+
+	device := lookupDeviceName(client, *entity.DeviceID)
+	if device == "" {
+		device = *entity.DeviceID
+	}
+
+	fmt.Fprintf(os.Stderr, "Warning: %s belongs to device %q (%s integration).\n", entityID, device, entity.Platform)
+	fmt.Fprintln(os.Stderr, "Setting its state does not control the device and is overwritten on the integration's next update.")
+	return fmt.Errorf("refusing to set the state of a device entity: use 'hass-cli call' to control it, or pass --force")
+}
+
 // formatTime formats an ISO timestamp for display.
 func formatTime(timestamp string) string {
 	t, err := time.Parse(time.RFC3339, timestamp)
 	if err != nil {
 		return timestamp
 	}
-	return t.Local().Format("2006-01-02 15:04:05")
+	return displayTime(t).Format("2006-01-02 15:04:05")
 }
@@ -1,7 +1,13 @@
 package cli
 
 import (
+	"fmt"
 	"testing"
+	"time"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/dorinclisu/hass-cli/internal/testutil"
+	"github.com/dorinclisu/hass-cli/internal/websocket"
 )
 
 func TestFormatTime(t *testing.T) {
@@ -53,3 +59,159 @@ func TestFormatTime(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatTime_DisplayLocation(t *testing.T) {
+	old := displayLocation
+	defer func() { displayLocation = old }()
+
+	displayLocation = time.UTC
+	if got := formatTime("2024-01-15T10:30:00+02:00"); got != "2024-01-15 08:30:00" {
+		t.Errorf("formatTime() in UTC = %q, want %q", got, "2024-01-15 08:30:00")
+	}
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	displayLocation = tokyo
+	if got := formatEventTime("2024-01-15T10:30:00Z"); got != "19:30:00" {
+		t.Errorf("formatEventTime() in Asia/Tokyo = %q, want %q", got, "19:30:00")
+	}
+}
+
+func TestResolveDisplayLocation(t *testing.T) {
+	oldTZ, oldUTC, oldLoc := timezone, useUTC, displayLocation
+	defer func() { timezone, useUTC, displayLocation = oldTZ, oldUTC, oldLoc }()
+
+	tests := []struct {
+		name     string
+		timezone string
+		utc      bool
+		want     string
+		wantErr  bool
+	}{
+		{name: "default is local", want: time.Local.String()},
+		{name: "utc flag", utc: true, want: "UTC"},
+		{name: "timezone name", timezone: "UTC", want: "UTC"},
+		{name: "utc with matching timezone", timezone: "utc", utc: true, want: "UTC"},
+		{name: "utc with other timezone", timezone: "Europe/Paris", utc: true, wantErr: true},
+		{name: "unknown timezone", timezone: "Mars/Olympus", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timezone, useUTC = tt.timezone, tt.utc
+			err := resolveDisplayLocation()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("resolveDisplayLocation() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveDisplayLocation() error = %v", err)
+			}
+			if displayLocation.String() != tt.want {
+				t.Errorf("displayLocation = %q, want %q", displayLocation, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckNotDeviceBacked(t *testing.T) {
+	mock := testutil.NewWSMock(t, testToken)
+	mock.Handle("config/entity_registry/get", func(msg map[string]interface{}) (interface{}, error) {
+		switch msg["entity_id"] {
+		case "light.kitchen":
+			return map[string]interface{}{"entity_id": "light.kitchen", "device_id": "dev1", "platform": "hue"}, nil
+		case "sensor.template":
+			return map[string]interface{}{"entity_id": "sensor.template", "device_id": nil, "platform": "template"}, nil
+		case "sensor.custom":
+			return nil, &testutil.WSError{Code: "not_found", Message: "Entity not found"}
+		}
+		return nil, fmt.Errorf("registry unavailable")
+	})
+	mock.Handle("config/device_registry/list", func(msg map[string]interface{}) (interface{}, error) {
+		return []map[string]interface{}{{"id": "dev1", "name": "Kitchen Lamp"}}, nil
+	})
+
+	client, err := websocket.NewClient(mock.URL(), testToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	tests := []struct {
+		entityID string
+		wantErr  bool
+	}{
+		{"light.kitchen", true},
+		{"sensor.template", false},
+		{"sensor.custom", false},
+		{"sensor.broken", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entityID, func(t *testing.T) {
+			err := checkNotDeviceBacked(client, tt.entityID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkNotDeviceBacked(%q) error = %v, wantErr %v", tt.entityID, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFilterStatesByDomain(t *testing.T) {
+	states := []api.State{
+		{EntityID: "sensor.temp"},
+		{EntityID: "light.kitchen"},
+		{EntityID: "switch.fan"},
+		{EntityID: "sensor_x.other"},
+	}
+
+	tests := []struct {
+		name    string
+		domains []string
+		want    []string
+	}{
+		{"no filter", nil, []string{"sensor.temp", "light.kitchen", "switch.fan", "sensor_x.other"}},
+		{"one domain", []string{"sensor"}, []string{"sensor.temp"}},
+		{"several domains", []string{"light", "switch."}, []string{"light.kitchen", "switch.fan"}},
+		{"no match", []string{"climate"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterStatesByDomain(states, tt.domains)
+			if len(got) != len(tt.want) {
+				t.Fatalf("filterStatesByDomain(%q) returned %d states, want %d", tt.domains, len(got), len(tt.want))
+			}
+			for i, s := range got {
+				if s.EntityID != tt.want[i] {
+					t.Errorf("filterStatesByDomain(%q)[%d] = %q, want %q", tt.domains, i, s.EntityID, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{45 * time.Second, "45s"},
+		{12*time.Minute + 30*time.Second, "12m 30s"},
+		{10 * time.Minute, "10m"},
+		{time.Hour + 5*time.Minute + 10*time.Second, "1h 5m"},
+		{2*24*time.Hour + 3*time.Hour, "2d 3h"},
+		{-time.Second, "0s"},
+	}
+
+	for _, tt := range tests {
+		if got := formatDuration(tt.d); got != tt.want {
+			t.Errorf("formatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -2,36 +2,74 @@ package cli
 
 import (
 	"fmt"
-	"time"
+	"sort"
+	"strings"
 
 	"github.com/dorinclisu/hass-cli/internal/api"
 	"github.com/spf13/cobra"
 )
 
 var statusCmd = &cobra.Command{
-	Use:   "status",
+	Use:   "status [filter]",
 	Short: "Check Home Assistant API connectivity",
 	Long: `Check the connection to Home Assistant and display system information.
 
 Shows the Home Assistant version, location name, time zone, and other configuration details.
 
+--full also fetches every state and summarizes the entities: the total, how
+many are unavailable, and a count per domain. This takes a second request,
+so it is not done by default.
+
+--components lists the loaded integrations and platforms (e.g. "hue" or
+"sensor.hue") in name order. A filter argument keeps only those containing
+it, which is a quick way to check that an integration actually loaded.
+
 Examples:
   hass-cli status              # Check connectivity and show system info
+  hass-cli status --full       # Include an entity summary
+  hass-cli status --components mqtt
   hass-cli status --json       # Output as JSON`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runStatus,
 }
 
+var (
+	statusFull       bool
+	statusComponents bool
+)
+
 func init() {
 	rootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().BoolVar(&statusFull, "full", false, "Also summarize entities by domain and availability")
+	statusCmd.Flags().BoolVar(&statusComponents, "components", false, "List the loaded components, optionally only those containing [filter]")
+}
+
+// EntityCounts is the entity overview shown by 'status --full'.
+type EntityCounts struct {
+	Total       int            `json:"total"`
+	Unavailable int            `json:"unavailable"`
+	Domains     map[string]int `json:"domains"`
+}
+
+// StatusReport is the output of 'status --full': the configuration with the
+// entity summary added.
+type StatusReport struct {
+	*api.Config
+	Entities *EntityCounts `json:"entities,omitempty"`
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 && !statusComponents {
+		return fmt.Errorf("a filter can only be given with --components")
+	}
+
 	cfg, err := loadConfig()
 	if err != nil {
 		return err
 	}
 
-	client := api.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client := newRESTClient(cfg)
 
 	printInfo("Checking connection to %s...", cfg.Server.URL)
 
@@ -40,24 +78,110 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to connect: %w", err)
 	}
 
+	loaded := len(config.Components)
+	if statusComponents {
+		filter := ""
+		if len(args) > 0 {
+			filter = args[0]
+		}
+		config.Components = filterComponents(config.Components, filter)
+	}
+
+	var summary *EntityCounts
+	if statusFull {
+		printInfo("Fetching states...")
+		states, err := client.GetStates()
+		if err != nil {
+			return fmt.Errorf("failed to get states: %w", err)
+		}
+		s := summarizeEntities(states)
+		summary = &s
+	}
+
 	if jsonOutput {
-		return outputJSON(config)
+		if summary != nil {
+			return outputJSON(cmd.OutOrStdout(), StatusReport{Config: config, Entities: summary})
+		}
+		return outputJSON(cmd.OutOrStdout(), config)
 	}
 
-	fmt.Printf("Connected to Home Assistant\n\n")
-	fmt.Printf("Version:       %s\n", config.Version)
-	fmt.Printf("Location:      %s\n", config.LocationName)
-	fmt.Printf("Time Zone:     %s\n", config.TimeZone)
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Connected to Home Assistant\n\n")
+	fmt.Fprintf(out, "Version:       %s\n", config.Version)
+	fmt.Fprintf(out, "Location:      %s\n", config.LocationName)
+	fmt.Fprintf(out, "Time Zone:     %s\n", config.TimeZone)
 	if config.State != "" {
-		fmt.Printf("State:         %s\n", config.State)
+		fmt.Fprintf(out, "State:         %s\n", config.State)
 	}
 	if config.Country != "" {
-		fmt.Printf("Country:       %s\n", config.Country)
+		fmt.Fprintf(out, "Country:       %s\n", config.Country)
 	}
 	if config.Language != "" {
-		fmt.Printf("Language:      %s\n", config.Language)
+		fmt.Fprintf(out, "Language:      %s\n", config.Language)
+	}
+	fmt.Fprintf(out, "Components:    %d loaded\n", loaded)
+
+	if summary != nil {
+		fmt.Fprintf(out, "Entities:      %d (%d unavailable)\n", summary.Total, summary.Unavailable)
+		fmt.Fprintln(out, "\nDomains:")
+		for _, domain := range domainsByCount(summary.Domains) {
+			fmt.Fprintf(out, "  %-24s %d\n", domain, summary.Domains[domain])
+		}
+	}
+
+	if statusComponents {
+		if len(args) > 0 {
+			fmt.Fprintf(out, "\nComponents matching %q (%d):\n", args[0], len(config.Components))
+		} else {
+			fmt.Fprintln(out, "\nComponents:")
+		}
+		for _, component := range config.Components {
+			fmt.Fprintf(out, "  %s\n", component)
+		}
 	}
-	fmt.Printf("Components:    %d loaded\n", len(config.Components))
 
 	return nil
 }
+
+// filterComponents returns the components containing filter
+// (case-insensitive), sorted by name. An empty filter keeps all.
+func filterComponents(components []string, filter string) []string {
+	filter = strings.ToLower(filter)
+	result := []string{}
+	for _, c := range components {
+		if strings.Contains(strings.ToLower(c), filter) {
+			result = append(result, c)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// summarizeEntities counts states in total, per domain, and in the
+// unavailable state.
+func summarizeEntities(states []api.State) EntityCounts {
+	summary := EntityCounts{Total: len(states), Domains: make(map[string]int)}
+	for _, state := range states {
+		domain, _, _ := strings.Cut(state.EntityID, ".")
+		summary.Domains[domain]++
+		if state.State == "unavailable" {
+			summary.Unavailable++
+		}
+	}
+	return summary
+}
+
+// domainsByCount returns the domains ordered by descending count, then name.
+func domainsByCount(counts map[string]int) []string {
+	domains := make([]string, 0, len(counts))
+	for domain := range counts {
+		domains = append(domains, domain)
+	}
+	sort.Slice(domains, func(i, j int) bool {
+		if counts[domains[i]] != counts[domains[j]] {
+			return counts[domains[i]] > counts[domains[j]]
+		}
+		return domains[i] < domains[j]
+	})
+	return domains
+}
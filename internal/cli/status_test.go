@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+)
+
+func TestSummarizeEntities(t *testing.T) {
+	states := []api.State{
+		{EntityID: "sensor.a", State: "21.5"},
+		{EntityID: "sensor.b", State: "unavailable"},
+		{EntityID: "light.kitchen", State: "on"},
+		{EntityID: "switch.fan", State: "unavailable"},
+		{EntityID: "light.hall", State: "unknown"},
+		{EntityID: "sensor.c", State: "3"},
+	}
+
+	got := summarizeEntities(states)
+	if got.Total != 6 || got.Unavailable != 2 {
+		t.Errorf("summarizeEntities() total = %d, unavailable = %d; want 6, 2", got.Total, got.Unavailable)
+	}
+	if got.Domains["sensor"] != 3 || got.Domains["light"] != 2 || got.Domains["switch"] != 1 {
+		t.Errorf("summarizeEntities() domains = %v", got.Domains)
+	}
+
+	if order := domainsByCount(got.Domains); !slices.Equal(order, []string{"sensor", "light", "switch"}) {
+		t.Errorf("domainsByCount() = %v", order)
+	}
+}
+
+func TestStatusReportJSON(t *testing.T) {
+	report := StatusReport{
+		Config:   &api.Config{Version: "2024.1.0"},
+		Entities: &EntityCounts{Total: 1, Domains: map[string]int{"light": 1}},
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if decoded["version"] != "2024.1.0" {
+		t.Errorf("version = %v, want config fields at the top level", decoded["version"])
+	}
+	entities, ok := decoded["entities"].(map[string]interface{})
+	if !ok || entities["total"] != float64(1) {
+		t.Errorf("entities = %v, want the summary", decoded["entities"])
+	}
+}
+
+func TestFilterComponents(t *testing.T) {
+	components := []string{"sensor.mqtt", "hue", "mqtt", "sensor", "light.hue"}
+
+	if got := filterComponents(components, ""); !slices.Equal(got, []string{"hue", "light.hue", "mqtt", "sensor", "sensor.mqtt"}) {
+		t.Errorf("filterComponents(\"\") = %v", got)
+	}
+	if got := filterComponents(components, "MQTT"); !slices.Equal(got, []string{"mqtt", "sensor.mqtt"}) {
+		t.Errorf("filterComponents(MQTT) = %v", got)
+	}
+	if got := filterComponents(components, "zwave"); got == nil || len(got) != 0 {
+		t.Errorf("filterComponents(zwave) = %#v, want an empty list", got)
+	}
+}
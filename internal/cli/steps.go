@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var stepAt int
+
+var automationsAddActionCmd = &cobra.Command{
+	Use:   "add-action <automation_id> <json>",
+	Short: "Append an action to an automation",
+	Long: `Add a single action to an automation without re-sending the whole list.
+
+The action is appended to the end unless --at gives the position (0-based)
+to insert it at.
+
+Examples:
+  hass-cli automations add-action 1761025981191 '{"action":"light.turn_off","target":{"entity_id":"light.kitchen"}}'
+  hass-cli automations add-action 1761025981191 '{"delay":"00:00:05"}' --at 0`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAutomationsAddAction,
+}
+
+var automationsAddTriggerCmd = &cobra.Command{
+	Use:   "add-trigger <automation_id> <json>",
+	Short: "Append a trigger to an automation",
+	Long: `Add a single trigger to an automation without re-sending the whole list.
+
+The trigger is appended to the end unless --at gives the position (0-based)
+to insert it at.
+
+Examples:
+  hass-cli automations add-trigger 1761025981191 '{"trigger":"time","at":"07:00:00"}'
+  hass-cli automations add-trigger 1761025981191 '{"trigger":"state","entity_id":"binary_sensor.door","to":"on"}'`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAutomationsAddTrigger,
+}
+
+var scriptsAddStepCmd = &cobra.Command{
+	Use:   "add-step <script_id> <json>",
+	Short: "Append a step to a script's sequence",
+	Long: `Add a single step to a script's sequence without re-sending the whole list.
+
+The step is appended to the end unless --at gives the position (0-based)
+to insert it at.
+
+Examples:
+  hass-cli scripts add-step hello_world '{"action":"light.turn_on","target":{"entity_id":"light.kitchen"}}'
+  hass-cli scripts add-step hello_world '{"delay":"00:00:05"}' --at 1`,
+	Args: cobra.ExactArgs(2),
+	RunE: runScriptsAddStep,
+}
+
+func init() {
+	automationsCmd.AddCommand(automationsAddActionCmd)
+	automationsCmd.AddCommand(automationsAddTriggerCmd)
+	scriptsCmd.AddCommand(scriptsAddStepCmd)
+
+	for _, cmd := range []*cobra.Command{automationsAddActionCmd, automationsAddTriggerCmd, scriptsAddStepCmd} {
+		cmd.Flags().IntVar(&stepAt, "at", -1, "Position to insert at (0-based, default: append)")
+	}
+}
+
+// parseStep parses raw as a single JSON object. kind names the value in
+// error messages (e.g. "action").
+func parseStep(raw, kind string) (map[string]interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		return nil, fmt.Errorf("invalid %s JSON: %w", kind, err)
+	}
+
+	step, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be a single JSON object", kind)
+	}
+
+	return step, nil
+}
+
+// insertStep inserts step into list at index at, or appends it when at is
+// negative.
+func insertStep(list []map[string]interface{}, step map[string]interface{}, at int) ([]map[string]interface{}, error) {
+	if at < 0 {
+		return append(list, step), nil
+	}
+	if at > len(list) {
+		return nil, fmt.Errorf("--at %d is out of range (0-%d)", at, len(list))
+	}
+
+	result := make([]map[string]interface{}, 0, len(list)+1)
+	result = append(result, list[:at]...)
+	result = append(result, step)
+	result = append(result, list[at:]...)
+	return result, nil
+}
+
+// stepPosition describes where a step was placed, for the success message.
+func stepPosition(at, count int) string {
+	if at < 0 {
+		at = count - 1
+	}
+	return fmt.Sprintf("%d of %d", at+1, count)
+}
+
+func runAutomationsAddAction(cmd *cobra.Command, args []string) error {
+	return addAutomationStep(args, "action", func(config *api.AutomationConfig) *[]map[string]interface{} {
+		return &config.Actions
+	})
+}
+
+func runAutomationsAddTrigger(cmd *cobra.Command, args []string) error {
+	return addAutomationStep(args, "trigger", func(config *api.AutomationConfig) *[]map[string]interface{} {
+		return &config.Triggers
+	})
+}
+
+// addAutomationStep inserts the object in args[1] into the list of the
+// automation in args[0] selected by field.
+func addAutomationStep(args []string, kind string, field func(*api.AutomationConfig) *[]map[string]interface{}) error {
+	automationID := normalizeAutomationID(args[0])
+
+	step, err := parseStep(args[1], kind)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	printInfo("Fetching current automation configuration...")
+	config, err := client.GetAutomationConfig(automationID)
+	if err != nil {
+		return fmt.Errorf("failed to get automation: %w", err)
+	}
+
+	list := field(config)
+	updated, err := insertStep(*list, step, stepAt)
+	if err != nil {
+		return err
+	}
+	*list = updated
+
+	printInfo("Updating automation...")
+	if err := client.UpdateAutomation(automationID, config); err != nil {
+		return fmt.Errorf("failed to update automation: %w", err)
+	}
+
+	printSuccess("Added %s %s to automation: %s", kind, stepPosition(stepAt, len(updated)), config.Alias)
+
+	return nil
+}
+
+func runScriptsAddStep(cmd *cobra.Command, args []string) error {
+	scriptID := normalizeScriptID(args[0])
+
+	step, err := parseStep(args[1], "step")
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	printInfo("Fetching current script configuration...")
+	config, err := client.GetScriptConfig(scriptID)
+	if err != nil {
+		return fmt.Errorf("failed to get script: %w", err)
+	}
+
+	config.Sequence, err = insertStep(config.Sequence, step, stepAt)
+	if err != nil {
+		return err
+	}
+
+	printInfo("Updating script...")
+	if err := client.UpdateScript(scriptID, config); err != nil {
+		return fmt.Errorf("failed to update script: %w", err)
+	}
+
+	printSuccess("Added step %s to script: %s", stepPosition(stepAt, len(config.Sequence)), config.Alias)
+
+	return nil
+}
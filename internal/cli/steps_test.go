@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"testing"
+)
+
+func TestParseStep(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"object", `{"action":"light.turn_on"}`, false},
+		{"array", `[{"action":"light.turn_on"}]`, true},
+		{"string", `"light.turn_on"`, true},
+		{"null", `null`, true},
+		{"invalid", `{action}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, err := parseStep(tt.input, "action")
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseStep(%q) expected error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseStep(%q) error = %v", tt.input, err)
+			}
+			if step["action"] != "light.turn_on" {
+				t.Errorf("action = %v, want %q", step["action"], "light.turn_on")
+			}
+		})
+	}
+}
+
+func TestInsertStep(t *testing.T) {
+	step := func(id string) map[string]interface{} { return map[string]interface{}{"id": id} }
+	ids := func(list []map[string]interface{}) string {
+		s := ""
+		for _, item := range list {
+			s += item["id"].(string)
+		}
+		return s
+	}
+
+	tests := []struct {
+		name    string
+		at      int
+		want    string
+		wantErr bool
+	}{
+		{"append", -1, "abX", false},
+		{"start", 0, "Xab", false},
+		{"middle", 1, "aXb", false},
+		{"end", 2, "abX", false},
+		{"out of range", 3, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := []map[string]interface{}{step("a"), step("b")}
+			got, err := insertStep(list, step("X"), tt.at)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("insertStep(at=%d) expected error", tt.at)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("insertStep(at=%d) error = %v", tt.at, err)
+			}
+			if ids(got) != tt.want {
+				t.Errorf("insertStep(at=%d) = %q, want %q", tt.at, ids(got), tt.want)
+			}
+		})
+	}
+}
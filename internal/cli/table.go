@@ -0,0 +1,316 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// tableFields is the --fields selection of list commands that support it.
+var tableFields []string
+
+// listLimit and listOffset are the --limit and --offset of list commands
+// that support paging.
+var (
+	listLimit  int
+	listOffset int
+)
+
+// tableColumn describes one column of a list command's output.
+type tableColumn struct {
+	Header string
+	// Width is the maximum width of the column in table output; longer
+	// values are truncated with "...". Zero means no limit. Machine-readable
+	// formats always get the full value.
+	Width int
+}
+
+// tableData is the tabular form of a list command's output. It is rendered
+// as an aligned table for humans or as TSV or CSV for scripts.
+type tableData struct {
+	Columns []tableColumn
+	Rows    [][]string
+	// Noun is used in the "Total: N <noun>" footer
+	Noun string
+	// Empty is printed instead of the table when there are no rows
+	Empty string
+	// Hint is printed after the total in table output
+	Hint string
+}
+
+// addRow appends a row. Values must be given in column order.
+func (t *tableData) addRow(values ...string) {
+	t.Rows = append(t.Rows, values)
+}
+
+// outputData writes the result of a list command in the selected output
+// format: data as JSON, or table as a table, TSV or CSV.
+func outputData(out io.Writer, data interface{}, table *tableData) error {
+	if len(tableFields) > 0 && outputFormat != "json" {
+		selected, err := fieldsTable(data, tableFields, table)
+		if err != nil {
+			return err
+		}
+		table = selected
+	}
+	if redactOutput {
+		table = redactTable(table)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(out, data)
+	case "tsv":
+		return writeTSV(out, table)
+	case "csv":
+		return writeCSV(out, table)
+	default:
+		return writeTable(out, table)
+	}
+}
+
+// writeTable renders t as an aligned table with a header, separator and total.
+func writeTable(out io.Writer, t *tableData) error {
+	if len(t.Rows) == 0 {
+		fmt.Fprintln(out, t.Empty)
+		return nil
+	}
+
+	headers := make([]string, len(t.Columns))
+	separators := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		headers[i] = col.Header
+		separators[i] = strings.Repeat("-", len(col.Header))
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	fmt.Fprintln(w, strings.Join(separators, "\t"))
+
+	cells := make([]string, len(t.Columns))
+	for _, row := range t.Rows {
+		for i, col := range t.Columns {
+			cells[i] = truncate(row[i], col.Width)
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+
+	w.Flush()
+	printFooter(out, "Total: %d %s", len(t.Rows), t.Noun)
+	if t.Hint != "" {
+		printFooter(out, "%s", t.Hint)
+	}
+
+	return nil
+}
+
+// writeTSV renders t as tab-separated values: a header line followed by one
+// line per row, with no separator, total or hint. Tabs and newlines inside
+// values are replaced with spaces so every row stays on one line.
+func writeTSV(out io.Writer, t *tableData) error {
+	clean := strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+
+	headers := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		headers[i] = col.Header
+	}
+	if _, err := fmt.Fprintln(out, strings.Join(headers, "\t")); err != nil {
+		return err
+	}
+
+	cells := make([]string, len(t.Columns))
+	for _, row := range t.Rows {
+		for i := range t.Columns {
+			cells[i] = clean.Replace(row[i])
+		}
+		if _, err := fmt.Fprintln(out, strings.Join(cells, "\t")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeCSV renders t as comma-separated values: a header line followed by
+// one line per row, quoted as needed for spreadsheets (RFC 4180).
+func writeCSV(out io.Writer, t *tableData) error {
+	w := csv.NewWriter(out)
+
+	headers := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		headers[i] = col.Header
+	}
+	if err := w.Write(headers); err != nil {
+		return err
+	}
+	for _, row := range t.Rows {
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// truncate shortens s to at most width bytes, ending in "...". A width of
+// zero leaves s unchanged.
+func truncate(s string, width int) string {
+	if width <= 0 || len(s) <= width {
+		return s
+	}
+	return s[:width-3] + "..."
+}
+
+// addFieldsFlag registers --fields on a list command whose rows are structs
+// of the same type as row. The requested names are checked before the
+// command runs, so a typo fails fast instead of after fetching everything.
+func addFieldsFlag(cmd *cobra.Command, row interface{}) {
+	cmd.Flags().StringSliceVar(&tableFields, "fields", nil, "Table columns to show, in order (comma-separated field names as in --json output)")
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		_, err := selectFields(reflect.TypeOf(row), tableFields)
+		return err
+	}
+}
+
+// addPageFlags adds --limit and --offset to a list command, applied with
+// applyPage after sorting.
+func addPageFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&listLimit, "limit", 0, "Show at most this many rows (0 = all)")
+	cmd.Flags().IntVar(&listOffset, "offset", 0, "Skip this many rows first")
+}
+
+// applyPage returns the page of items selected by --limit and --offset,
+// reporting on stderr which rows are shown when not all of them are. JSON
+// output contains only the page as well.
+func applyPage[T any](items []T) ([]T, error) {
+	if listLimit < 0 || listOffset < 0 {
+		return nil, fmt.Errorf("--limit and --offset must not be negative")
+	}
+
+	page := paginate(items, listLimit, listOffset)
+	if len(page) < len(items) && !jsonOutput {
+		if len(page) == 0 {
+			fmt.Fprintf(os.Stderr, "Showing none of %d (--offset is past the end)\n", len(items))
+		} else {
+			fmt.Fprintf(os.Stderr, "Showing %d–%d of %d\n", listOffset+1, listOffset+len(page), len(items))
+		}
+	}
+	return page, nil
+}
+
+// paginate returns at most limit items starting at offset. A limit of zero
+// returns everything from offset on.
+func paginate[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return items[:0]
+	}
+	items = items[offset:]
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+// structFields returns the field names of struct type t as they appear in
+// JSON output, with the index of each field.
+func structFields(t reflect.Type) ([]string, map[string]int) {
+	var names []string
+	index := make(map[string]int)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+		index[name] = i
+	}
+	return names, index
+}
+
+// selectFields resolves the requested field names of struct type t to field
+// indexes, in the requested order.
+func selectFields(t reflect.Type, fields []string) ([]int, error) {
+	names, index := structFields(t)
+
+	var selected []int
+	for _, field := range fields {
+		i, ok := index[strings.TrimSpace(field)]
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q (available: %s)", field, strings.Join(names, ", "))
+		}
+		selected = append(selected, i)
+	}
+	return selected, nil
+}
+
+// fieldsTable builds a table of the requested fields from data, a slice of
+// structs or struct pointers. The noun, empty text and hint are taken from
+// base, the command's default table.
+func fieldsTable(data interface{}, fields []string, base *tableData) (*tableData, error) {
+	rows := reflect.ValueOf(data)
+	if rows.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("--fields is not supported for this output")
+	}
+	elemType := rows.Type().Elem()
+	if elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("--fields is not supported for this output")
+	}
+
+	selected, err := selectFields(elemType, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &tableData{Noun: base.Noun, Empty: base.Empty, Hint: base.Hint}
+	for _, field := range fields {
+		t.Columns = append(t.Columns, tableColumn{Header: strings.ToUpper(strings.TrimSpace(field))})
+	}
+
+	for i := 0; i < rows.Len(); i++ {
+		row := reflect.Indirect(rows.Index(i))
+		values := make([]string, len(selected))
+		for j, index := range selected {
+			values[j] = fieldString(row.Field(index))
+		}
+		t.addRow(values...)
+	}
+
+	return t, nil
+}
+
+// fieldString renders a struct field for a table cell: nil pointers, slices
+// and maps as blanks, strings as they are and anything else as JSON.
+func fieldString(v reflect.Value) string {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return ""
+		}
+	}
+	return attributeString(v.Interface())
+}
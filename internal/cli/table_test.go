@@ -0,0 +1,224 @@
+package cli
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func testTable() *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "ENTITY ID"}, {Header: "NAME", Width: 10}},
+		Noun:    "entities",
+		Empty:   "No entities found",
+	}
+	t.addRow("light.kitchen", "Kitchen ceiling light")
+	t.addRow("light.hall", "Hall\twith\ttabs")
+	return t
+}
+
+func TestWriteTable(t *testing.T) {
+	table := testTable()
+	table.Rows = table.Rows[:1]
+	table.Hint = "Use inspect for details"
+
+	var buf bytes.Buffer
+	if err := writeTable(&buf, table); err != nil {
+		t.Fatalf("writeTable() error = %v", err)
+	}
+
+	want := "ENTITY ID      NAME\n" +
+		"---------      ----\n" +
+		"light.kitchen  Kitchen...\n" +
+		"\nTotal: 1 entities\n" +
+		"\nUse inspect for details\n"
+	if got := buf.String(); got != want {
+		t.Errorf("writeTable() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteTable_Quiet(t *testing.T) {
+	quiet = true
+	defer func() { quiet = false }()
+
+	table := testTable()
+	table.Rows = table.Rows[:1]
+	table.Hint = "Use inspect for details"
+
+	var buf bytes.Buffer
+	if err := writeTable(&buf, table); err != nil {
+		t.Fatalf("writeTable() error = %v", err)
+	}
+
+	want := "ENTITY ID      NAME\n" +
+		"---------      ----\n" +
+		"light.kitchen  Kitchen...\n"
+	if got := buf.String(); got != want {
+		t.Errorf("writeTable() with --quiet = %q, want %q", got, want)
+	}
+}
+
+func TestWriteTSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeTSV(&buf, testTable()); err != nil {
+		t.Fatalf("writeTSV() error = %v", err)
+	}
+
+	want := "ENTITY ID\tNAME\n" +
+		"light.kitchen\tKitchen ceiling light\n" +
+		"light.hall\tHall with tabs\n"
+	if got := buf.String(); got != want {
+		t.Errorf("writeTSV() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteTSV_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeTSV(&buf, &tableData{Columns: []tableColumn{{Header: "ID"}}, Empty: "No items found"}); err != nil {
+		t.Fatalf("writeTSV() error = %v", err)
+	}
+	if got := buf.String(); got != "ID\n" {
+		t.Errorf("writeTSV() = %q, want %q", got, "ID\n")
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	table := testTable()
+	table.addRow("light.porch", `Porch, "front"`)
+
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, table); err != nil {
+		t.Fatalf("writeCSV() error = %v", err)
+	}
+
+	want := "ENTITY ID,NAME\n" +
+		"light.kitchen,Kitchen ceiling light\n" +
+		"light.hall,Hall\twith\ttabs\n" +
+		"light.porch,\"Porch, \"\"front\"\"\"\n"
+	if got := buf.String(); got != want {
+		t.Errorf("writeCSV() = %q, want %q", got, want)
+	}
+}
+
+func TestResolveOutputFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		json     bool
+		tsv      bool
+		want     string
+		wantJSON bool
+		wantErr  bool
+	}{
+		{name: "default", format: "table", want: "table"},
+		{name: "json flag", format: "table", json: true, want: "json", wantJSON: true},
+		{name: "tsv flag", format: "table", tsv: true, want: "tsv"},
+		{name: "format json", format: "json", want: "json", wantJSON: true},
+		{name: "format TSV", format: "TSV", want: "tsv"},
+		{name: "format csv", format: "csv", want: "csv"},
+		{name: "csv with tsv shorthand", format: "csv", tsv: true, wantErr: true},
+		{name: "matching shorthand", format: "tsv", tsv: true, want: "tsv"},
+		{name: "conflicting shorthand", format: "tsv", json: true, wantErr: true},
+		{name: "both shorthands", format: "table", json: true, tsv: true, wantErr: true},
+		{name: "unknown", format: "xml", wantErr: true},
+	}
+
+	defer func() {
+		outputFormat, jsonOutput, tsvOutput = "table", false, false
+	}()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputFormat, jsonOutput, tsvOutput = tt.format, tt.json, tt.tsv
+
+			err := resolveOutputFormat()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("resolveOutputFormat() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveOutputFormat() error = %v", err)
+			}
+			if outputFormat != tt.want {
+				t.Errorf("outputFormat = %q, want %q", outputFormat, tt.want)
+			}
+			if jsonOutput != tt.wantJSON {
+				t.Errorf("jsonOutput = %v, want %v", jsonOutput, tt.wantJSON)
+			}
+		})
+	}
+}
+
+func TestFieldsTable(t *testing.T) {
+	type row struct {
+		EntityID string            `json:"entity_id"`
+		Name     *string           `json:"name"`
+		Count    int               `json:"count,omitempty"`
+		Labels   []string          `json:"labels"`
+		Hidden   string            `json:"-"`
+		Extra    map[string]string `json:"extra,omitempty"`
+	}
+	rows := []row{
+		{EntityID: "light.kitchen", Name: strPtr("Kitchen"), Count: 2, Labels: []string{"a"}},
+		{EntityID: "light.hall"},
+	}
+	base := &tableData{Noun: "entities", Empty: "No entities found", Hint: "hint"}
+
+	got, err := fieldsTable(rows, []string{"count", "entity_id", "name", "labels"}, base)
+	if err != nil {
+		t.Fatalf("fieldsTable() error = %v", err)
+	}
+	if got.Noun != "entities" || got.Empty != "No entities found" || got.Hint != "hint" {
+		t.Errorf("fieldsTable() did not keep noun, empty text and hint: %+v", got)
+	}
+
+	var headers []string
+	for _, col := range got.Columns {
+		headers = append(headers, col.Header)
+	}
+	if strings.Join(headers, ",") != "COUNT,ENTITY_ID,NAME,LABELS" {
+		t.Errorf("headers = %v", headers)
+	}
+	if r := strings.Join(got.Rows[0], ","); r != `2,light.kitchen,Kitchen,["a"]` {
+		t.Errorf("row 0 = %s", r)
+	}
+	if r := strings.Join(got.Rows[1], ","); r != "0,light.hall,," {
+		t.Errorf("row 1 = %s", r)
+	}
+
+	_, err = fieldsTable(rows, []string{"entity_id", "Hidden"}, base)
+	if err == nil || !strings.Contains(err.Error(), `unknown field "Hidden"`) || !strings.Contains(err.Error(), "entity_id, name, count, labels, extra") {
+		t.Errorf("fieldsTable(unknown) error = %v", err)
+	}
+
+	if _, err := fieldsTable(map[string]string{}, []string{"entity_id"}, base); err == nil {
+		t.Error("fieldsTable(map) expected error")
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		limit, offset int
+		want          []int
+	}{
+		{0, 0, []int{1, 2, 3, 4, 5}},
+		{2, 0, []int{1, 2}},
+		{2, 3, []int{4, 5}},
+		{10, 1, []int{2, 3, 4, 5}},
+		{0, 4, []int{5}},
+		{3, 5, []int{}},
+		{3, 9, []int{}},
+	}
+
+	for _, tt := range tests {
+		got := paginate(items, tt.limit, tt.offset)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("paginate(limit=%d, offset=%d) = %v, want %v", tt.limit, tt.offset, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var templateFile string
+
+var templateCmd = &cobra.Command{
+	Use:   "template [template]",
+	Short: "Render a Jinja template",
+	Long: `Render a Jinja template on the Home Assistant server and print the result.
+
+The template is taken from the argument, from --file, or from stdin when
+neither is given. Template errors are reported as Home Assistant returns them.
+
+A --file ending in .yaml or .yml is read as a map of names to templates.
+Each template is rendered in turn and the results are listed by name; a
+failing template is reported without stopping the others, and the exit
+status is non-zero if any failed:
+
+  outdoor: "{{ states('sensor.outdoor_temperature') | float(0) | round(1) }}"
+  lights_on: "{{ states.light | selectattr('state', 'eq', 'on') | list | count }}"
+
+Examples:
+  hass-cli template "{{ states('sun.sun') }}"
+  hass-cli template --file notification.j2
+  hass-cli template --file sensors.yaml
+  echo "{{ now() }}" | hass-cli template
+  hass-cli template "{{ states.light | count }}" --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTemplate,
+}
+
+func init() {
+	templateCmd.Flags().StringVarP(&templateFile, "file", "f", "", "Read the template from a file")
+
+	rootCmd.AddCommand(templateCmd)
+}
+
+// readTemplate returns the template from the argument, --file or stdin.
+func readTemplate(args []string, file string, stdin io.Reader) (string, error) {
+	switch {
+	case len(args) > 0 && file != "":
+		return "", fmt.Errorf("give the template as an argument or with --file, not both")
+	case len(args) > 0:
+		return args[0], nil
+	case file != "":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read template file: %w", err)
+		}
+		return string(data), nil
+	default:
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read template from stdin: %w", err)
+		}
+		if strings.TrimSpace(string(data)) == "" {
+			return "", fmt.Errorf("no template given")
+		}
+		return string(data), nil
+	}
+}
+
+func runTemplate(cmd *cobra.Command, args []string) error {
+	if isTemplateSetFile(templateFile) {
+		if len(args) > 0 {
+			return fmt.Errorf("give the template as an argument or with --file, not both")
+		}
+		return runTemplateSet(cmd, templateFile)
+	}
+
+	template, err := readTemplate(args, templateFile, os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	printInfo("Rendering template...")
+	result, err := client.RenderTemplate(template)
+	if err != nil {
+		return templateError(err)
+	}
+
+	if jsonOutput {
+		return outputJSON(cmd.OutOrStdout(), map[string]string{"result": result})
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), result)
+	return nil
+}
+
+// templateError turns a rendering failure into the error to report: the
+// message Home Assistant gives for a template error, or the request error.
+func templateError(err error) error {
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == 400 {
+		return errors.New(apiErr.Message)
+	}
+	return fmt.Errorf("failed to render template: %w", err)
+}
+
+// TemplateResult is the outcome of rendering one template of a set.
+type TemplateResult struct {
+	Name   string `json:"name"`
+	Result string `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// namedTemplate is one entry of a template set file.
+type namedTemplate struct {
+	Name     string
+	Template string
+}
+
+// isTemplateSetFile reports whether --file names a YAML template set.
+func isTemplateSetFile(file string) bool {
+	ext := strings.ToLower(filepath.Ext(file))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+func runTemplateSet(cmd *cobra.Command, file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read template file: %w", err)
+	}
+
+	templates, err := parseTemplateSet(data)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	printInfo("Rendering %d templates...", len(templates))
+	results := renderTemplateSet(templates, client.RenderTemplate)
+
+	if err := outputData(cmd.OutOrStdout(), results, templateResultsTable(results)); err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		if r.Error != "" {
+			return &ExitError{Code: 1}
+		}
+	}
+	return nil
+}
+
+// parseTemplateSet reads a YAML map of names to templates, keeping the
+// order of the file.
+func parseTemplateSet(data []byte) ([]namedTemplate, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse template file: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("template file is empty")
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("template file must be a map of names to templates")
+	}
+
+	var templates []namedTemplate
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if value.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("line %d: template %q must be a string", value.Line, key.Value)
+		}
+		templates = append(templates, namedTemplate{Name: key.Value, Template: value.Value})
+	}
+	if len(templates) == 0 {
+		return nil, fmt.Errorf("template file is empty")
+	}
+
+	return templates, nil
+}
+
+// renderTemplateSet renders each template in order. Failures are recorded in
+// the result and do not stop the remaining templates.
+func renderTemplateSet(templates []namedTemplate, render templateRenderer) []TemplateResult {
+	results := make([]TemplateResult, 0, len(templates))
+	for _, t := range templates {
+		result := TemplateResult{Name: t.Name}
+		out, err := render(t.Template)
+		if err != nil {
+			result.Error = templateError(err).Error()
+		} else {
+			result.Result = out
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func templateResultsTable(results []TemplateResult) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "NAME"}, {Header: "RESULT", Width: 60}, {Header: "ERROR", Width: 60}},
+		Noun:    "templates",
+		Empty:   "No templates found",
+	}
+
+	for _, r := range results {
+		t.addRow(
+			r.Name,
+			strings.Join(strings.Fields(r.Result), " "),
+			r.Error,
+		)
+	}
+
+	return t
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+)
+
+func TestReadTemplate(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tpl.j2")
+	if err := os.WriteFile(file, []byte("{{ now() }}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		file    string
+		stdin   string
+		want    string
+		wantErr bool
+	}{
+		{name: "argument", args: []string{"{{ 1 + 1 }}"}, stdin: "ignored", want: "{{ 1 + 1 }}"},
+		{name: "file", file: file, want: "{{ now() }}"},
+		{name: "stdin", stdin: "{{ states('sun.sun') }}\n", want: "{{ states('sun.sun') }}\n"},
+		{name: "argument and file", args: []string{"x"}, file: file, wantErr: true},
+		{name: "missing file", file: filepath.Join(t.TempDir(), "missing.j2"), wantErr: true},
+		{name: "empty stdin", stdin: "  \n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readTemplate(tt.args, tt.file, strings.NewReader(tt.stdin))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("readTemplate() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readTemplate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("readTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTemplateSet(t *testing.T) {
+	data := []byte(`outdoor: "{{ states('sensor.outdoor') }}"
+lights_on: "{{ states.light | count }}"
+multi: |
+  {% if is_state('sun.sun', 'above_horizon') %}day{% else %}night{% endif %}
+`)
+
+	templates, err := parseTemplateSet(data)
+	if err != nil {
+		t.Fatalf("parseTemplateSet() error = %v", err)
+	}
+
+	wantNames := []string{"outdoor", "lights_on", "multi"}
+	if len(templates) != len(wantNames) {
+		t.Fatalf("len(templates) = %d, want %d", len(templates), len(wantNames))
+	}
+	for i, name := range wantNames {
+		if templates[i].Name != name {
+			t.Errorf("templates[%d].Name = %q, want %q", i, templates[i].Name, name)
+		}
+	}
+	if templates[1].Template != "{{ states.light | count }}" {
+		t.Errorf("templates[1].Template = %q", templates[1].Template)
+	}
+
+	for _, bad := range []string{"", "- a\n- b\n", "nested:\n  a: b\n", "{{ oops"} {
+		if _, err := parseTemplateSet([]byte(bad)); err == nil {
+			t.Errorf("parseTemplateSet(%q) expected error", bad)
+		}
+	}
+}
+
+func TestRenderTemplateSet(t *testing.T) {
+	render := func(tpl string) (string, error) {
+		if tpl == "bad" {
+			return "", &api.APIError{StatusCode: 400, Message: "TemplateSyntaxError: unexpected end of template"}
+		}
+		return "rendered " + tpl, nil
+	}
+
+	results := renderTemplateSet([]namedTemplate{
+		{Name: "first", Template: "a"},
+		{Name: "broken", Template: "bad"},
+		{Name: "last", Template: "b"},
+	}, render)
+
+	want := []TemplateResult{
+		{Name: "first", Result: "rendered a"},
+		{Name: "broken", Error: "TemplateSyntaxError: unexpected end of template"},
+		{Name: "last", Result: "rendered b"},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("len(results) = %d, want %d", len(results), len(want))
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("results[%d] = %+v, want %+v", i, results[i], want[i])
+		}
+	}
+}
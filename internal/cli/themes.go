@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+	"github.com/spf13/cobra"
+)
+
+var themeMode string
+
+var themesCmd = &cobra.Command{
+	Use:   "themes",
+	Short: "List and set frontend themes",
+	Long: `List the frontend themes and which ones are the default.
+
+The built-in theme is listed as "default". Setting it, or its alias
+"backend-selected", goes back to the built-in theme.
+
+Examples:
+  hass-cli themes                          # List themes
+  hass-cli themes set midnight             # Default theme for light mode
+  hass-cli themes set midnight --mode dark # Default theme for dark mode
+  hass-cli themes set default              # Back to the built-in theme`,
+	Args: cobra.NoArgs,
+	RunE: runThemes,
+}
+
+var themesSetCmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Set the default frontend theme",
+	Long: `Set the default frontend theme for light or dark mode.
+
+Browsers whose theme is set to "Backend-selected" follow this default.
+"default" (or "backend-selected") resets the mode to the built-in theme.
+
+Examples:
+  hass-cli themes set midnight
+  hass-cli themes set midnight --mode dark
+  hass-cli themes set default --mode dark`,
+	Args: cobra.ExactArgs(1),
+	RunE: runThemesSet,
+}
+
+func init() {
+	rootCmd.AddCommand(themesCmd)
+	themesCmd.AddCommand(themesSetCmd)
+
+	themesSetCmd.Flags().StringVar(&themeMode, "mode", "light", "Mode to set the theme for: light or dark")
+}
+
+// ThemeInfo is a frontend theme and whether it is a default.
+type ThemeInfo struct {
+	Name        string `json:"name"`
+	Default     bool   `json:"default"`
+	DefaultDark bool   `json:"default_dark"`
+}
+
+func runThemes(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	printInfo("Connecting to Home Assistant...")
+	client, err := newWSClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer client.Close()
+
+	printInfo("Fetching themes...")
+	themes, err := client.GetThemes()
+	if err != nil {
+		return fmt.Errorf("failed to get themes: %w", err)
+	}
+
+	list := themeInfos(themes)
+	return outputData(cmd.OutOrStdout(), list, themesTable(list))
+}
+
+// themeInfos lists the built-in theme followed by the configured themes
+// sorted by name.
+func themeInfos(themes *websocket.Themes) []ThemeInfo {
+	names := make([]string, 0, len(themes.Themes))
+	for name := range themes.Themes {
+		if name != "default" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	names = append([]string{"default"}, names...)
+
+	list := make([]ThemeInfo, len(names))
+	for i, name := range names {
+		list[i] = ThemeInfo{
+			Name: name,
+			// An unset default is the built-in theme
+			Default:     name == themes.DefaultTheme || (name == "default" && themes.DefaultTheme == ""),
+			DefaultDark: themes.DefaultDarkTheme != nil && name == *themes.DefaultDarkTheme,
+		}
+	}
+
+	return list
+}
+
+func themesTable(themes []ThemeInfo) *tableData {
+	t := &tableData{
+		Columns: []tableColumn{{Header: "THEME", Width: 40}, {Header: "DEFAULT"}},
+		Noun:    "themes",
+		Empty:   "No themes found",
+	}
+
+	for _, theme := range themes {
+		var modes []string
+		if theme.Default {
+			modes = append(modes, "light")
+		}
+		if theme.DefaultDark {
+			modes = append(modes, "dark")
+		}
+		def := strings.Join(modes, ", ")
+		if def == "" {
+			def = "-"
+		}
+		t.addRow(theme.Name, def)
+	}
+
+	return t
+}
+
+func runThemesSet(cmd *cobra.Command, args []string) error {
+	if themeMode != "light" && themeMode != "dark" {
+		return fmt.Errorf("--mode must be light or dark")
+	}
+
+	name := args[0]
+	builtIn := name == "default" || strings.EqualFold(name, "backend-selected")
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if !builtIn {
+		printInfo("Connecting to Home Assistant...")
+		wsClient, err := newWSClient(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		themes, err := wsClient.GetThemes()
+		wsClient.Close()
+		if err != nil {
+			return fmt.Errorf("failed to get themes: %w", err)
+		}
+		if _, ok := themes.Themes[name]; !ok {
+			return fmt.Errorf("theme not found: %s (run 'hass-cli themes' to list them)", name)
+		}
+	}
+
+	// The dark mode default is cleared with "none" rather than "default"
+	serviceName := name
+	if builtIn {
+		serviceName = "default"
+		if themeMode == "dark" {
+			serviceName = "none"
+		}
+	}
+
+	client := newRESTClient(cfg)
+	printInfo("Setting %s theme...", themeMode)
+	if _, err := client.CallService("frontend", "set_theme", map[string]interface{}{"name": serviceName, "mode": themeMode}); err != nil {
+		return fmt.Errorf("failed to set theme: %w", err)
+	}
+
+	if builtIn {
+		printSuccess("Default %s theme reset to the built-in theme", themeMode)
+	} else {
+		printSuccess("Default %s theme set to %s", themeMode, name)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+)
+
+func TestThemeInfos(t *testing.T) {
+	dark := "midnight"
+	themes := &websocket.Themes{
+		Themes: map[string]map[string]interface{}{
+			"solarized": {},
+			"midnight":  {},
+		},
+		DefaultTheme:     "default",
+		DefaultDarkTheme: &dark,
+	}
+
+	got := themeInfos(themes)
+	want := []ThemeInfo{
+		{Name: "default", Default: true},
+		{Name: "midnight", DefaultDark: true},
+		{Name: "solarized"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("themeInfos() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("themeInfos()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	themes = &websocket.Themes{Themes: map[string]map[string]interface{}{"solarized": {}}, DefaultTheme: "solarized"}
+	got = themeInfos(themes)
+	if got[0].Default || !got[1].Default {
+		t.Errorf("themeInfos() with custom default = %+v", got)
+	}
+}
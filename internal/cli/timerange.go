@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+)
+
+// timeFlagLayouts are the non-RFC3339 formats accepted by --start and --end,
+// interpreted in the display time zone.
+var timeFlagLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseTimeFlag parses a --start or --end value.
+func parseTimeFlag(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range timeFlagLayouts {
+		if t, err := time.ParseInLocation(layout, s, displayLocation); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use RFC3339 or YYYY-MM-DD [HH:MM[:SS]]", s)
+}
+
+// parseRelativeTime parses a time given either as a duration before now
+// (e.g. "2h", "1d") or as an absolute time accepted by parseTimeFlag.
+func parseRelativeTime(s string, now time.Time) (time.Time, error) {
+	if d, err := parseAge(s); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := parseTimeFlag(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: use a duration (e.g., 30m, 2h, 1d), RFC3339 or YYYY-MM-DD [HH:MM[:SS]]", s)
+	}
+	return t, nil
+}
+
+// timeRange resolves the --start, --end and --days flags of commands that
+// query a period of time. The period defaults to the 24 hours before end,
+// and end defaults to now.
+func timeRange(start, end string, days float64, now time.Time) (time.Time, time.Time, error) {
+	if start != "" && days != 0 {
+		return time.Time{}, time.Time{}, fmt.Errorf("--start cannot be combined with --days")
+	}
+	if days < 0 {
+		return time.Time{}, time.Time{}, fmt.Errorf("--days must be positive")
+	}
+
+	to := now
+	if end != "" {
+		t, err := parseTimeFlag(end)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --end: %w", err)
+		}
+		to = t
+	}
+
+	from := to.Add(-24 * time.Hour)
+	switch {
+	case start != "":
+		t, err := parseTimeFlag(start)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --start: %w", err)
+		}
+		from = t
+	case days > 0:
+		from = to.Add(-time.Duration(days * float64(24*time.Hour)))
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("start of the period must be before its end")
+	}
+
+	return from, to, nil
+}
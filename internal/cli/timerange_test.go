@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeRange(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	displayLocation = time.UTC
+	defer func() { displayLocation = time.Local }()
+
+	tests := []struct {
+		name      string
+		start     string
+		end       string
+		days      float64
+		wantStart time.Time
+		wantEnd   time.Time
+		wantErr   bool
+	}{
+		{name: "default", wantStart: now.Add(-24 * time.Hour), wantEnd: now},
+		{name: "days", days: 7, wantStart: now.Add(-7 * 24 * time.Hour), wantEnd: now},
+		{name: "half day", days: 0.5, wantStart: now.Add(-12 * time.Hour), wantEnd: now},
+		{
+			name:      "start and end dates",
+			start:     "2024-01-10",
+			end:       "2024-01-11 06:30",
+			wantStart: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2024, 1, 11, 6, 30, 0, 0, time.UTC),
+		},
+		{
+			name:      "end only",
+			end:       "2024-01-10T08:00:00+02:00",
+			wantStart: time.Date(2024, 1, 9, 6, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2024, 1, 10, 6, 0, 0, 0, time.UTC),
+		},
+		{name: "start with days", start: "2024-01-10", days: 1, wantErr: true},
+		{name: "negative days", days: -1, wantErr: true},
+		{name: "start after end", start: "2024-01-16", wantErr: true},
+		{name: "invalid", start: "last tuesday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := timeRange(tt.start, tt.end, tt.days, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("timeRange() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("timeRange() error = %v", err)
+			}
+			if !start.Equal(tt.wantStart) {
+				t.Errorf("start = %v, want %v", start, tt.wantStart)
+			}
+			if !end.Equal(tt.wantEnd) {
+				t.Errorf("end = %v, want %v", end, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestParseRelativeTime(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{input: "2h", want: now.Add(-2 * time.Hour)},
+		{input: "1d", want: now.Add(-24 * time.Hour)},
+		{input: "2024-01-15T08:00:00Z", want: time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC)},
+		{input: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseRelativeTime(tt.input, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseRelativeTime(%q) expected error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRelativeTime(%q) error = %v", tt.input, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseRelativeTime(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
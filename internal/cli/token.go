@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var tokenShowRaw bool
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Inspect the configured access token",
+	Long: `Inspect the access token hass-cli is configured with.
+
+Examples:
+  hass-cli token info              # Show token metadata
+  hass-cli token info --json       # Output as JSON`,
+}
+
+var tokenInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Decode the access token's metadata",
+	Long: `Decode the claims of the configured access token.
+
+Long-lived access tokens are JWTs. This shows when the token was issued and
+when it expires, along with the server it is configured for, which helps
+telling several tokens apart. The signature is not verified.
+
+The raw token is never printed unless --show-token is given.
+
+Examples:
+  hass-cli token info
+  hass-cli token info --show-token`,
+	Args: cobra.NoArgs,
+	RunE: runTokenInfo,
+}
+
+func init() {
+	rootCmd.AddCommand(tokenCmd)
+	tokenCmd.AddCommand(tokenInfoCmd)
+
+	tokenInfoCmd.Flags().BoolVar(&tokenShowRaw, "show-token", false, "Include the raw token in the output")
+}
+
+// TokenInfo is the decoded metadata of an access token.
+type TokenInfo struct {
+	Server string                 `json:"server"`
+	Token  string                 `json:"token"`
+	JWT    bool                   `json:"jwt"`
+	Claims map[string]interface{} `json:"claims,omitempty"`
+}
+
+func runTokenInfo(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	info := TokenInfo{
+		Server: cfg.Server.URL,
+		Token:  cfg.RedactedToken(),
+	}
+	if tokenShowRaw {
+		info.Token = cfg.Server.Token
+	}
+
+	claims, err := decodeJWTClaims(cfg.Server.Token)
+	if err != nil {
+		printInfo("Token is not a JWT: %v", err)
+	} else {
+		info.JWT = true
+		info.Claims = claims
+	}
+
+	if jsonOutput {
+		return outputJSON(cmd.OutOrStdout(), info)
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Server:        %s\n", info.Server)
+	fmt.Fprintf(out, "Token:         %s\n", info.Token)
+
+	if !info.JWT {
+		fmt.Fprintln(out, "\nToken is not a JWT; no metadata available")
+		return nil
+	}
+
+	if iss, ok := claims["iss"].(string); ok {
+		fmt.Fprintf(out, "Issuer:        %s\n", iss)
+	}
+	if iat, ok := claimTime(claims, "iat"); ok {
+		fmt.Fprintf(out, "Issued At:     %s\n", displayTime(iat).Format("2006-01-02 15:04:05"))
+	}
+	if exp, ok := claimTime(claims, "exp"); ok {
+		status := ""
+		if time.Now().After(exp) {
+			status = " (expired)"
+		}
+		fmt.Fprintf(out, "Expires:       %s%s\n", displayTime(exp).Format("2006-01-02 15:04:05"), status)
+	}
+
+	// Any other claims
+	var other []string
+	for key := range claims {
+		switch key {
+		case "iss", "iat", "exp":
+			continue
+		}
+		other = append(other, key)
+	}
+	if len(other) > 0 {
+		sort.Strings(other)
+		fmt.Fprintln(out, "\nOther Claims:")
+		for _, key := range other {
+			fmt.Fprintf(out, "  %s: %v\n", key, claims[key])
+		}
+	}
+
+	return nil
+}
+
+// decodeJWTClaims decodes the payload segment of a JWT without verifying
+// its signature.
+func decodeJWTClaims(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("expected 3 segments, got %d", len(parts))
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload encoding: %w", err)
+	}
+
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+
+	return claims, nil
+}
+
+// claimTime returns a NumericDate claim (seconds since the epoch) as a time.
+func claimTime(claims map[string]interface{}, key string) (time.Time, bool) {
+	v, ok := claims[key].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(v), 0), true
+}
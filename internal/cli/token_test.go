@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestDecodeJWTClaims(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"abc123","iat":1700000000,"exp":2015360000}`))
+	jwt := "eyJhbGciOiJIUzI1NiJ9." + payload + ".signature"
+
+	claims, err := decodeJWTClaims(jwt)
+	if err != nil {
+		t.Fatalf("decodeJWTClaims() error = %v", err)
+	}
+	if claims["iss"] != "abc123" {
+		t.Errorf("iss = %v, want %q", claims["iss"], "abc123")
+	}
+
+	iat, ok := claimTime(claims, "iat")
+	if !ok {
+		t.Fatal("claimTime(iat) not found")
+	}
+	if !iat.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("iat = %v, want %v", iat, time.Unix(1700000000, 0))
+	}
+
+	if _, ok := claimTime(claims, "nbf"); ok {
+		t.Error("claimTime(nbf) found, want missing")
+	}
+}
+
+func TestDecodeJWTClaims_NotJWT(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"opaque token", "abcdef0123456789"},
+		{"bad base64", "a.%%%.c"},
+		{"payload not JSON", "a." + base64.RawURLEncoding.EncodeToString([]byte("hello")) + ".c"},
+		{"empty", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeJWTClaims(tt.token); err == nil {
+				t.Errorf("decodeJWTClaims(%q) expected error", tt.token)
+			}
+		})
+	}
+}
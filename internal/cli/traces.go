@@ -0,0 +1,261 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+	"github.com/spf13/cobra"
+)
+
+var (
+	traceSince string
+	traceUntil string
+	traceLimit int
+)
+
+// addTraceTimeFlags registers --since and --until on a trace listing command.
+func addTraceTimeFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&traceSince, "since", "", "Only list runs started within this long ago (e.g., 30m, 2h, 1d) or after this time")
+	cmd.Flags().StringVar(&traceUntil, "until", "", "Only list runs started more than this long ago (e.g., 1h) or before this time")
+}
+
+// addTraceLimitFlag registers --limit on a trace listing command.
+func addTraceLimitFlag(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&traceLimit, "limit", 20, "Show only the N most recent runs (0 for all)")
+}
+
+// applyTraceLimit sorts traces newest first and keeps the --limit most
+// recent, reporting on stderr when some were left out.
+func applyTraceLimit(traces []websocket.TraceSummary) []websocket.TraceSummary {
+	limited := limitTraces(traces, traceLimit)
+	if len(limited) < len(traces) && !jsonOutput {
+		fmt.Fprintf(os.Stderr, "Showing %d of %d traces (use --limit 0 for all)\n", len(limited), len(traces))
+	}
+	return limited
+}
+
+// limitTraces sorts traces by start time, newest first, and returns at most
+// limit of them. A limit of zero or less returns all. Traces without a
+// parseable start time sort last.
+func limitTraces(traces []websocket.TraceSummary, limit int) []websocket.TraceSummary {
+	starts := make(map[string]time.Time, len(traces))
+	for _, tr := range traces {
+		if start, err := time.Parse(time.RFC3339, tr.Timestamp.Start); err == nil {
+			starts[tr.RunID] = start
+		}
+	}
+
+	sorted := append([]websocket.TraceSummary(nil), traces...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return starts[sorted[i].RunID].After(starts[sorted[j].RunID])
+	})
+
+	if limit > 0 && len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted
+}
+
+// applyTraceTimeFlags filters traces by --since and --until, and reports on
+// stderr how many were left out.
+func applyTraceTimeFlags(traces []websocket.TraceSummary) ([]websocket.TraceSummary, error) {
+	if traceSince == "" && traceUntil == "" {
+		return traces, nil
+	}
+
+	now := time.Now()
+	var since, until time.Time
+	if traceSince != "" {
+		t, err := parseRelativeTime(traceSince, now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --since: %w", err)
+		}
+		since = t
+	}
+	if traceUntil != "" {
+		t, err := parseRelativeTime(traceUntil, now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --until: %w", err)
+		}
+		until = t
+	}
+	if !since.IsZero() && !until.IsZero() && !since.Before(until) {
+		return nil, fmt.Errorf("--since must be earlier than --until")
+	}
+
+	filtered := filterTracesByTime(traces, since, until)
+	if dropped := len(traces) - len(filtered); dropped > 0 && !jsonOutput {
+		fmt.Fprintf(os.Stderr, "%d of %d traces filtered out by --since/--until\n", dropped, len(traces))
+	}
+	return filtered, nil
+}
+
+// filterTracesByTime returns the traces that started at or after since and
+// at or before until. A zero bound is not applied. Traces without a
+// parseable start time are dropped when any bound is given.
+func filterTracesByTime(traces []websocket.TraceSummary, since, until time.Time) []websocket.TraceSummary {
+	if since.IsZero() && until.IsZero() {
+		return traces
+	}
+
+	var filtered []websocket.TraceSummary
+	for _, tr := range traces {
+		start, err := time.Parse(time.RFC3339, tr.Timestamp.Start)
+		if err != nil {
+			continue
+		}
+		if !since.IsZero() && start.Before(since) {
+			continue
+		}
+		if !until.IsZero() && start.After(until) {
+			continue
+		}
+		filtered = append(filtered, tr)
+	}
+	return filtered
+}
+
+// tracePretty is --pretty on trace commands: render a single run as a tree
+// of steps instead of raw JSON.
+var tracePretty bool
+
+// addTracePrettyFlag registers --pretty on a trace command.
+func addTracePrettyFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&tracePretty, "pretty", false, "With --run-id, show the run's steps as a tree instead of raw JSON")
+}
+
+// outputTrace writes a single trace: as a step tree with --pretty, and as
+// raw JSON otherwise or when --json is given.
+func outputTrace(out io.Writer, trace *websocket.TraceDetail) error {
+	if !tracePretty || jsonOutput {
+		return outputJSON(out, trace)
+	}
+	return writeTraceTree(out, trace, isTerminal(out))
+}
+
+// traceDuration returns how long a run took, or "" if it has not finished.
+func traceDuration(ts websocket.TraceTimestamp) string {
+	start, err1 := time.Parse(time.RFC3339, ts.Start)
+	finish, err2 := time.Parse(time.RFC3339, ts.Finish)
+	if err1 != nil || err2 != nil {
+		return ""
+	}
+
+	d := finish.Sub(start)
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return d.Round(time.Millisecond).String()
+}
+
+// writeTraceTree renders a run's steps in execution order, each indented by
+// its nesting depth, with the variables it changed and its error. Steps
+// that failed are highlighted in red when color is set.
+func writeTraceTree(out io.Writer, trace *websocket.TraceDetail, color bool) error {
+	red := func(s string) string {
+		if color {
+			return "\033[31m" + s + "\033[0m"
+		}
+		return s
+	}
+
+	fmt.Fprintf(out, "Run:      %s\n", trace.RunID)
+	result := trace.State
+	if trace.ScriptExecution != "" {
+		result += " (" + trace.ScriptExecution + ")"
+	}
+	if trace.Error != "" {
+		result = red(result)
+	}
+	fmt.Fprintf(out, "State:    %s\n", result)
+	fmt.Fprintf(out, "Started:  %s\n", formatTime(trace.Timestamp.Start))
+	if d := traceDuration(trace.Timestamp); d != "" {
+		fmt.Fprintf(out, "Duration: %s\n", d)
+	}
+	if trace.Error != "" {
+		fmt.Fprintf(out, "Error:    %s\n", red(trace.Error))
+	}
+
+	if len(trace.Trace) == 0 {
+		fmt.Fprintln(out, "\nNo steps recorded")
+		return nil
+	}
+
+	paths := make([]string, 0, len(trace.Trace))
+	for path := range trace.Trace {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool { return traceStepLess(paths[i], paths[j]) })
+
+	fmt.Fprintln(out)
+	for _, path := range paths {
+		indent := strings.Repeat("  ", strings.Count(path, "/")/2)
+		for _, step := range trace.Trace[path] {
+			line := path
+			if step.Error != "" {
+				line = red(path + " ✗")
+			}
+			stamp := step.Timestamp
+			if t, err := time.Parse(time.RFC3339, step.Timestamp); err == nil {
+				stamp = displayTime(t).Format("15:04:05.000")
+			}
+			fmt.Fprintf(out, "%s%s  %s\n", indent, line, stamp)
+
+			if len(step.ChangedVariables) > 0 {
+				keys := make([]string, 0, len(step.ChangedVariables))
+				for k := range step.ChangedVariables {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					fmt.Fprintf(out, "%s    %s = %s\n", indent, k, attributeString(step.ChangedVariables[k]))
+				}
+			}
+			if step.Error != "" {
+				fmt.Fprintf(out, "%s    %s\n", indent, red("error: "+step.Error))
+			}
+		}
+	}
+
+	return nil
+}
+
+// traceSectionOrder ranks the top-level sections of a trace in the order
+// Home Assistant runs them.
+var traceSectionOrder = map[string]int{"trigger": 0, "condition": 1, "action": 2, "sequence": 2}
+
+// traceStepLess orders step paths such as "action/10/choose/0" by
+// execution: triggers, then conditions, then actions, comparing numeric
+// path segments as numbers so action/2 comes before action/10.
+func traceStepLess(a, b string) bool {
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+
+	rank := func(section string) int {
+		if r, ok := traceSectionOrder[section]; ok {
+			return r
+		}
+		return len(traceSectionOrder)
+	}
+	if ra, rb := rank(as[0]), rank(bs[0]); ra != rb {
+		return ra < rb
+	}
+
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] == bs[i] {
+			continue
+		}
+		na, errA := strconv.Atoi(as[i])
+		nb, errB := strconv.Atoi(bs[i])
+		if errA == nil && errB == nil {
+			return na < nb
+		}
+		return as[i] < bs[i]
+	}
+	return len(as) < len(bs)
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+)
+
+func TestFilterTracesByTime(t *testing.T) {
+	trace := func(runID, start string) websocket.TraceSummary {
+		return websocket.TraceSummary{RunID: runID, Timestamp: websocket.TraceTimestamp{Start: start}}
+	}
+	traces := []websocket.TraceSummary{
+		trace("before", "2024-01-15T09:59:59+00:00"),
+		trace("at-since", "2024-01-15T10:00:00+00:00"),
+		trace("inside", "2024-01-15T11:30:00.123456+00:00"),
+		trace("at-until", "2024-01-15T12:00:00+00:00"),
+		trace("after", "2024-01-15T12:00:01+00:00"),
+		trace("no-start", ""),
+	}
+
+	since := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		since time.Time
+		until time.Time
+		want  []string
+	}{
+		{"no bounds", time.Time{}, time.Time{}, []string{"before", "at-since", "inside", "at-until", "after", "no-start"}},
+		{"since", since, time.Time{}, []string{"at-since", "inside", "at-until", "after"}},
+		{"until", time.Time{}, until, []string{"before", "at-since", "inside", "at-until"}},
+		{"both", since, until, []string{"at-since", "inside", "at-until"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterTracesByTime(traces, tt.since, tt.until)
+			if len(got) != len(tt.want) {
+				t.Fatalf("filterTracesByTime() returned %d traces, want %d", len(got), len(tt.want))
+			}
+			for i, tr := range got {
+				if tr.RunID != tt.want[i] {
+					t.Errorf("trace %d = %q, want %q", i, tr.RunID, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLimitTraces(t *testing.T) {
+	trace := func(runID, start string) websocket.TraceSummary {
+		return websocket.TraceSummary{RunID: runID, Timestamp: websocket.TraceTimestamp{Start: start}}
+	}
+	traces := []websocket.TraceSummary{
+		trace("middle", "2024-01-15T11:00:00+00:00"),
+		trace("no-start", ""),
+		trace("newest", "2024-01-15T12:00:00.5+00:00"),
+		trace("other-zone", "2024-01-15T13:30:00+02:00"), // 11:30 UTC
+		trace("oldest", "2024-01-15T10:00:00+00:00"),
+	}
+
+	ids := func(list []websocket.TraceSummary) []string {
+		var out []string
+		for _, tr := range list {
+			out = append(out, tr.RunID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		limit int
+		want  []string
+	}{
+		{0, []string{"newest", "other-zone", "middle", "oldest", "no-start"}},
+		{2, []string{"newest", "other-zone"}},
+		{10, []string{"newest", "other-zone", "middle", "oldest", "no-start"}},
+	}
+
+	for _, tt := range tests {
+		got := ids(limitTraces(traces, tt.limit))
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("limitTraces(limit=%d) = %v, want %v", tt.limit, got, tt.want)
+		}
+	}
+
+	if traces[0].RunID != "middle" {
+		t.Error("limitTraces() reordered its input")
+	}
+}
+
+func TestTraceStepLess(t *testing.T) {
+	paths := []string{
+		"action/10",
+		"condition/0",
+		"action/2/choose/0",
+		"trigger/0",
+		"action/2",
+		"action/0",
+	}
+	sort.Slice(paths, func(i, j int) bool { return traceStepLess(paths[i], paths[j]) })
+
+	want := "trigger/0,condition/0,action/0,action/2,action/2/choose/0,action/10"
+	if got := strings.Join(paths, ","); got != want {
+		t.Errorf("sorted paths = %s, want %s", got, want)
+	}
+}
+
+func TestWriteTraceTree(t *testing.T) {
+	displayLocation = time.UTC
+	defer func() { displayLocation = time.Local }()
+
+	trace := &websocket.TraceDetail{
+		RunID:           "run1",
+		State:           "stopped",
+		ScriptExecution: "error",
+		Error:           "Service not found",
+		Timestamp:       websocket.TraceTimestamp{Start: "2024-01-15T10:00:00+00:00", Finish: "2024-01-15T10:00:01.5+00:00"},
+		Trace: map[string][]websocket.TraceStep{
+			"action/0/choose/0": {{Path: "action/0/choose/0", Timestamp: "2024-01-15T10:00:00.3+00:00", Error: "Service not found"}},
+			"trigger/0": {{
+				Path:             "trigger/0",
+				Timestamp:        "2024-01-15T10:00:00.1+00:00",
+				ChangedVariables: map[string]interface{}{"this": "light.a", "count": 2.0},
+			}},
+			"action/0": {{Path: "action/0", Timestamp: "2024-01-15T10:00:00.2+00:00"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeTraceTree(&buf, trace, false); err != nil {
+		t.Fatalf("writeTraceTree() error = %v", err)
+	}
+
+	want := "Run:      run1\n" +
+		"State:    stopped (error)\n" +
+		"Started:  2024-01-15 10:00:00\n" +
+		"Duration: 1.5s\n" +
+		"Error:    Service not found\n" +
+		"\n" +
+		"trigger/0  10:00:00.100\n" +
+		"    count = 2\n" +
+		"    this = light.a\n" +
+		"action/0  10:00:00.200\n" +
+		"  action/0/choose/0 ✗  10:00:00.300\n" +
+		"      error: Service not found\n"
+	if got := buf.String(); got != want {
+		t.Errorf("writeTraceTree() =\n%s\nwant:\n%s", got, want)
+	}
+}
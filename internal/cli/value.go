@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var (
+	valueAttr   string
+	valueFormat string
+)
+
+var valueCmd = &cobra.Command{
+	Use:   "value [entity_id]",
+	Short: "Print an entity's state as a single line",
+	Long: `Print only the state of an entity, or one of its attributes, on a single
+line. Meant for status bars (tmux, polybar, waybar) and shell scripts.
+
+The exit status is non-zero when the entity is unavailable or unknown; the
+state is still printed so a status bar can show it.
+
+--format shapes the line with these placeholders:
+  {value}   the state, or the attribute given with --attr
+  {unit}    the unit_of_measurement attribute (empty if unset)
+  {name}    the friendly name
+
+Without an entity ID, defaults.entity from the config file is used, so a
+status bar bound to one sensor can run plain 'hass-cli value'.
+
+Examples:
+  hass-cli value sensor.outdoor_temperature
+  hass-cli value sensor.outdoor_temperature --format '{value}{unit}'
+  hass-cli value climate.living_room --attr current_temperature
+  hass-cli value sensor.power --format '{name}: {value} {unit}'`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runValue,
+}
+
+func init() {
+	rootCmd.AddCommand(valueCmd)
+
+	valueCmd.Flags().StringVar(&valueAttr, "attr", "", "Print this attribute instead of the state")
+	valueCmd.Flags().StringVar(&valueFormat, "format", "{value}", "Output template with {value}, {unit} and {name} placeholders")
+}
+
+func runValue(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	entityID, err := entityArg(cfg, args)
+	if err != nil {
+		return err
+	}
+
+	client := newRESTClient(cfg)
+
+	state, err := client.GetState(entityID)
+	if err != nil {
+		return fmt.Errorf("failed to get state: %w", err)
+	}
+
+	line, err := renderValue(state, valueAttr, valueFormat)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), line)
+
+	if state.State == "unavailable" || state.State == "unknown" {
+		return &ExitError{Code: 1}
+	}
+	return nil
+}
+
+// renderValue fills the --format template for state. The result is always
+// a single line.
+func renderValue(state *api.State, attr, format string) (string, error) {
+	value := state.State
+	if attr != "" {
+		raw, ok := state.Attributes[attr]
+		if !ok {
+			return "", fmt.Errorf("%s has no attribute %q", state.EntityID, attr)
+		}
+		value = attributeString(raw)
+	}
+
+	unit := stateUnit(state.Attributes)
+
+	name := state.EntityID
+	if raw, ok := state.Attributes["friendly_name"]; ok {
+		name = attributeString(raw)
+	}
+
+	line := strings.NewReplacer("{value}", value, "{unit}", unit, "{name}", name).Replace(format)
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(line), nil
+}
+
+// stateUnit returns the unit_of_measurement attribute, or "" if unset.
+func stateUnit(attrs map[string]interface{}) string {
+	if raw, ok := attrs["unit_of_measurement"]; ok && raw != nil {
+		return attributeString(raw)
+	}
+	return ""
+}
+
+// attributeString renders an attribute value: strings as they are, anything
+// else as JSON.
+func attributeString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
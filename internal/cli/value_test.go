@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/dorinclisu/hass-cli/internal/api"
+)
+
+func TestRenderValue(t *testing.T) {
+	state := &api.State{
+		EntityID: "sensor.outdoor",
+		State:    "21.5",
+		Attributes: map[string]interface{}{
+			"unit_of_measurement": "°C",
+			"friendly_name":       "Outdoor",
+			"humidity":            float64(60),
+			"note":                "line one\nline two",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		attr    string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{name: "state", format: "{value}", want: "21.5"},
+		{name: "with unit", format: "{value}{unit}", want: "21.5°C"},
+		{name: "with name", format: "{name}: {value} {unit}", want: "Outdoor: 21.5 °C"},
+		{name: "numeric attribute", attr: "humidity", format: "{value}%", want: "60%"},
+		{name: "single line", attr: "note", format: "{value}", want: "line one line two"},
+		{name: "missing attribute", attr: "pressure", format: "{value}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderValue(state, tt.attr, tt.format)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("renderValue() = %q, expected error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("renderValue() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("renderValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,13 +1,18 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"sort"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/dorinclisu/hass-cli/internal/api"
 	"github.com/dorinclisu/hass-cli/internal/websocket"
 	"github.com/spf13/cobra"
 )
@@ -17,134 +22,708 @@ var watchCmd = &cobra.Command{
 	Short: "Watch entity state changes in real-time",
 	Long: `Watch for entity state changes via WebSocket.
 
-If no entity IDs are specified, watches all state changes.
+If no entity IDs are specified, watches all state changes. A single entity
+ID without wildcards is subscribed to directly, so Home Assistant only sends
+that entity's changes; otherwise all changes are received and filtered here.
 Press Ctrl+C to stop watching.
 
+If the connection drops (e.g. Home Assistant restarts), watch reconnects with
+increasing delays and subscribes again. Changes made while disconnected are
+not reported. Use --reconnect=false to exit instead. With --keepalive, the
+server is pinged at the given interval, so connections that die silently
+(e.g. behind a proxy that drops idle connections) are noticed and
+re-established instead of waiting forever. --idle-timeout does the same
+without sending anything: the connection counts as dropped once nothing was
+received for that long, so pair it with a shorter --keepalive when watching
+entities that rarely change.
+
+--jsonl writes each event as one line of compact JSON (JSON Lines), the
+usual format for log processors; it is the same as --json --compact.
+
+With --attributes, changes that leave the state as it was but change
+attributes (e.g. a light's brightness while it stays on) are followed by
+the changed attributes and their old and new values.
+
+With --poll, states are fetched over the REST API at the given interval and
+compared with the previous poll instead, for networks that block WebSocket
+connections. Changes that are undone between two polls are not reported.
+
 Examples:
   hass-cli watch                           # Watch all state changes
   hass-cli watch light.living_room         # Watch specific entity
   hass-cli watch light.* sensor.*          # Watch multiple patterns
-  hass-cli watch --json                    # Output as JSON`,
+  hass-cli watch '*motion*'                # Glob anywhere in the entity ID
+  hass-cli watch --json                    # Output as JSON
+  hass-cli watch --jsonl | jq -c .data     # One JSON object per line
+  hass-cli watch light.desk --attributes   # Also show brightness etc. changes
+  hass-cli watch --keepalive 30s           # Ping every 30s to detect dead connections
+  hass-cli watch --keepalive 30s --idle-timeout 2m
+  hass-cli watch --registry                # Report entities added/removed
+  hass-cli watch --registry --interval 5s 'sensor.*'
+  hass-cli watch --poll 5s 'light.*'       # Poll over REST, no WebSocket`,
 	RunE: runWatch,
 }
 
+var (
+	watchRegistry      bool
+	watchInterval      time.Duration
+	watchReconnect     bool
+	watchMaxReconnects int
+	watchPoll          time.Duration
+	watchKeepalive     time.Duration
+	watchIdleTimeout   time.Duration
+	watchAttributes    bool
+	watchJSONL         bool
+)
+
+// errWatchStopped is returned when Ctrl+C interrupts a reconnect.
+var errWatchStopped = errors.New("stopped watching")
+
 func init() {
 	rootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().BoolVar(&watchRegistry, "registry", false, "Report entities added to or removed from the entity registry instead of state changes")
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", 10*time.Second, "How often to poll the registry with --registry")
+	watchCmd.Flags().BoolVar(&watchReconnect, "reconnect", true, "Reconnect when the connection drops")
+	watchCmd.Flags().IntVar(&watchMaxReconnects, "max-reconnects", 0, "Give up after this many failed reconnect attempts in a row (0 = never)")
+	watchCmd.Flags().DurationVar(&watchPoll, "poll", 0, "Poll states over REST at this interval instead of using the WebSocket API")
+	watchCmd.Flags().DurationVar(&watchKeepalive, "keepalive", 0, "Ping the server at this interval to detect dead connections (0 = never)")
+	watchCmd.Flags().BoolVar(&watchJSONL, "jsonl", false, "Write each event as a single line of JSON (same as --json --compact)")
+	watchCmd.Flags().BoolVar(&watchAttributes, "attributes", false, "Show which attributes changed when the state itself did not")
+	watchCmd.Flags().DurationVar(&watchIdleTimeout, "idle-timeout", 0, "Treat the connection as dropped after receiving nothing for this long (0 = never)")
+}
+
+// RegistryChange is an entity added to or removed from the entity registry.
+type RegistryChange struct {
+	Time     string `json:"time"`
+	Change   string `json:"change"` // "added" or "removed"
+	EntityID string `json:"entity_id"`
+	Platform string `json:"platform"`
 }
 
 func runWatch(cmd *cobra.Command, args []string) error {
+	if watchJSONL {
+		if outputFormat != "table" && outputFormat != "json" {
+			return fmt.Errorf("--jsonl cannot be combined with --format %s", outputFormat)
+		}
+		// Events are encoded straight to the unbuffered stdout, so each
+		// line is written as soon as its event arrives
+		jsonOutput, compactJSON = true, true
+	}
+
 	cfg, err := loadConfig()
 	if err != nil {
 		return err
 	}
 
+	// Build entity filter
+	patterns, err := parsePatterns(args)
+	if err != nil {
+		return err
+	}
+
+	if watchPoll != 0 {
+		if watchRegistry {
+			return fmt.Errorf("--poll cannot be combined with --registry")
+		}
+		return runWatchPoll(cmd, newRESTClient(cfg), patterns)
+	}
+
+	var opts []websocket.ClientOption
+	if watchIdleTimeout > 0 {
+		opts = append(opts, websocket.WithIdleTimeout(watchIdleTimeout))
+	}
+
 	printInfo("Connecting to Home Assistant...")
-	client, err := websocket.NewClient(cfg.Server.URL, cfg.Server.Token, time.Duration(timeout)*time.Second)
+	client, err := newWSClient(cfg, opts...)
 	if err != nil {
+		if !watchRegistry {
+			return fmt.Errorf("failed to connect: %w (use --poll 5s to watch over the REST API instead)", err)
+		}
 		return fmt.Errorf("failed to connect: %w", err)
 	}
 	defer client.Close()
 
-	printInfo("Subscribing to state changes...")
-	_, err = client.SubscribeEvents("state_changed")
-	if err != nil {
-		return fmt.Errorf("failed to subscribe: %w", err)
+	if watchRegistry {
+		return runWatchRegistry(cmd, client, patterns)
 	}
 
-	// Build entity filter
-	var patterns []string
-	for _, arg := range args {
-		patterns = append(patterns, strings.ToLower(arg))
+	printInfo("Subscribing to state changes...")
+	stream, err := watchStateChanges(client, patterns)
+	if err != nil {
+		return err
 	}
 
-	fmt.Println("Watching for state changes... (press Ctrl+C to stop)")
+	printSuccess("Watching for state changes... (press Ctrl+C to stop)")
 	if len(patterns) > 0 {
-		fmt.Printf("Filtering: %s\n", strings.Join(patterns, ", "))
+		printSuccess("Filtering: %s", strings.Join(patterns, ", "))
 	}
-	fmt.Println()
+	fmt.Fprintln(os.Stderr)
+
+	return followStateChanges(client, patterns, stream, func(event *websocket.EventData) {
+		if jsonOutput {
+			outputJSON(cmd.OutOrStdout(), event)
+			return
+		}
+		printWatchChange(cmd.OutOrStdout(), event)
+	})
+}
+
+// followStateChanges passes each state change from stream that matches
+// patterns (all changes if there are none) to handle until Ctrl+C. Dropped
+// connections are re-established unless watch's --reconnect is turned off.
+// The stream is stopped on return, which closes client.
+func followStateChanges(client *websocket.Client, patterns []string, stream *watchStream, handle func(*websocket.EventData)) error {
+	defer func() {
+		if stream != nil {
+			stream.stop(client)
+		}
+	}()
 
 	// Handle Ctrl+C
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	for {
+		select {
+		case <-sigChan:
+			printSuccess("\nStopped watching")
+			return nil
+
+		case err := <-stream.errs:
+			if !watchReconnect {
+				return fmt.Errorf("connection error: %w", err)
+			}
+			// Nothing may use the client while it reconnects
+			stream.stop(client)
+			stream, err = reconnectWatch(client, patterns, err, sigChan)
+			if errors.Is(err, errWatchStopped) {
+				printSuccess("\nStopped watching")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+		case event := <-stream.events:
+			if event.Event.EventType != "state_changed" {
+				continue
+			}
+
+			entityID := event.Event.Data.EntityID
+
+			// Apply filter
+			if len(patterns) > 0 && !matchesPatterns(entityID, patterns) {
+				continue
+			}
+
+			handle(&event.Event)
+		}
+	}
+}
+
+// watchStream is a state change subscription read in the background by
+// watchStateChanges.
+type watchStream struct {
+	events <-chan *websocket.EventMessage
+	errs   <-chan error
+
+	quit chan struct{}  // closed by stop
+	wg   sync.WaitGroup // the reader and keepalive goroutines
+}
+
+// stop closes client, which fails a pending ReadEvent or Ping, and waits for
+// the background goroutines to exit. The client must be reconnected before
+// it is used again.
+func (s *watchStream) stop(client *websocket.Client) {
+	close(s.quit)
+	client.Close()
+	s.wg.Wait()
+}
+
+// watchStateChanges subscribes client to state changes and reads events in
+// the background until the connection fails, which is reported on the error
+// channel, or the stream is stopped. When patterns name a single entity, a state trigger for it is
+// subscribed to, so the server only sends that entity's changes; otherwise
+// every state change is received and patterns are applied client-side.
+// With --keepalive, a failed ping is reported on the error channel too.
+func watchStateChanges(client *websocket.Client, patterns []string) (*watchStream, error) {
+	entityID, single := singleEntity(patterns)
+	if single {
+		trigger := map[string]interface{}{"platform": "state", "entity_id": entityID}
+		if _, err := client.SubscribeTrigger(trigger); err != nil {
+			return nil, fmt.Errorf("failed to subscribe: %w", err)
+		}
+	} else if _, err := client.SubscribeEvents("state_changed"); err != nil {
+		return nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
 
-	// Event loop
 	eventChan := make(chan *websocket.EventMessage)
-	errChan := make(chan error)
+	errChan := make(chan error, 1)
+	done := make(chan struct{})
+	stream := &watchStream{events: eventChan, errs: errChan, quit: make(chan struct{})}
 
+	// Only the first failure is reported; a later one would block forever
+	fail := func(err error) {
+		select {
+		case errChan <- err:
+		default:
+		}
+	}
+
+	stream.wg.Add(1)
 	go func() {
+		defer stream.wg.Done()
+		defer close(done)
 		for {
 			event, err := client.ReadEvent()
 			if err != nil {
-				errChan <- err
+				fail(err)
+				return
+			}
+			if single {
+				// Present trigger events as the state_changed events
+				// they stand for
+				data, ok := event.StateChange()
+				if !ok {
+					continue
+				}
+				event.Event = *data
+			}
+			select {
+			case eventChan <- event:
+			case <-stream.quit:
 				return
 			}
-			eventChan <- event
 		}
 	}()
 
+	if watchKeepalive > 0 {
+		stream.wg.Add(1)
+		go func() {
+			defer stream.wg.Done()
+			keepaliveWatch(client, watchKeepalive, done, fail)
+		}()
+	}
+
+	return stream, nil
+}
+
+// keepaliveWatch pings client every interval until done is closed or a ping
+// fails, which is passed to fail. A connection that died without being
+// closed would otherwise leave ReadEvent waiting forever.
+func keepaliveWatch(client *websocket.Client, interval time.Duration, done <-chan struct{}, fail func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := client.Ping(); err != nil {
+				fail(fmt.Errorf("keepalive ping failed: %w", err))
+				return
+			}
+		}
+	}
+}
+
+// reconnectWatch reconnects client after the connection failed with cause
+// and subscribes to patterns again, retrying with watchBackoff delays up to
+// --max-reconnects times. The previous stream must already be stopped.
+// Progress goes to stderr to keep stdout clean.
+func reconnectWatch(client *websocket.Client, patterns []string, cause error, sigChan <-chan os.Signal) (*watchStream, error) {
+	for attempt := 1; watchMaxReconnects <= 0 || attempt <= watchMaxReconnects; attempt++ {
+		delay := watchBackoff(attempt)
+		printDim(os.Stderr, "connection lost (%v), reconnecting in %s...", cause, delay)
+
+		select {
+		case <-sigChan:
+			return nil, errWatchStopped
+		case <-time.After(delay):
+		}
+
+		if err := client.Reconnect(); err != nil {
+			cause = err
+			continue
+		}
+		// Make sure the new connection answers before subscribing on it
+		if err := client.Ping(); err != nil {
+			cause = err
+			continue
+		}
+		stream, err := watchStateChanges(client, patterns)
+		if err != nil {
+			cause = err
+			continue
+		}
+
+		printDim(os.Stderr, "reconnected")
+		return stream, nil
+	}
+
+	return nil, fmt.Errorf("giving up after %d reconnect attempts: %w", watchMaxReconnects, cause)
+}
+
+// singleEntity returns the entity ID when patterns consist of exactly one
+// entity ID without glob characters.
+func singleEntity(patterns []string) (string, bool) {
+	if len(patterns) != 1 || strings.ContainsAny(patterns[0], `*?[\`) || !strings.Contains(patterns[0], ".") {
+		return "", false
+	}
+	return patterns[0], true
+}
+
+// watchBackoff is the delay before reconnect attempt n (from 1): one second,
+// doubling each attempt up to 30 seconds.
+func watchBackoff(attempt int) time.Duration {
+	const maxDelay = 30 * time.Second
+	if attempt > 6 {
+		return maxDelay
+	}
+	return min(time.Second<<(attempt-1), maxDelay)
+}
+
+// printDim prints a status line, dimmed when out is a terminal.
+func printDim(out *os.File, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if isTerminal(out) {
+		msg = "\033[2m" + msg + "\033[0m"
+	}
+	fmt.Fprintln(out, msg)
+}
+
+// isTerminal reports whether w is a terminal, where ANSI styling is safe.
+// Writers that are not files, such as --output-file, never are.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// runWatchRegistry polls the entity registry and reports entities that
+// appear or disappear between polls. The registry has no change event in the
+// subscription API, so this diffs the set of entity IDs instead.
+func runWatchRegistry(cmd *cobra.Command, client *websocket.Client, patterns []string) error {
+	if watchInterval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	printInfo("Fetching entity registry...")
+	entities, err := client.GetEntities()
+	if err != nil {
+		return fmt.Errorf("failed to get entities: %w", err)
+	}
+	known := registryIndex(entities)
+
+	printSuccess("Watching %d registry entries for changes every %s... (press Ctrl+C to stop)", len(known), watchInterval)
+	if len(patterns) > 0 {
+		printSuccess("Filtering: %s", strings.Join(patterns, ", "))
+	}
+	fmt.Fprintln(os.Stderr)
+
+	// Handle Ctrl+C
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-sigChan:
-			fmt.Println("\nStopped watching")
+			printSuccess("\nStopped watching")
 			return nil
 
-		case err := <-errChan:
-			return fmt.Errorf("connection error: %w", err)
+		case now := <-ticker.C:
+			entities, err := client.GetEntities()
+			if err != nil {
+				return fmt.Errorf("failed to get entities: %w", err)
+			}
+			current := registryIndex(entities)
 
-		case event := <-eventChan:
-			if event.Event.EventType != "state_changed" {
-				continue
+			for _, change := range diffRegistry(known, current, now) {
+				if len(patterns) > 0 && !matchesPatterns(change.EntityID, patterns) {
+					continue
+				}
+
+				if jsonOutput {
+					outputJSON(cmd.OutOrStdout(), change)
+					continue
+				}
+
+				marker := "+"
+				if change.Change == "removed" {
+					marker = "-"
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "[%s] %s %s (%s)\n", displayTime(now).Format("15:04:05"), marker, change.EntityID, change.Platform)
 			}
 
-			entityID := event.Event.Data.EntityID
+			known = current
+		}
+	}
+}
 
-			// Apply filter
-			if len(patterns) > 0 && !matchesPatterns(entityID, patterns) {
+// runWatchPoll polls states over REST every --poll interval and reports
+// the changes between polls like state_changed events.
+func runWatchPoll(cmd *cobra.Command, client *api.Client, patterns []string) error {
+	if watchPoll < 0 {
+		return fmt.Errorf("--poll must be positive")
+	}
+
+	printInfo("Fetching states...")
+	known, err := pollStates(client, patterns)
+	if err != nil {
+		return err
+	}
+
+	printSuccess("Polling %d entities for state changes every %s... (press Ctrl+C to stop)", len(known), watchPoll)
+	if len(patterns) > 0 {
+		printSuccess("Filtering: %s", strings.Join(patterns, ", "))
+	}
+	fmt.Fprintln(os.Stderr)
+
+	// Handle Ctrl+C
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	ticker := time.NewTicker(watchPoll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sigChan:
+			printSuccess("\nStopped watching")
+			return nil
+
+		case now := <-ticker.C:
+			current, err := pollStates(client, patterns)
+			if err != nil {
+				if !watchReconnect {
+					return err
+				}
+				printDim(os.Stderr, "%v, retrying in %s...", err, watchPoll)
 				continue
 			}
 
-			if jsonOutput {
-				outputJSON(event.Event)
-				continue
+			for _, event := range diffStates(known, current, now) {
+				if jsonOutput {
+					outputJSON(cmd.OutOrStdout(), event)
+					continue
+				}
+				printWatchChange(cmd.OutOrStdout(), &event)
 			}
 
-			// Human-readable output
-			newState := event.Event.Data.NewState
-			oldState := event.Event.Data.OldState
+			known = current
+		}
+	}
+}
 
-			oldValue := "unavailable"
-			if oldState != nil {
-				oldValue = oldState.State
-			}
+// pollStates fetches the states matching patterns, keyed by entity ID. A
+// single entity is fetched on its own rather than with every state.
+func pollStates(client *api.Client, patterns []string) (map[string]api.State, error) {
+	states := make(map[string]api.State)
 
-			newValue := "unavailable"
-			if newState != nil {
-				newValue = newState.State
+	if entityID, ok := singleEntity(patterns); ok {
+		state, err := client.GetState(entityID)
+		if err != nil {
+			if api.IsNotFound(err) {
+				return states, nil
 			}
+			return nil, fmt.Errorf("failed to get state: %w", err)
+		}
+		states[state.EntityID] = *state
+		return states, nil
+	}
 
-			timestamp := formatEventTime(event.Event.TimeFired)
-			fmt.Printf("[%s] %s: %s -> %s\n", timestamp, entityID, oldValue, newValue)
+	all, err := client.GetStates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get states: %w", err)
+	}
+	for _, state := range all {
+		if len(patterns) > 0 && !matchesPatterns(state.EntityID, patterns) {
+			continue
 		}
+		states[state.EntityID] = state
 	}
+
+	return states, nil
 }
 
-// matchesPatterns checks if an entity ID matches any of the patterns.
-// Supports wildcards (*) for prefix matching.
-func matchesPatterns(entityID string, patterns []string) bool {
-	entityLower := strings.ToLower(entityID)
+// diffStates lists the states that changed between two polls as
+// state_changed events, sorted by entity ID. An entity that appeared has no
+// old state and one that disappeared has no new state.
+func diffStates(before, after map[string]api.State, now time.Time) []websocket.EventData {
+	var events []websocket.EventData
+	stamp := now.UTC().Format(time.RFC3339)
 
-	for _, pattern := range patterns {
-		if strings.HasSuffix(pattern, "*") {
-			prefix := strings.TrimSuffix(pattern, "*")
-			if strings.HasPrefix(entityLower, prefix) {
-				return true
-			}
-		} else if entityLower == pattern {
-			return true
+	for id, state := range after {
+		old, ok := before[id]
+		if ok && old.LastUpdated == state.LastUpdated && old.State == state.State {
+			continue
+		}
+
+		event := websocket.EventData{
+			EventType: "state_changed",
+			Data:      websocket.StateChangedData{EntityID: id, NewState: stateObject(state)},
+			TimeFired: state.LastUpdated,
+		}
+		if ok {
+			event.Data.OldState = stateObject(old)
+		}
+		if event.TimeFired == "" {
+			event.TimeFired = stamp
+		}
+		events = append(events, event)
+	}
+	for id, old := range before {
+		if _, ok := after[id]; !ok {
+			events = append(events, websocket.EventData{
+				EventType: "state_changed",
+				Data:      websocket.StateChangedData{EntityID: id, OldState: stateObject(old)},
+				TimeFired: stamp,
+			})
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Data.EntityID < events[j].Data.EntityID
+	})
+
+	return events
+}
+
+// stateObject converts a REST state into its WebSocket form.
+func stateObject(state api.State) *websocket.StateObject {
+	return &websocket.StateObject{
+		EntityID:    state.EntityID,
+		State:       state.State,
+		Attributes:  state.Attributes,
+		LastChanged: state.LastChanged,
+		LastUpdated: state.LastUpdated,
+		Context: websocket.EventContext{
+			ID:       state.Context.ID,
+			ParentID: state.Context.ParentID,
+			UserID:   state.Context.UserID,
+		},
+	}
+}
+
+// registryIndex maps entity IDs to their registry entries.
+func registryIndex(entities []websocket.Entity) map[string]websocket.Entity {
+	index := make(map[string]websocket.Entity, len(entities))
+	for _, e := range entities {
+		index[e.EntityID] = e
+	}
+	return index
+}
+
+// diffRegistry lists the entities added and removed between two polls of
+// the registry, sorted by entity ID with additions first.
+func diffRegistry(before, after map[string]websocket.Entity, now time.Time) []RegistryChange {
+	var changes []RegistryChange
+	stamp := now.UTC().Format(time.RFC3339)
+
+	for id, e := range after {
+		if _, ok := before[id]; !ok {
+			changes = append(changes, RegistryChange{Time: stamp, Change: "added", EntityID: id, Platform: e.Platform})
+		}
+	}
+	for id, e := range before {
+		if _, ok := after[id]; !ok {
+			changes = append(changes, RegistryChange{Time: stamp, Change: "removed", EntityID: id, Platform: e.Platform})
 		}
 	}
 
-	return false
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Change != changes[j].Change {
+			return changes[i].Change == "added"
+		}
+		return changes[i].EntityID < changes[j].EntityID
+	})
+
+	return changes
+}
+
+// printStateChange prints a state_changed event to out in human-readable form.
+func printStateChange(out io.Writer, event *websocket.EventData) {
+	newState := event.Data.NewState
+	oldState := event.Data.OldState
+
+	oldValue := "unavailable"
+	if oldState != nil {
+		oldValue = oldState.State
+	}
+
+	newValue := "unavailable"
+	if newState != nil {
+		newValue = newState.State
+	}
+
+	timestamp := formatEventTime(event.TimeFired)
+	fmt.Fprintf(out, "[%s] %s: %s -> %s\n", timestamp, event.Data.EntityID, oldValue, newValue)
+}
+
+// printWatchChange prints a state change for watch: the state line and, with
+// --attributes, the attributes that changed while the state stayed the same.
+func printWatchChange(out io.Writer, event *websocket.EventData) {
+	printStateChange(out, event)
+
+	oldState, newState := event.Data.OldState, event.Data.NewState
+	if !watchAttributes || oldState == nil || newState == nil || oldState.State != newState.State {
+		return
+	}
+	for _, change := range attributeChanges(oldState.Attributes, newState.Attributes) {
+		if redactOutput && isRedactedKey(change.Key) {
+			change.Old, change.New = redactedValue, redactedValue
+		}
+		fmt.Fprintf(out, "  %s: %s -> %s\n", change.Key, change.Old, change.New)
+	}
+}
+
+// attributeChange is one attribute that differs between two states. Old or
+// New is "(none)" when the attribute was added or removed.
+type attributeChange struct {
+	Key string
+	Old string
+	New string
+}
+
+// attributeChanges compares two attribute maps and returns the attributes
+// that were added, removed or changed, sorted by key.
+func attributeChanges(before, after map[string]interface{}) []attributeChange {
+	render := func(attrs map[string]interface{}, key string) string {
+		v, ok := attrs[key]
+		if !ok {
+			return "(none)"
+		}
+		return attributeString(v)
+	}
+
+	keys := make(map[string]bool, len(after))
+	for k := range before {
+		keys[k] = true
+	}
+	for k := range after {
+		keys[k] = true
+	}
+
+	var changes []attributeChange
+	for k := range keys {
+		oldValue, newValue := render(before, k), render(after, k)
+		if oldValue != newValue {
+			changes = append(changes, attributeChange{Key: k, Old: oldValue, New: newValue})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
 }
 
 // formatEventTime formats an event timestamp.
@@ -157,5 +736,5 @@ func formatEventTime(timestamp string) string {
 			return timestamp
 		}
 	}
-	return t.Local().Format("15:04:05")
+	return displayTime(t).Format("15:04:05")
 }
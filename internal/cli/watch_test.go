@@ -1,93 +1,16 @@
 package cli
 
 import (
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
-)
-
-func TestMatchesPatterns(t *testing.T) {
-	tests := []struct {
-		name     string
-		entityID string
-		patterns []string
-		want     bool
-	}{
-		{
-			name:     "exact match",
-			entityID: "light.living_room",
-			patterns: []string{"light.living_room"},
-			want:     true,
-		},
-		{
-			name:     "no match",
-			entityID: "light.living_room",
-			patterns: []string{"light.bedroom"},
-			want:     false,
-		},
-		{
-			name:     "wildcard prefix match",
-			entityID: "light.living_room",
-			patterns: []string{"light.*"},
-			want:     true,
-		},
-		{
-			name:     "wildcard no match",
-			entityID: "sensor.temperature",
-			patterns: []string{"light.*"},
-			want:     false,
-		},
-		{
-			name:     "multiple patterns first matches",
-			entityID: "light.living_room",
-			patterns: []string{"light.*", "sensor.*"},
-			want:     true,
-		},
-		{
-			name:     "multiple patterns second matches",
-			entityID: "sensor.temperature",
-			patterns: []string{"light.*", "sensor.*"},
-			want:     true,
-		},
-		{
-			name:     "multiple patterns none match",
-			entityID: "switch.outlet",
-			patterns: []string{"light.*", "sensor.*"},
-			want:     false,
-		},
-		{
-			name:     "case insensitive",
-			entityID: "Light.Living_Room",
-			patterns: []string{"light.living_room"},
-			want:     true,
-		},
-		{
-			name:     "empty patterns",
-			entityID: "light.living_room",
-			patterns: []string{},
-			want:     false,
-		},
-		{
-			name:     "wildcard all match",
-			entityID: "anything.at_all",
-			patterns: []string{"*"},
-			want:     true,
-		},
-		{
-			name:     "partial domain wildcard",
-			entityID: "light.living_room",
-			patterns: []string{"li*"},
-			want:     true,
-		},
-	}
+	"time"
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := matchesPatterns(tt.entityID, tt.patterns)
-			if got != tt.want {
-				t.Errorf("matchesPatterns(%q, %v) = %v, want %v", tt.entityID, tt.patterns, got, tt.want)
-			}
-		})
-	}
-}
+	"github.com/dorinclisu/hass-cli/internal/api"
+	"github.com/dorinclisu/hass-cli/internal/testutil"
+	"github.com/dorinclisu/hass-cli/internal/websocket"
+)
 
 func TestFormatEventTime(t *testing.T) {
 	tests := []struct {
@@ -133,3 +56,197 @@ func TestFormatEventTime(t *testing.T) {
 		})
 	}
 }
+
+func TestDiffRegistry(t *testing.T) {
+	before := registryIndex([]websocket.Entity{
+		{EntityID: "light.kitchen", Platform: "hue"},
+		{EntityID: "sensor.old", Platform: "zha"},
+	})
+	after := registryIndex([]websocket.Entity{
+		{EntityID: "light.kitchen", Platform: "hue"},
+		{EntityID: "sensor.new_b", Platform: "mqtt"},
+		{EntityID: "sensor.new_a", Platform: "mqtt"},
+	})
+
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	changes := diffRegistry(before, after, now)
+
+	want := []RegistryChange{
+		{Time: "2024-01-15T12:00:00Z", Change: "added", EntityID: "sensor.new_a", Platform: "mqtt"},
+		{Time: "2024-01-15T12:00:00Z", Change: "added", EntityID: "sensor.new_b", Platform: "mqtt"},
+		{Time: "2024-01-15T12:00:00Z", Change: "removed", EntityID: "sensor.old", Platform: "zha"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("diffRegistry() = %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("diffRegistry()[%d] = %v, want %v", i, changes[i], want[i])
+		}
+	}
+
+	if changes := diffRegistry(after, after, now); len(changes) != 0 {
+		t.Errorf("diffRegistry() on unchanged registry = %v, want none", changes)
+	}
+}
+
+func TestWatchBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{5, 16 * time.Second},
+		{6, 30 * time.Second},
+		{100, 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := watchBackoff(tt.attempt); got != tt.want {
+			t.Errorf("watchBackoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestSingleEntity(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		want     string
+		wantOK   bool
+	}{
+		{[]string{"light.kitchen"}, "light.kitchen", true},
+		{[]string{"light.*"}, "", false},
+		{[]string{"*motion*"}, "", false},
+		{[]string{"sensor.temp_[12]"}, "", false},
+		{[]string{"light.kitchen", "light.hall"}, "", false},
+		{[]string{"kitchen"}, "", false},
+		{nil, "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := singleEntity(tt.patterns)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("singleEntity(%v) = %q, %v, want %q, %v", tt.patterns, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestDiffStates(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	before := map[string]api.State{
+		"light.same":    {EntityID: "light.same", State: "on", LastUpdated: "t1"},
+		"light.changed": {EntityID: "light.changed", State: "off", LastUpdated: "t1"},
+		"sensor.attr":   {EntityID: "sensor.attr", State: "20", LastUpdated: "t1"},
+		"light.removed": {EntityID: "light.removed", State: "on", LastUpdated: "t1"},
+	}
+	after := map[string]api.State{
+		"light.same":    {EntityID: "light.same", State: "on", LastUpdated: "t1"},
+		"light.changed": {EntityID: "light.changed", State: "on", LastUpdated: "t2"},
+		"sensor.attr":   {EntityID: "sensor.attr", State: "20", LastUpdated: "t2"},
+		"light.added":   {EntityID: "light.added", State: "off", LastUpdated: "t2"},
+	}
+
+	events := diffStates(before, after, now)
+	if len(events) != 4 {
+		t.Fatalf("diffStates() returned %d events, want 4: %+v", len(events), events)
+	}
+
+	added, changed, removed, attr := events[0], events[1], events[2], events[3]
+	if added.Data.EntityID != "light.added" || added.Data.OldState != nil || added.Data.NewState.State != "off" {
+		t.Errorf("added = %+v", added.Data)
+	}
+	if changed.Data.EntityID != "light.changed" || changed.Data.OldState.State != "off" || changed.Data.NewState.State != "on" || changed.TimeFired != "t2" {
+		t.Errorf("changed = %+v", changed)
+	}
+	if removed.Data.EntityID != "light.removed" || removed.Data.NewState != nil || removed.TimeFired != "2024-01-15T12:00:00Z" {
+		t.Errorf("removed = %+v", removed)
+	}
+	if attr.Data.EntityID != "sensor.attr" || attr.EventType != "state_changed" {
+		t.Errorf("attribute change = %+v", attr)
+	}
+}
+
+func TestAttributeChanges(t *testing.T) {
+	before := map[string]interface{}{
+		"brightness":    128.0,
+		"color_mode":    "brightness",
+		"friendly_name": "Desk",
+		"effect":        "none",
+		"rgb_color":     []interface{}{255.0, 0.0, 0.0},
+	}
+	after := map[string]interface{}{
+		"brightness":    200.0,
+		"color_mode":    "brightness",
+		"friendly_name": "Desk",
+		"rgb_color":     []interface{}{255.0, 0.0, 0.0},
+		"color_temp":    350.0,
+	}
+
+	got := attributeChanges(before, after)
+	want := []attributeChange{
+		{Key: "brightness", Old: "128", New: "200"},
+		{Key: "color_temp", Old: "(none)", New: "350"},
+		{Key: "effect", Old: "none", New: "(none)"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("attributeChanges() = %+v, want %+v", got, want)
+	}
+
+	if got := attributeChanges(before, before); len(got) != 0 {
+		t.Errorf("attributeChanges() of equal attributes = %+v, want none", got)
+	}
+}
+
+func TestFollowStateChanges_ReconnectAfterPingFailure(t *testing.T) {
+	mock := testutil.NewWSMock(t, testToken)
+	// Each connection is served by its own goroutine, so count atomically
+	var pings, subscriptions atomic.Int32
+	mock.Handle("ping", func(msg map[string]interface{}) (interface{}, error) {
+		if pings.Add(1) == 1 {
+			// The connection stays up, so the reader is still waiting on it
+			return nil, &testutil.WSError{Code: "timeout", Message: "no pong"}
+		}
+		return nil, nil
+	})
+	mock.Handle("subscribe_events", func(msg map[string]interface{}) (interface{}, error) {
+		switch subscriptions.Add(1) {
+		case 1:
+			return nil, nil
+		case 2:
+			return &testutil.WSSubscription{Drop: true, Events: []interface{}{map[string]interface{}{
+				"event_type": "state_changed",
+				"data":       map[string]interface{}{"entity_id": "light.kitchen"},
+			}}}, nil
+		default:
+			return nil, &testutil.WSError{Code: "unavailable", Message: "shutting down"}
+		}
+	})
+
+	savedKeepalive, savedReconnect, savedMax := watchKeepalive, watchReconnect, watchMaxReconnects
+	defer func() { watchKeepalive, watchReconnect, watchMaxReconnects = savedKeepalive, savedReconnect, savedMax }()
+	watchKeepalive, watchReconnect, watchMaxReconnects = 20*time.Millisecond, true, 1
+
+	client, err := websocket.NewClient(mock.URL(), testToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	stream, err := watchStateChanges(client, nil)
+	if err != nil {
+		t.Fatalf("watchStateChanges() error = %v", err)
+	}
+
+	var entityIDs []string
+	err = followStateChanges(client, nil, stream, func(event *websocket.EventData) {
+		entityIDs = append(entityIDs, event.Data.EntityID)
+	})
+	// The first reconnect delivers the event; the second one gives up
+	if err == nil || !strings.Contains(err.Error(), "giving up after 1 reconnect attempts") {
+		t.Fatalf("followStateChanges() error = %v, want giving up after 1 attempt", err)
+	}
+	if !slices.Equal(entityIDs, []string{"light.kitchen"}) {
+		t.Errorf("handled entities = %v, want [light.kitchen]", entityIDs)
+	}
+}
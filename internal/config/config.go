@@ -4,8 +4,13 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -26,10 +31,17 @@ type ServerConfig struct {
 type DefaultsConfig struct {
 	Output  string `yaml:"output"`
 	Timeout int    `yaml:"timeout"`
+	// Entity is used by single-entity commands such as 'state get' and
+	// 'value' when no entity ID is given on the command line.
+	Entity string `yaml:"entity,omitempty"`
 }
 
+// entityIDPattern matches a Home Assistant entity ID (domain.object_id).
+var entityIDPattern = regexp.MustCompile(`^[a-z0-9_]+\.[a-z0-9_]+$`)
+
 // ErrNotConfigured is returned when the config file doesn't exist or is incomplete.
-var ErrNotConfigured = errors.New("hass-cli not configured. Run 'hass-cli login' first")
+var ErrNotConfigured = errors.New("hass-cli not configured. Run 'hass-cli login' first, or set HASS_URL and HASS_TOKEN " +
+	"(precedence: --url/--token flags, then HASS_URL/HASS_TOKEN, then the config file)")
 
 // DefaultConfigPath returns the default configuration file path.
 func DefaultConfigPath() string {
@@ -67,6 +79,9 @@ func LoadFrom(path string) (*Config, error) {
 	if cfg.Defaults.Timeout == 0 {
 		cfg.Defaults.Timeout = 30
 	}
+	if cfg.Defaults.Entity != "" && !entityIDPattern.MatchString(cfg.Defaults.Entity) {
+		return nil, fmt.Errorf("invalid defaults.entity %q in config file (expected domain.object_id)", cfg.Defaults.Entity)
+	}
 
 	return &cfg, nil
 }
@@ -116,6 +131,66 @@ func DeleteFrom(path string) error {
 	return nil
 }
 
+// OutputFormats are the accepted values of defaults.output. "human" is the
+// aligned table, the same as "table".
+var OutputFormats = []string{"human", "table", "json", "tsv", "csv"}
+
+// Keys are the settings that can be read with Get and changed with Set.
+var Keys = []string{"server.url", "defaults.output", "defaults.timeout", "defaults.entity"}
+
+// Get returns the value of a setting by its key in the config file, such
+// as "defaults.timeout".
+func (c *Config) Get(key string) (string, error) {
+	switch key {
+	case "server.url":
+		return c.Server.URL, nil
+	case "defaults.output":
+		return c.Defaults.Output, nil
+	case "defaults.timeout":
+		return strconv.Itoa(c.Defaults.Timeout), nil
+	case "defaults.entity":
+		return c.Defaults.Entity, nil
+	default:
+		return "", unknownKeyError(key)
+	}
+}
+
+// Set validates value and stores it in the setting named by key.
+func (c *Config) Set(key, value string) error {
+	switch key {
+	case "server.url":
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid server.url %q (expected http:// or https:// URL)", value)
+		}
+		c.Server.URL = strings.TrimSuffix(value, "/")
+	case "defaults.output":
+		output := strings.ToLower(value)
+		if !slices.Contains(OutputFormats, output) {
+			return fmt.Errorf("invalid defaults.output %q (expected one of: %s)", value, strings.Join(OutputFormats, ", "))
+		}
+		c.Defaults.Output = output
+	case "defaults.timeout":
+		timeout, err := strconv.Atoi(value)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid defaults.timeout %q (expected a positive number of seconds)", value)
+		}
+		c.Defaults.Timeout = timeout
+	case "defaults.entity":
+		if value != "" && !entityIDPattern.MatchString(value) {
+			return fmt.Errorf("invalid defaults.entity %q (expected domain.object_id)", value)
+		}
+		c.Defaults.Entity = value
+	default:
+		return unknownKeyError(key)
+	}
+	return nil
+}
+
+func unknownKeyError(key string) error {
+	return fmt.Errorf("unknown config key %q (expected one of: %s)", key, strings.Join(Keys, ", "))
+}
+
 // RedactedToken returns the token with most characters replaced by asterisks.
 func (c *Config) RedactedToken() string {
 	if len(c.Server.Token) <= 8 {
@@ -67,6 +67,43 @@ server:
 		}
 	})
 
+	t.Run("loads default entity", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.yaml")
+		os.WriteFile(path, []byte(`
+server:
+  url: http://localhost:8123
+  token: abc
+defaults:
+  entity: sensor.outdoor_temperature
+`), 0600)
+
+		cfg, err := LoadFrom(path)
+		if err != nil {
+			t.Fatalf("LoadFrom() error = %v", err)
+		}
+		if cfg.Defaults.Entity != "sensor.outdoor_temperature" {
+			t.Errorf("Entity = %q, want %q", cfg.Defaults.Entity, "sensor.outdoor_temperature")
+		}
+	})
+
+	t.Run("returns error for invalid default entity", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.yaml")
+		os.WriteFile(path, []byte(`
+server:
+  url: http://localhost:8123
+  token: abc
+defaults:
+  entity: Outdoor Temperature
+`), 0600)
+
+		_, err := LoadFrom(path)
+		if err == nil {
+			t.Error("LoadFrom() expected error for invalid defaults.entity")
+		}
+	})
+
 	t.Run("returns error for invalid YAML", func(t *testing.T) {
 		dir := t.TempDir()
 		path := filepath.Join(dir, "config.yaml")
@@ -271,3 +308,58 @@ func TestDeleteFrom(t *testing.T) {
 		}
 	})
 }
+
+func TestGetSet(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{key: "server.url", value: "https://ha.example.com:8123/", want: "https://ha.example.com:8123"},
+		{key: "server.url", value: "ha.example.com", wantErr: true},
+		{key: "server.url", value: "ftp://ha.example.com", wantErr: true},
+		{key: "defaults.output", value: "JSON", want: "json"},
+		{key: "defaults.output", value: "xml", wantErr: true},
+		{key: "defaults.timeout", value: "60", want: "60"},
+		{key: "defaults.timeout", value: "0", wantErr: true},
+		{key: "defaults.timeout", value: "-5", wantErr: true},
+		{key: "defaults.timeout", value: "soon", wantErr: true},
+		{key: "defaults.entity", value: "sensor.outdoor", want: "sensor.outdoor"},
+		{key: "defaults.entity", value: "outdoor", wantErr: true},
+		{key: "server.token", value: "secret", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			cfg := &Config{Defaults: DefaultsConfig{Output: "human", Timeout: 30}}
+			before := *cfg
+
+			err := cfg.Set(tt.key, tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Set(%q, %q) expected error", tt.key, tt.value)
+				}
+				if *cfg != before {
+					t.Errorf("Set(%q, %q) changed the config on error: %+v", tt.key, tt.value, cfg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Set(%q, %q) error = %v", tt.key, tt.value, err)
+			}
+
+			got, err := cfg.Get(tt.key)
+			if err != nil {
+				t.Fatalf("Get(%q) error = %v", tt.key, err)
+			}
+			if got != tt.want {
+				t.Errorf("Get(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := (&Config{}).Get("server.token"); err == nil {
+		t.Error("Get(server.token) expected error")
+	}
+}
@@ -2,10 +2,12 @@ package testutil
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -14,12 +16,36 @@ import (
 // The handler receives the full message map and returns the result payload.
 type WSHandler func(msg map[string]interface{}) (interface{}, error)
 
+// WSError is an error a WSHandler can return to fail a command with a
+// specific error code. Other errors are reported as "command_error".
+type WSError struct {
+	Code    string
+	Message string
+}
+
+func (e *WSError) Error() string {
+	return e.Message
+}
+
+// ErrDropConnection can be returned by a WSHandler to close the connection
+// without answering the command, like a server going away mid-request.
+var ErrDropConnection = errors.New("drop connection")
+
+// WSSubscription can be returned by a WSHandler to accept a subscription and
+// then send Events on it, like Home Assistant does when they fire. With Drop
+// set, the connection is closed after the last event.
+type WSSubscription struct {
+	Events []interface{}
+	Drop   bool
+}
+
 // WSMock wraps httptest.Server with WebSocket support for testing the WS client.
 type WSMock struct {
-	Server   *httptest.Server
-	Token    string
-	mu       sync.Mutex
-	handlers map[string]WSHandler
+	Server    *httptest.Server
+	Token     string
+	mu        sync.Mutex
+	handlers  map[string]WSHandler
+	authDelay time.Duration
 }
 
 var upgrader = websocket.Upgrader{
@@ -44,7 +70,12 @@ func NewWSMock(t *testing.T, token string) *WSMock {
 		}
 		defer conn.Close()
 
-		// Step 1: Send auth_required
+		// Step 1: Send auth_required (optionally delayed, like a busy server)
+		m.mu.Lock()
+		delay := m.authDelay
+		m.mu.Unlock()
+		time.Sleep(delay)
+
 		conn.WriteJSON(map[string]interface{}{
 			"type":       "auth_required",
 			"ha_version": "2024.1.0",
@@ -93,6 +124,15 @@ func NewWSMock(t *testing.T, token string) *WSMock {
 			handler, ok := m.handlers[msgType]
 			m.mu.Unlock()
 
+			if !ok && msgType == "ping" {
+				// Answer pings like Home Assistant unless a test overrides them
+				conn.WriteJSON(map[string]interface{}{
+					"id":   int(msgID),
+					"type": "pong",
+				})
+				continue
+			}
+
 			if !ok {
 				// No handler, return error
 				conn.WriteJSON(map[string]interface{}{
@@ -108,19 +148,47 @@ func NewWSMock(t *testing.T, token string) *WSMock {
 			}
 
 			result, err := handler(msg)
+			if errors.Is(err, ErrDropConnection) {
+				return
+			}
 			if err != nil {
+				code := "command_error"
+				var wsErr *WSError
+				if errors.As(err, &wsErr) {
+					code = wsErr.Code
+				}
 				conn.WriteJSON(map[string]interface{}{
 					"id":      int(msgID),
 					"type":    "result",
 					"success": false,
 					"error": map[string]string{
-						"code":    "command_error",
+						"code":    code,
 						"message": err.Error(),
 					},
 				})
 				continue
 			}
 
+			if sub, ok := result.(*WSSubscription); ok {
+				conn.WriteJSON(map[string]interface{}{
+					"id":      int(msgID),
+					"type":    "result",
+					"success": true,
+					"result":  nil,
+				})
+				for _, event := range sub.Events {
+					conn.WriteJSON(map[string]interface{}{
+						"id":    int(msgID),
+						"type":  "event",
+						"event": event,
+					})
+				}
+				if sub.Drop {
+					return
+				}
+				continue
+			}
+
 			// Marshal and re-unmarshal the result so it becomes json.RawMessage compatible
 			resultJSON, _ := json.Marshal(result)
 			var rawResult json.RawMessage = resultJSON
@@ -152,3 +220,10 @@ func (m *WSMock) Handle(msgType string, handler WSHandler) {
 	defer m.mu.Unlock()
 	m.handlers[msgType] = handler
 }
+
+// SetAuthDelay delays the initial auth_required frame on new connections.
+func (m *WSMock) SetAuthDelay(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.authDelay = d
+}
@@ -1,8 +1,11 @@
 package websocket
 
 import (
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/url"
 	"strings"
 	"sync"
@@ -12,48 +15,130 @@ import (
 )
 
 // Client is a WebSocket client for Home Assistant.
+//
+// SendCommand and the registry helpers built on it may be called from
+// multiple goroutines, also while another goroutine is in ReadEvent.
+// Commands are written as soon as they are issued and a single reader
+// goroutine matches results to their callers by message ID, so independent
+// requests overlap on the wire instead of waiting for each other.
 type Client struct {
 	conn      *websocket.Conn
+	url       string
 	token     string
 	msgID     int
 	msgIDLock sync.Mutex
 	timeout   time.Duration
+	tlsConfig *tls.Config
+
+	// idleTimeout makes ReadEvent fail when nothing at all was received
+	// for this long. Zero waits forever.
+	idleTimeout time.Duration
+
+	writeLock sync.Mutex
+	reader    *reader
+}
+
+// ClientOption configures optional Client behavior.
+type ClientOption func(*Client)
+
+// WithTLSConfig sets the TLS configuration used when dialing a wss:// URL.
+func WithTLSConfig(tlsConfig *tls.Config) ClientOption {
+	return func(c *Client) {
+		c.tlsConfig = tlsConfig
+	}
+}
+
+// WithIdleTimeout makes ReadEvent return ErrIdleTimeout once no message of
+// any kind was received for d, so a connection that died without being
+// closed is noticed. Pings count as traffic, so pinging more often than d
+// keeps quiet but healthy connections from timing out.
+func WithIdleTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.idleTimeout = d
+	}
 }
 
+// ErrIdleTimeout is returned by ReadEvent when the connection was silent for
+// longer than the idle timeout set with WithIdleTimeout.
+var ErrIdleTimeout = errors.New("connection idle timeout")
+
 // NewClient creates a new WebSocket client.
 func NewClient(baseURL, token string, timeout time.Duration) (*Client, error) {
+	return NewClientWithOptions(baseURL, token, timeout)
+}
+
+// NewClientWithOptions creates a new WebSocket client with options.
+func NewClientWithOptions(baseURL, token string, timeout time.Duration, opts ...ClientOption) (*Client, error) {
 	// Convert HTTP URL to WebSocket URL
 	wsURL, err := httpToWS(baseURL)
 	if err != nil {
 		return nil, err
 	}
 
-	// Connect to WebSocket
-	dialer := websocket.Dialer{
-		HandshakeTimeout: timeout,
-	}
-
-	conn, _, err := dialer.Dial(wsURL+"/api/websocket", nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect: %w", err)
-	}
-
 	client := &Client{
-		conn:    conn,
+		url:     wsURL,
 		token:   token,
 		msgID:   0,
 		timeout: timeout,
 	}
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	conn, err := client.dial()
+	if err != nil {
+		return nil, err
+	}
+	client.conn = conn
 
 	// Authenticate
-	if err := client.authenticate(); err != nil {
+	if err := client.authenticate(conn); err != nil {
 		conn.Close()
 		return nil, err
 	}
+	client.reader = newReader(conn)
 
 	return client, nil
 }
 
+// dial opens a WebSocket connection to the client's server.
+func (c *Client) dial() (*websocket.Conn, error) {
+	dialer := websocket.Dialer{
+		HandshakeTimeout: c.timeout,
+		TLSClientConfig:  c.tlsConfig,
+	}
+
+	conn, _, err := dialer.Dial(c.url+"/api/websocket", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	return conn, nil
+}
+
+// Reconnect replaces the connection with a new, authenticated one, for
+// recovering from a ConnectionError. Results still pending on the old
+// connection and event subscriptions are lost. It must not be called while
+// other goroutines are using the client.
+func (c *Client) Reconnect() error {
+	conn, err := c.dial()
+	if err != nil {
+		return err
+	}
+	if err := c.authenticate(conn); err != nil {
+		conn.Close()
+		return err
+	}
+
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	c.reader.close()
+	c.conn.Close()
+	c.conn = conn
+	c.reader = newReader(conn)
+	return nil
+}
+
 // httpToWS converts an HTTP(S) URL to a WebSocket URL.
 func httpToWS(httpURL string) (string, error) {
 	u, err := url.Parse(httpURL)
@@ -73,32 +158,42 @@ func httpToWS(httpURL string) (string, error) {
 	return strings.TrimSuffix(u.String(), "/"), nil
 }
 
-// authenticate performs the authentication handshake.
-func (c *Client) authenticate() error {
-	// Set read deadline for auth
-	c.conn.SetReadDeadline(time.Now().Add(c.timeout))
+// authRequiredGrace is extra time allowed for the server's first frame.
+var authRequiredGrace = 5 * time.Second
+
+// authenticate performs the authentication handshake on conn.
+func (c *Client) authenticate(conn *websocket.Conn) error {
+	// A busy server (e.g. during startup) can be slow to send the first
+	// frame, so allow a grace period on top of the normal timeout.
+	conn.SetReadDeadline(time.Now().Add(c.timeout + authRequiredGrace))
 
 	// Read auth_required message
 	var authRequired AuthRequiredMessage
-	if err := c.conn.ReadJSON(&authRequired); err != nil {
+	if err := conn.ReadJSON(&authRequired); err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return fmt.Errorf("timed out waiting for auth_required after %s", c.timeout+authRequiredGrace)
+		}
 		return fmt.Errorf("failed to read auth_required: %w", err)
 	}
 
 	if authRequired.Type != "auth_required" {
-		return fmt.Errorf("expected auth_required, got %s", authRequired.Type)
+		return fmt.Errorf("expected auth_required, got %q", authRequired.Type)
 	}
 
+	conn.SetReadDeadline(time.Now().Add(c.timeout))
+
 	// Send auth message
 	authMsg := AuthMessage{
 		Type:        "auth",
 		AccessToken: c.token,
 	}
-	if err := c.conn.WriteJSON(authMsg); err != nil {
+	if err := conn.WriteJSON(authMsg); err != nil {
 		return fmt.Errorf("failed to send auth: %w", err)
 	}
 
 	// Read auth response
-	_, msg, err := c.conn.ReadMessage()
+	_, msg, err := conn.ReadMessage()
 	if err != nil {
 		return fmt.Errorf("failed to read auth response: %w", err)
 	}
@@ -115,7 +210,7 @@ func (c *Client) authenticate() error {
 	case "auth_invalid":
 		var authInvalid AuthInvalidMessage
 		json.Unmarshal(msg, &authInvalid)
-		return fmt.Errorf("authentication failed: %s", authInvalid.Message)
+		return fmt.Errorf("%w: %s", ErrAuthInvalid, authInvalid.Message)
 	default:
 		return fmt.Errorf("unexpected auth response: %s", baseMsg.Type)
 	}
@@ -123,6 +218,7 @@ func (c *Client) authenticate() error {
 
 // Close closes the WebSocket connection.
 func (c *Client) Close() error {
+	c.reader.close()
 	return c.conn.Close()
 }
 
@@ -138,7 +234,86 @@ func (c *Client) nextID() int {
 func (c *Client) SendCommand(msgType string, payload map[string]interface{}) (*ResultMessage, error) {
 	id := c.nextID()
 
-	// Build message
+	ch, err := c.reader.register(id)
+	if err != nil {
+		return nil, &ConnectionError{Op: "failed to send command", Err: err}
+	}
+	if err := c.writeMessage(commandMessage(id, msgType, payload)); err != nil {
+		c.reader.cancel(id)
+		return nil, &ConnectionError{Op: "failed to send command", Err: err}
+	}
+
+	result, err := c.waitResult(id, ch)
+	if err != nil {
+		return nil, &ConnectionError{Op: "failed to read response", Err: err}
+	}
+
+	return checkResult(result)
+}
+
+// Ping sends a ping and waits for the server's pong, to check that the
+// connection is still alive.
+func (c *Client) Ping() error {
+	_, err := c.SendCommand("ping", nil)
+	return err
+}
+
+// Command is a single command sent with SendCommandsConcurrent.
+type Command struct {
+	Type    string
+	Payload map[string]interface{}
+}
+
+// CommandResult is the outcome of one command sent with
+// SendCommandsConcurrent. Err is set exactly as SendCommand would return it.
+type CommandResult struct {
+	Result *ResultMessage
+	Err    error
+}
+
+// SendCommandsConcurrent pipelines several commands over the connection:
+// all of them are written before any response is read, and responses are
+// matched to their command by message ID, so the server can work on them
+// in parallel. Results are returned in the order of cmds.
+func (c *Client) SendCommandsConcurrent(cmds []Command) []CommandResult {
+	results := make([]CommandResult, len(cmds))
+	ids := make([]int, len(cmds))
+	chans := make([]chan *ResultMessage, len(cmds))
+
+	sent := 0
+	for i, cmd := range cmds {
+		ids[i] = c.nextID()
+		ch, err := c.reader.register(ids[i])
+		if err == nil {
+			if err = c.writeMessage(commandMessage(ids[i], cmd.Type, cmd.Payload)); err != nil {
+				c.reader.cancel(ids[i])
+			}
+		}
+		if err != nil {
+			// Nothing after a failed write can be delivered either
+			for j := i; j < len(cmds); j++ {
+				results[j].Err = &ConnectionError{Op: "failed to send command", Err: err}
+			}
+			break
+		}
+		chans[i] = ch
+		sent++
+	}
+
+	for i := 0; i < sent; i++ {
+		result, err := c.waitResult(ids[i], chans[i])
+		if err != nil {
+			results[i].Err = &ConnectionError{Op: "failed to read response", Err: err}
+			continue
+		}
+		results[i].Result, results[i].Err = checkResult(result)
+	}
+
+	return results
+}
+
+// commandMessage builds the message for a command with the given ID.
+func commandMessage(id int, msgType string, payload map[string]interface{}) map[string]interface{} {
 	msg := map[string]interface{}{
 		"id":   id,
 		"type": msgType,
@@ -146,40 +321,100 @@ func (c *Client) SendCommand(msgType string, payload map[string]interface{}) (*R
 	for k, v := range payload {
 		msg[k] = v
 	}
+	return msg
+}
 
-	// Set write deadline
-	c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
-
-	// Send message
-	if err := c.conn.WriteJSON(msg); err != nil {
-		return nil, fmt.Errorf("failed to send command: %w", err)
+// checkResult turns an unsuccessful result into an error.
+func checkResult(result *ResultMessage) (*ResultMessage, error) {
+	if !result.Success {
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		return nil, fmt.Errorf("command failed")
 	}
+	return result, nil
+}
 
-	// Set read deadline
-	c.conn.SetReadDeadline(time.Now().Add(c.timeout))
+// ConnectionError is returned by SendCommand when the connection itself
+// fails while sending a command or waiting for its result, as opposed to the
+// server rejecting the command. Whether the command took effect is unknown.
+type ConnectionError struct {
+	Op  string
+	Err error
+}
 
-	// Read response(s) until we get the result for our ID
-	for {
-		_, data, err := c.conn.ReadMessage()
-		if err != nil {
-			return nil, fmt.Errorf("failed to read response: %w", err)
-		}
+func (e *ConnectionError) Error() string {
+	return e.Op + ": " + e.Err.Error()
+}
 
-		var result ResultMessage
-		if err := json.Unmarshal(data, &result); err != nil {
-			continue // Skip messages we can't parse
-		}
+func (e *ConnectionError) Unwrap() error {
+	return e.Err
+}
 
-		if result.ID == id && result.Type == "result" {
-			if !result.Success {
-				if result.Error != nil {
-					return nil, fmt.Errorf("%s: %s", result.Error.Code, result.Error.Message)
-				}
-				return nil, fmt.Errorf("command failed")
-			}
-			return &result, nil
+// ErrAuthInvalid is returned when the server rejects the access token.
+var ErrAuthInvalid = errors.New("authentication failed")
+
+// IsConnectionError reports whether err is a ConnectionError, after which
+// the client must be reconnected before further use.
+func IsConnectionError(err error) bool {
+	var connErr *ConnectionError
+	return errors.As(err, &connErr)
+}
+
+// IsUnknownCommand reports whether err is the server rejecting a command it
+// does not know, which usually means the Home Assistant version is too old.
+func IsUnknownCommand(err error) bool {
+	var cmdErr *ErrorResult
+	return errors.As(err, &cmdErr) && cmdErr.Code == "unknown_command"
+}
+
+// IsNotFound reports whether err is the server rejecting a command because
+// the item it refers to does not exist.
+func IsNotFound(err error) bool {
+	var cmdErr *ErrorResult
+	return errors.As(err, &cmdErr) && cmdErr.Code == "not_found"
+}
+
+// writeMessage writes a single message to the connection.
+func (c *Client) writeMessage(msg interface{}) error {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	return c.conn.WriteJSON(msg)
+}
+
+// waitResult waits up to the client timeout for the result of id, which
+// the reader delivers on ch.
+func (c *Client) waitResult(id int, ch chan *ResultMessage) (*ResultMessage, error) {
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	select {
+	case result := <-ch:
+		return result, nil
+	case <-c.reader.done:
+		// The result may have been delivered just before the reader stopped
+		select {
+		case result := <-ch:
+			return result, nil
+		default:
+			return nil, c.reader.readErr()
 		}
+	case <-timer.C:
+		c.reader.cancel(id)
+		return nil, fmt.Errorf("timed out after %s", c.timeout)
+	}
+}
+
+// decodeResult unmarshals the result payload of a successful command into v.
+// Commands may succeed without returning a result (or with a null one), in
+// which case v is left at its zero value.
+func decodeResult(result *ResultMessage, v interface{}) error {
+	if len(result.Result) == 0 || string(result.Result) == "null" {
+		return nil
 	}
+	return json.Unmarshal(result.Result, v)
 }
 
 // GetDevices retrieves all devices from the device registry.
@@ -190,7 +425,7 @@ func (c *Client) GetDevices() ([]Device, error) {
 	}
 
 	var devices []Device
-	if err := json.Unmarshal(result.Result, &devices); err != nil {
+	if err := decodeResult(result, &devices); err != nil {
 		return nil, fmt.Errorf("failed to parse devices: %w", err)
 	}
 
@@ -205,13 +440,61 @@ func (c *Client) GetAreas() ([]Area, error) {
 	}
 
 	var areas []Area
-	if err := json.Unmarshal(result.Result, &areas); err != nil {
+	if err := decodeResult(result, &areas); err != nil {
 		return nil, fmt.Errorf("failed to parse areas: %w", err)
 	}
 
 	return areas, nil
 }
 
+// GetLabels retrieves all labels from the label registry.
+func (c *Client) GetLabels() ([]Label, error) {
+	result, err := c.SendCommand("config/label_registry/list", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var labels []Label
+	if err := decodeResult(result, &labels); err != nil {
+		return nil, fmt.Errorf("failed to parse labels: %w", err)
+	}
+
+	return labels, nil
+}
+
+// GetFloors retrieves all floors from the floor registry.
+func (c *Client) GetFloors() ([]Floor, error) {
+	result, err := c.SendCommand("config/floor_registry/list", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var floors []Floor
+	if err := decodeResult(result, &floors); err != nil {
+		return nil, fmt.Errorf("failed to parse floors: %w", err)
+	}
+
+	return floors, nil
+}
+
+// CreateArea creates an area with the given name. Home Assistant derives
+// the area ID from the name; the returned area carries it.
+func (c *Client) CreateArea(name string) (*Area, error) {
+	result, err := c.SendCommand("config/area_registry/create", map[string]interface{}{
+		"name": name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var area Area
+	if err := decodeResult(result, &area); err != nil {
+		return nil, fmt.Errorf("failed to parse area: %w", err)
+	}
+
+	return &area, nil
+}
+
 // GetEntities retrieves all entities from the entity registry.
 func (c *Client) GetEntities() ([]Entity, error) {
 	result, err := c.SendCommand("config/entity_registry/list", nil)
@@ -220,13 +503,53 @@ func (c *Client) GetEntities() ([]Entity, error) {
 	}
 
 	var entities []Entity
-	if err := json.Unmarshal(result.Result, &entities); err != nil {
+	if err := decodeResult(result, &entities); err != nil {
 		return nil, fmt.Errorf("failed to parse entities: %w", err)
 	}
 
 	return entities, nil
 }
 
+// Registries holds the area, device and entity registries fetched by
+// GetRegistries. A registry that failed to load is nil and its error is set
+// in the matching field, so callers can decide which ones they need.
+type Registries struct {
+	Areas       []Area
+	Devices     []Device
+	Entities    []Entity
+	AreasErr    error
+	DevicesErr  error
+	EntitiesErr error
+}
+
+// GetRegistries retrieves the area, device and entity registries in a
+// single round trip using SendCommandsConcurrent.
+func (c *Client) GetRegistries() *Registries {
+	results := c.SendCommandsConcurrent([]Command{
+		{Type: "config/area_registry/list"},
+		{Type: "config/device_registry/list"},
+		{Type: "config/entity_registry/list"},
+	})
+
+	var r Registries
+	r.AreasErr = decodeCommandResult(results[0], &r.Areas, "areas")
+	r.DevicesErr = decodeCommandResult(results[1], &r.Devices, "devices")
+	r.EntitiesErr = decodeCommandResult(results[2], &r.Entities, "entities")
+	return &r
+}
+
+// decodeCommandResult decodes a pipelined command's result into v, or
+// returns the command's error.
+func decodeCommandResult(r CommandResult, v interface{}, what string) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	if err := decodeResult(r.Result, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", what, err)
+	}
+	return nil
+}
+
 // SubscribeEvents subscribes to events and returns the subscription ID.
 // eventType can be empty to subscribe to all events, or a specific type like "state_changed".
 func (c *Client) SubscribeEvents(eventType string) (int, error) {
@@ -240,55 +563,86 @@ func (c *Client) SubscribeEvents(eventType string) (int, error) {
 		msg["event_type"] = eventType
 	}
 
-	c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
-	if err := c.conn.WriteJSON(msg); err != nil {
-		return 0, fmt.Errorf("failed to subscribe: %w", err)
+	return c.subscribe(id, msg)
+}
+
+// SubscribeTrigger subscribes to an automation trigger, such as
+// {"platform": "state", "entity_id": "light.kitchen"}, and returns the
+// subscription ID. An event is sent each time the trigger fires.
+func (c *Client) SubscribeTrigger(trigger map[string]interface{}) (int, error) {
+	id := c.nextID()
+
+	msg := map[string]interface{}{
+		"id":      id,
+		"type":    "subscribe_trigger",
+		"trigger": trigger,
 	}
 
-	// Wait for result
-	c.conn.SetReadDeadline(time.Now().Add(c.timeout))
-	for {
-		_, data, err := c.conn.ReadMessage()
-		if err != nil {
-			return 0, fmt.Errorf("failed to read subscription response: %w", err)
-		}
+	return c.subscribe(id, msg)
+}
 
-		var result ResultMessage
-		if err := json.Unmarshal(data, &result); err != nil {
-			continue
-		}
+// subscribe sends a subscription message with the given ID and waits for
+// the server to confirm it.
+func (c *Client) subscribe(id int, msg map[string]interface{}) (int, error) {
+	ch, err := c.reader.register(id)
+	if err != nil {
+		return 0, &ConnectionError{Op: "failed to subscribe", Err: err}
+	}
+	if err := c.writeMessage(msg); err != nil {
+		c.reader.cancel(id)
+		return 0, &ConnectionError{Op: "failed to subscribe", Err: err}
+	}
 
-		if result.ID == id && result.Type == "result" {
-			if !result.Success {
-				if result.Error != nil {
-					return 0, fmt.Errorf("%s: %s", result.Error.Code, result.Error.Message)
-				}
-				return 0, fmt.Errorf("subscription failed")
-			}
-			return id, nil
+	// Wait for result
+	result, err := c.waitResult(id, ch)
+	if err != nil {
+		return 0, &ConnectionError{Op: "failed to read subscription response", Err: err}
+	}
+
+	if !result.Success {
+		if result.Error != nil {
+			return 0, fmt.Errorf("%s: %s", result.Error.Code, result.Error.Message)
 		}
+		return 0, fmt.Errorf("subscription failed")
 	}
+	return id, nil
 }
 
 // ReadEvent reads the next event from the WebSocket.
-// This blocks until an event is received or context is cancelled.
+// This blocks until an event is received or the connection fails. Events
+// queued before a failure are still returned first. With WithIdleTimeout,
+// a connection that stays silent for the idle timeout counts as failed.
 func (c *Client) ReadEvent() (*EventMessage, error) {
-	// Clear deadline for long-running reads
-	c.conn.SetReadDeadline(time.Time{})
-
-	for {
-		_, data, err := c.conn.ReadMessage()
-		if err != nil {
-			return nil, fmt.Errorf("failed to read event: %w", err)
-		}
+	r := c.reader
 
-		var msg EventMessage
-		if err := json.Unmarshal(data, &msg); err != nil {
-			continue // Skip messages we can't parse
-		}
+	// A nil channel never fires, which disables the idle timeout
+	var timer *time.Timer
+	var idle <-chan time.Time
+	if c.idleTimeout > 0 {
+		timer = time.NewTimer(c.idleTimeout - r.idleFor())
+		defer timer.Stop()
+		idle = timer.C
+	}
 
-		if msg.Type == "event" {
-			return &msg, nil
+	for {
+		select {
+		case event := <-r.events:
+			return event, nil
+		case <-r.done:
+			select {
+			case event := <-r.events:
+				return event, nil
+			default:
+				return nil, fmt.Errorf("failed to read event: %w", r.readErr())
+			}
+		case <-idle:
+			// Other messages (e.g. pongs) may have arrived meanwhile
+			silent := r.idleFor()
+			if silent >= c.idleTimeout {
+				return nil, fmt.Errorf("failed to read event: %w (nothing received for %s)", ErrIdleTimeout, silent.Round(time.Second))
+			}
+			// Wait out the rest of the window counted from the last message
+			timer.Reset(c.idleTimeout - silent)
 		}
 	}
 }
@@ -301,7 +655,7 @@ func (c *Client) GetStates() ([]StateObject, error) {
 	}
 
 	var states []StateObject
-	if err := json.Unmarshal(result.Result, &states); err != nil {
+	if err := decodeResult(result, &states); err != nil {
 		return nil, fmt.Errorf("failed to parse states: %w", err)
 	}
 
@@ -327,7 +681,7 @@ func (c *Client) UpdateDevice(deviceID string, updates map[string]interface{}) (
 	}
 
 	var device Device
-	if err := json.Unmarshal(result.Result, &device); err != nil {
+	if err := decodeResult(result, &device); err != nil {
 		return nil, fmt.Errorf("failed to parse device: %w", err)
 	}
 
@@ -348,6 +702,23 @@ func (c *Client) EnableDevice(deviceID string) (*Device, error) {
 	})
 }
 
+// GetEntity retrieves a single entry from the entity registry.
+func (c *Client) GetEntity(entityID string) (*Entity, error) {
+	result, err := c.SendCommand("config/entity_registry/get", map[string]interface{}{
+		"entity_id": entityID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var entity Entity
+	if err := decodeResult(result, &entity); err != nil {
+		return nil, fmt.Errorf("failed to parse entity: %w", err)
+	}
+
+	return &entity, nil
+}
+
 // UpdateEntity updates an entity in the entity registry.
 func (c *Client) UpdateEntity(entityID string, updates map[string]interface{}) (*Entity, error) {
 	updates["entity_id"] = entityID
@@ -358,7 +729,7 @@ func (c *Client) UpdateEntity(entityID string, updates map[string]interface{}) (
 	}
 
 	var entity Entity
-	if err := json.Unmarshal(result.Result, &entity); err != nil {
+	if err := decodeResult(result, &entity); err != nil {
 		return nil, fmt.Errorf("failed to parse entity: %w", err)
 	}
 
@@ -389,7 +760,7 @@ func (c *Client) CreateInputSelect(name string, options []string, icon string) (
 	}
 
 	var helper HelperItem
-	if err := json.Unmarshal(result.Result, &helper); err != nil {
+	if err := decodeResult(result, &helper); err != nil {
 		return nil, fmt.Errorf("failed to parse helper: %w", err)
 	}
 
@@ -411,7 +782,7 @@ func (c *Client) CreateInputBoolean(name string, icon string) (*HelperItem, erro
 	}
 
 	var helper HelperItem
-	if err := json.Unmarshal(result.Result, &helper); err != nil {
+	if err := decodeResult(result, &helper); err != nil {
 		return nil, fmt.Errorf("failed to parse helper: %w", err)
 	}
 
@@ -433,7 +804,7 @@ func (c *Client) CreateInputButton(name string, icon string) (*HelperItem, error
 	}
 
 	var helper HelperItem
-	if err := json.Unmarshal(result.Result, &helper); err != nil {
+	if err := decodeResult(result, &helper); err != nil {
 		return nil, fmt.Errorf("failed to parse helper: %w", err)
 	}
 
@@ -464,7 +835,7 @@ func (c *Client) CreateInputNumber(name string, min, max, step float64, mode, ic
 	}
 
 	var helper HelperItem
-	if err := json.Unmarshal(result.Result, &helper); err != nil {
+	if err := decodeResult(result, &helper); err != nil {
 		return nil, fmt.Errorf("failed to parse helper: %w", err)
 	}
 
@@ -494,7 +865,7 @@ func (c *Client) CreateInputText(name string, min, max int, mode, pattern, icon
 	}
 
 	var helper HelperItem
-	if err := json.Unmarshal(result.Result, &helper); err != nil {
+	if err := decodeResult(result, &helper); err != nil {
 		return nil, fmt.Errorf("failed to parse helper: %w", err)
 	}
 
@@ -553,6 +924,7 @@ type TraceDetail struct {
 	RunID           string                 `json:"run_id"`
 	State           string                 `json:"state"`
 	ScriptExecution string                 `json:"script_execution"`
+	Error           string                 `json:"error,omitempty"`
 	Timestamp       TraceTimestamp         `json:"timestamp"`
 	Domain          string                 `json:"domain"`
 	ItemID          string                 `json:"item_id"`
@@ -589,7 +961,7 @@ func (c *Client) ListTraces(domain, itemID string) ([]TraceSummary, error) {
 	}
 
 	var traces []TraceSummary
-	if err := json.Unmarshal(result.Result, &traces); err != nil {
+	if err := decodeResult(result, &traces); err != nil {
 		return nil, fmt.Errorf("failed to parse traces: %w", err)
 	}
 
@@ -608,9 +980,193 @@ func (c *Client) GetTrace(domain, itemID, runID string) (*TraceDetail, error) {
 	}
 
 	var trace TraceDetail
-	if err := json.Unmarshal(result.Result, &trace); err != nil {
+	if err := decodeResult(result, &trace); err != nil {
 		return nil, fmt.Errorf("failed to parse trace: %w", err)
 	}
 
 	return &trace, nil
 }
+
+// ConfigFlow represents an integration config flow that is in progress.
+type ConfigFlow struct {
+	FlowID  string            `json:"flow_id"`
+	Handler string            `json:"handler"`
+	StepID  string            `json:"step_id"`
+	Context ConfigFlowContext `json:"context"`
+}
+
+// ConfigFlowContext describes how a config flow was started.
+type ConfigFlowContext struct {
+	Source            string                 `json:"source"`
+	UniqueID          *string                `json:"unique_id,omitempty"`
+	TitlePlaceholders map[string]interface{} `json:"title_placeholders,omitempty"`
+}
+
+// GetConfigFlows retrieves the config flows currently in progress.
+func (c *Client) GetConfigFlows() ([]ConfigFlow, error) {
+	result, err := c.SendCommand("config_entries/flow/progress", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var flows []ConfigFlow
+	if err := decodeResult(result, &flows); err != nil {
+		return nil, fmt.Errorf("failed to parse config flows: %w", err)
+	}
+
+	return flows, nil
+}
+
+// Themes is the frontend's theme configuration.
+type Themes struct {
+	Themes           map[string]map[string]interface{} `json:"themes"`
+	DefaultTheme     string                            `json:"default_theme"`
+	DefaultDarkTheme *string                           `json:"default_dark_theme"`
+}
+
+// GetThemes retrieves the frontend themes and which ones are the default.
+func (c *Client) GetThemes() (*Themes, error) {
+	result, err := c.SendCommand("frontend/get_themes", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var themes Themes
+	if err := decodeResult(result, &themes); err != nil {
+		return nil, fmt.Errorf("failed to parse themes: %w", err)
+	}
+
+	return &themes, nil
+}
+
+// ExposeEntities sets whether the given entities are exposed to each of the
+// given voice assistants (e.g. "conversation", "cloud.alexa").
+func (c *Client) ExposeEntities(assistants, entityIDs []string, expose bool) error {
+	_, err := c.SendCommand("homeassistant/expose_entity", map[string]interface{}{
+		"assistants":    assistants,
+		"entity_ids":    entityIDs,
+		"should_expose": expose,
+	})
+	return err
+}
+
+// ListExposedEntities retrieves the voice assistant exposure settings of every
+// entity that has any, keyed by entity ID and then by assistant.
+func (c *Client) ListExposedEntities() (map[string]map[string]bool, error) {
+	result, err := c.SendCommand("homeassistant/expose_entity/list", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		ExposedEntities map[string]map[string]bool `json:"exposed_entities"`
+	}
+	if err := decodeResult(result, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse exposed entities: %w", err)
+	}
+
+	return resp.ExposedEntities, nil
+}
+
+// BackupInfo describes a backup known to the backup integration.
+type BackupInfo struct {
+	BackupID string  `json:"backup_id"`
+	Name     string  `json:"name"`
+	Date     string  `json:"date"`
+	Size     float64 `json:"size"` // in MB
+}
+
+// UnmarshalJSON accepts the "slug" key that Home Assistant used for the
+// backup ID before 2025.1.
+func (b *BackupInfo) UnmarshalJSON(data []byte) error {
+	type plain BackupInfo
+	var raw struct {
+		plain
+		Slug string `json:"slug"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*b = BackupInfo(raw.plain)
+	if b.BackupID == "" {
+		b.BackupID = raw.Slug
+	}
+	return nil
+}
+
+// ListBackups retrieves the backups known to the backup integration.
+func (c *Client) ListBackups() ([]BackupInfo, error) {
+	result, err := c.SendCommand("backup/info", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Backups []BackupInfo `json:"backups"`
+	}
+	if err := decodeResult(result, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse backups: %w", err)
+	}
+
+	return resp.Backups, nil
+}
+
+// BackupAgent is a location the backup integration can store backups in,
+// such as the local disk or Home Assistant Cloud.
+type BackupAgent struct {
+	AgentID string `json:"agent_id"`
+	Name    string `json:"name"`
+}
+
+// ListBackupAgents retrieves the backup agents. Servers before 2025.1 have
+// none and fail with unknown_command.
+func (c *Client) ListBackupAgents() ([]BackupAgent, error) {
+	result, err := c.SendCommand("backup/agents/info", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Agents []BackupAgent `json:"agents"`
+	}
+	if err := decodeResult(result, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse backup agents: %w", err)
+	}
+
+	return resp.Agents, nil
+}
+
+// CreateBackup starts generating a backup stored by agentIDs and returns its
+// ID (the job ID on servers that report one). Generation continues on the
+// server after the command returns. An empty name lets the server pick one;
+// agentIDs must be empty on servers before 2025.1, which have no agents.
+func (c *Client) CreateBackup(name string, agentIDs []string) (string, error) {
+	payload := map[string]interface{}{}
+	if name != "" {
+		payload["name"] = name
+	}
+	if len(agentIDs) > 0 {
+		payload["agent_ids"] = agentIDs
+	}
+
+	result, err := c.SendCommand("backup/generate", payload)
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		BackupJobID string `json:"backup_job_id"`
+		BackupID    string `json:"backup_id"`
+		Slug        string `json:"slug"`
+	}
+	if err := decodeResult(result, &resp); err != nil {
+		return "", fmt.Errorf("failed to parse backup result: %w", err)
+	}
+
+	for _, id := range []string{resp.BackupJobID, resp.BackupID, resp.Slug} {
+		if id != "" {
+			return id, nil
+		}
+	}
+	return "", nil
+}
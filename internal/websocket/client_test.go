@@ -1,7 +1,12 @@
 package websocket
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -127,6 +132,66 @@ func TestWSClient_GetDevices(t *testing.T) {
 	}
 }
 
+func TestWSClient_GetLabels(t *testing.T) {
+	mock := testutil.NewWSMock(t, wsTestToken)
+	mock.Handle("config/label_registry/list", func(msg map[string]interface{}) (interface{}, error) {
+		return []map[string]interface{}{
+			{"label_id": "battery", "name": "Battery", "color": "red", "icon": "mdi:battery"},
+			{"label_id": "outdoor", "name": "Outdoor", "color": nil, "icon": nil},
+		}, nil
+	})
+
+	client, err := NewClient(mock.URL(), wsTestToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	labels, err := client.GetLabels()
+	if err != nil {
+		t.Fatalf("GetLabels() error = %v", err)
+	}
+	if len(labels) != 2 {
+		t.Fatalf("GetLabels() returned %d labels, want 2", len(labels))
+	}
+	if labels[0].LabelID != "battery" || labels[0].Color == nil || *labels[0].Color != "red" {
+		t.Errorf("labels[0] = %+v, want battery with color red", labels[0])
+	}
+	if labels[1].Color != nil || labels[1].Icon != nil {
+		t.Errorf("labels[1] = %+v, want no color or icon", labels[1])
+	}
+}
+
+func TestWSClient_GetFloors(t *testing.T) {
+	mock := testutil.NewWSMock(t, wsTestToken)
+	mock.Handle("config/floor_registry/list", func(msg map[string]interface{}) (interface{}, error) {
+		return []map[string]interface{}{
+			{"floor_id": "ground", "name": "Ground Floor", "level": 0, "icon": "mdi:home-floor-0"},
+			{"floor_id": "attic", "name": "Attic", "level": nil},
+		}, nil
+	})
+
+	client, err := NewClient(mock.URL(), wsTestToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	floors, err := client.GetFloors()
+	if err != nil {
+		t.Fatalf("GetFloors() error = %v", err)
+	}
+	if len(floors) != 2 {
+		t.Fatalf("GetFloors() returned %d floors, want 2", len(floors))
+	}
+	if floors[0].FloorID != "ground" || floors[0].Level == nil || *floors[0].Level != 0 {
+		t.Errorf("floors[0] = %+v, want ground at level 0", floors[0])
+	}
+	if floors[1].Level != nil {
+		t.Errorf("floors[1].Level = %d, want nil", *floors[1].Level)
+	}
+}
+
 func TestWSClient_GetAreas(t *testing.T) {
 	mock := testutil.NewWSMock(t, wsTestToken)
 	mock.Handle("config/area_registry/list", func(msg map[string]interface{}) (interface{}, error) {
@@ -163,6 +228,55 @@ func TestWSClient_GetAreas(t *testing.T) {
 	}
 }
 
+func TestWSClient_CreateArea(t *testing.T) {
+	mock := testutil.NewWSMock(t, wsTestToken)
+	mock.Handle("config/area_registry/create", func(msg map[string]interface{}) (interface{}, error) {
+		if msg["name"] != "Garden Shed" {
+			t.Errorf("name = %v, want %q", msg["name"], "Garden Shed")
+		}
+		return map[string]interface{}{"area_id": "garden_shed", "name": "Garden Shed"}, nil
+	})
+
+	client, err := NewClient(mock.URL(), wsTestToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	area, err := client.CreateArea("Garden Shed")
+	if err != nil {
+		t.Fatalf("CreateArea() error = %v", err)
+	}
+	if area.AreaID != "garden_shed" || area.Name != "Garden Shed" {
+		t.Errorf("CreateArea() = %+v", area)
+	}
+}
+
+func TestWSClient_Ping(t *testing.T) {
+	mock := testutil.NewWSMock(t, wsTestToken)
+
+	client, err := NewClient(mock.URL(), wsTestToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	for i := 0; i < 3; i++ {
+		if err := client.Ping(); err != nil {
+			t.Fatalf("Ping() #%d error = %v", i+1, err)
+		}
+	}
+
+	mock.Handle("ping", func(msg map[string]interface{}) (interface{}, error) {
+		return nil, testutil.ErrDropConnection
+	})
+	err = client.Ping()
+	var connErr *ConnectionError
+	if !errors.As(err, &connErr) {
+		t.Errorf("Ping() on dropped connection error = %v, want ConnectionError", err)
+	}
+}
+
 func TestWSClient_GetEntities(t *testing.T) {
 	mock := testutil.NewWSMock(t, wsTestToken)
 	mock.Handle("config/entity_registry/list", func(msg map[string]interface{}) (interface{}, error) {
@@ -201,6 +315,170 @@ func TestWSClient_GetEntities(t *testing.T) {
 	}
 }
 
+func TestWSClient_SendCommandsConcurrent(t *testing.T) {
+	mock := testutil.NewWSMock(t, wsTestToken)
+	mock.Handle("test/echo", func(msg map[string]interface{}) (interface{}, error) {
+		return map[string]interface{}{"value": msg["value"]}, nil
+	})
+
+	client, err := NewClient(mock.URL(), wsTestToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	results := client.SendCommandsConcurrent([]Command{
+		{Type: "test/echo", Payload: map[string]interface{}{"value": "first"}},
+		{Type: "test/unknown"},
+		{Type: "test/echo", Payload: map[string]interface{}{"value": "third"}},
+	})
+	if len(results) != 3 {
+		t.Fatalf("SendCommandsConcurrent() returned %d results, want 3", len(results))
+	}
+
+	for i, want := range map[int]string{0: "first", 2: "third"} {
+		if results[i].Err != nil {
+			t.Fatalf("results[%d].Err = %v", i, results[i].Err)
+		}
+		var got struct {
+			Value string `json:"value"`
+		}
+		if err := json.Unmarshal(results[i].Result.Result, &got); err != nil {
+			t.Fatalf("results[%d] decode error = %v", i, err)
+		}
+		if got.Value != want {
+			t.Errorf("results[%d].value = %q, want %q", i, got.Value, want)
+		}
+	}
+
+	var errResult *ErrorResult
+	if !errors.As(results[1].Err, &errResult) || errResult.Code != "unknown_command" {
+		t.Errorf("results[1].Err = %v, want unknown_command error", results[1].Err)
+	}
+}
+
+func TestWSClient_SendCommand_ConcurrentCallers(t *testing.T) {
+	mock := testutil.NewWSMock(t, wsTestToken)
+	mock.Handle("test/echo", func(msg map[string]interface{}) (interface{}, error) {
+		return map[string]interface{}{"value": msg["value"]}, nil
+	})
+
+	client, err := NewClient(mock.URL(), wsTestToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	const callers = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := client.SendCommand("test/echo", map[string]interface{}{"value": i})
+			if err != nil {
+				errs <- fmt.Errorf("call %d: %w", i, err)
+				return
+			}
+			var got struct {
+				Value int `json:"value"`
+			}
+			if err := json.Unmarshal(result.Result, &got); err != nil {
+				errs <- fmt.Errorf("call %d: decode: %w", i, err)
+				return
+			}
+			if got.Value != i {
+				errs <- fmt.Errorf("call %d got the result for %d", i, got.Value)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestWSClient_GetRegistries(t *testing.T) {
+	mock := testutil.NewWSMock(t, wsTestToken)
+
+	var mu sync.Mutex
+	requested := make(map[string]int)
+	record := func(msg map[string]interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		requested[msg["type"].(string)]++
+	}
+
+	mock.Handle("config/area_registry/list", func(msg map[string]interface{}) (interface{}, error) {
+		record(msg)
+		return []map[string]interface{}{{"area_id": "kitchen", "name": "Kitchen"}}, nil
+	})
+	mock.Handle("config/device_registry/list", func(msg map[string]interface{}) (interface{}, error) {
+		record(msg)
+		return []map[string]interface{}{{"id": "dev1", "area_id": "kitchen"}}, nil
+	})
+	mock.Handle("config/entity_registry/list", func(msg map[string]interface{}) (interface{}, error) {
+		record(msg)
+		return []map[string]interface{}{
+			{"entity_id": "light.kitchen", "device_id": "dev1"},
+			{"entity_id": "sensor.temp", "area_id": "kitchen"},
+		}, nil
+	})
+
+	client, err := NewClient(mock.URL(), wsTestToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	r := client.GetRegistries()
+	if r.AreasErr != nil || r.DevicesErr != nil || r.EntitiesErr != nil {
+		t.Fatalf("GetRegistries() errors = %v, %v, %v", r.AreasErr, r.DevicesErr, r.EntitiesErr)
+	}
+	if len(r.Areas) != 1 || r.Areas[0].AreaID != "kitchen" {
+		t.Errorf("Areas = %+v", r.Areas)
+	}
+	if len(r.Devices) != 1 || r.Devices[0].ID != "dev1" {
+		t.Errorf("Devices = %+v", r.Devices)
+	}
+	if len(r.Entities) != 2 {
+		t.Errorf("got %d entities, want 2", len(r.Entities))
+	}
+	for _, msgType := range []string{"config/area_registry/list", "config/device_registry/list", "config/entity_registry/list"} {
+		if requested[msgType] != 1 {
+			t.Errorf("%s requested %d times, want 1", msgType, requested[msgType])
+		}
+	}
+}
+
+func TestWSClient_GetRegistries_Failure(t *testing.T) {
+	// Only the area registry is available; the others fail with unknown_command
+	mock := testutil.NewWSMock(t, wsTestToken)
+	mock.Handle("config/area_registry/list", func(msg map[string]interface{}) (interface{}, error) {
+		return []map[string]interface{}{{"area_id": "kitchen", "name": "Kitchen"}}, nil
+	})
+
+	client, err := NewClient(mock.URL(), wsTestToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	r := client.GetRegistries()
+	if r.AreasErr != nil || len(r.Areas) != 1 {
+		t.Errorf("Areas = %+v, err = %v", r.Areas, r.AreasErr)
+	}
+	if r.DevicesErr == nil || r.Devices != nil {
+		t.Errorf("Devices = %+v, err = %v, want error", r.Devices, r.DevicesErr)
+	}
+	if r.EntitiesErr == nil || r.Entities != nil {
+		t.Errorf("Entities = %+v, err = %v, want error", r.Entities, r.EntitiesErr)
+	}
+}
+
 func TestWSClient_UpdateDevice(t *testing.T) {
 	mock := testutil.NewWSMock(t, wsTestToken)
 	mock.Handle("config/device_registry/update", func(msg map[string]interface{}) (interface{}, error) {
@@ -448,6 +726,58 @@ func TestWSClient_CreateInputSelect(t *testing.T) {
 	}
 }
 
+func TestWSClient_ReadEvent_IdleTimeout(t *testing.T) {
+	mock := testutil.NewWSMock(t, wsTestToken)
+
+	idle := 200 * time.Millisecond
+	client, err := NewClientWithOptions(mock.URL(), wsTestToken, 5*time.Second, WithIdleTimeout(idle))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	// A pong halfway through the window pushes the timeout back
+	time.Sleep(idle / 2)
+	if err := client.Ping(); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	pinged := time.Now()
+
+	_, err = client.ReadEvent()
+	if !errors.Is(err, ErrIdleTimeout) {
+		t.Fatalf("ReadEvent() error = %v, want ErrIdleTimeout", err)
+	}
+	if waited := time.Since(pinged); waited < idle*9/10 {
+		t.Errorf("ReadEvent() timed out %s after the last message, want about %s", waited, idle)
+	}
+}
+
+func TestWSClient_SubscribeTrigger(t *testing.T) {
+	mock := testutil.NewWSMock(t, wsTestToken)
+	var got map[string]interface{}
+	mock.Handle("subscribe_trigger", func(msg map[string]interface{}) (interface{}, error) {
+		got, _ = msg["trigger"].(map[string]interface{})
+		return nil, nil
+	})
+
+	client, err := NewClient(mock.URL(), wsTestToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	id, err := client.SubscribeTrigger(map[string]interface{}{"platform": "state", "entity_id": "light.kitchen"})
+	if err != nil {
+		t.Fatalf("SubscribeTrigger() error = %v", err)
+	}
+	if id == 0 {
+		t.Error("SubscribeTrigger() returned subscription ID 0")
+	}
+	if got["platform"] != "state" || got["entity_id"] != "light.kitchen" {
+		t.Errorf("trigger = %v", got)
+	}
+}
+
 func TestWSClient_CreateInputBoolean(t *testing.T) {
 	mock := testutil.NewWSMock(t, wsTestToken)
 	mock.Handle("input_boolean/create", func(msg map[string]interface{}) (interface{}, error) {
@@ -717,3 +1047,426 @@ func TestWSClient_MessageIDIncrement(t *testing.T) {
 		}
 	}
 }
+
+func TestDecodeResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  json.RawMessage
+		want    []string
+		wantErr bool
+	}{
+		{name: "missing result", result: nil, want: nil},
+		{name: "null result", result: json.RawMessage("null"), want: nil},
+		{name: "list result", result: json.RawMessage(`["a","b"]`), want: []string{"a", "b"}},
+		{name: "invalid result", result: json.RawMessage(`{"a":1}`), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := decodeResult(&ResultMessage{Success: true, Result: tt.result}, &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("decodeResult() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeResult() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("decodeResult() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("decodeResult()[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWSClient_NullResult(t *testing.T) {
+	mock := testutil.NewWSMock(t, wsTestToken)
+	mock.Handle("config/entity_registry/update", func(msg map[string]interface{}) (interface{}, error) {
+		return nil, nil
+	})
+
+	client, err := NewClient(mock.URL(), wsTestToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	entity, err := client.UpdateEntity("light.test", map[string]interface{}{"name": "Test"})
+	if err != nil {
+		t.Fatalf("UpdateEntity() error = %v", err)
+	}
+	if entity == nil {
+		t.Fatal("UpdateEntity() returned nil entity")
+	}
+	if entity.EntityID != "" {
+		t.Errorf("entity.EntityID = %q, want empty", entity.EntityID)
+	}
+}
+
+func TestWSClient_GetThemes(t *testing.T) {
+	mock := testutil.NewWSMock(t, wsTestToken)
+	mock.Handle("frontend/get_themes", func(msg map[string]interface{}) (interface{}, error) {
+		return map[string]interface{}{
+			"themes": map[string]interface{}{
+				"midnight": map[string]interface{}{"primary-color": "#000"},
+			},
+			"default_theme":      "default",
+			"default_dark_theme": nil,
+		}, nil
+	})
+
+	client, err := NewClient(mock.URL(), wsTestToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	themes, err := client.GetThemes()
+	if err != nil {
+		t.Fatalf("GetThemes() error = %v", err)
+	}
+	if _, ok := themes.Themes["midnight"]; !ok || len(themes.Themes) != 1 {
+		t.Errorf("Themes = %v, want midnight", themes.Themes)
+	}
+	if themes.DefaultTheme != "default" || themes.DefaultDarkTheme != nil {
+		t.Errorf("defaults = %q, %v", themes.DefaultTheme, themes.DefaultDarkTheme)
+	}
+}
+
+func TestWSClient_GetConfigFlows(t *testing.T) {
+	mock := testutil.NewWSMock(t, wsTestToken)
+	mock.Handle("config_entries/flow/progress", func(msg map[string]interface{}) (interface{}, error) {
+		return []map[string]interface{}{
+			{
+				"flow_id": "abc123",
+				"handler": "hue",
+				"step_id": "link",
+				"context": map[string]interface{}{
+					"source":             "zeroconf",
+					"unique_id":          "001788fffe",
+					"title_placeholders": map[string]interface{}{"name": "Hue Bridge"},
+				},
+			},
+		}, nil
+	})
+
+	client, err := NewClient(mock.URL(), wsTestToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	flows, err := client.GetConfigFlows()
+	if err != nil {
+		t.Fatalf("GetConfigFlows() error = %v", err)
+	}
+	if len(flows) != 1 {
+		t.Fatalf("GetConfigFlows() returned %d flows, want 1", len(flows))
+	}
+	if flows[0].Handler != "hue" {
+		t.Errorf("flows[0].Handler = %q, want %q", flows[0].Handler, "hue")
+	}
+	if flows[0].StepID != "link" {
+		t.Errorf("flows[0].StepID = %q, want %q", flows[0].StepID, "link")
+	}
+	if flows[0].Context.Source != "zeroconf" {
+		t.Errorf("flows[0].Context.Source = %q, want %q", flows[0].Context.Source, "zeroconf")
+	}
+}
+
+func TestWSClient_AuthRequiredDelayed(t *testing.T) {
+	mock := testutil.NewWSMock(t, wsTestToken)
+	mock.SetAuthDelay(300 * time.Millisecond)
+
+	// The delay exceeds the client timeout but stays within the grace period
+	client, err := NewClient(mock.URL(), wsTestToken, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+}
+
+func TestWSClient_AuthRequiredTimeout(t *testing.T) {
+	oldGrace := authRequiredGrace
+	authRequiredGrace = 100 * time.Millisecond
+	defer func() { authRequiredGrace = oldGrace }()
+
+	mock := testutil.NewWSMock(t, wsTestToken)
+	mock.SetAuthDelay(500 * time.Millisecond)
+
+	_, err := NewClient(mock.URL(), wsTestToken, 100*time.Millisecond)
+	if err == nil {
+		t.Fatal("NewClient() expected timeout error")
+	}
+	if !strings.Contains(err.Error(), "timed out waiting for auth_required") {
+		t.Errorf("NewClient() error = %q, want timeout waiting for auth_required", err)
+	}
+}
+
+func TestWSClient_GetEntity(t *testing.T) {
+	mock := testutil.NewWSMock(t, wsTestToken)
+	mock.Handle("config/entity_registry/get", func(msg map[string]interface{}) (interface{}, error) {
+		if msg["entity_id"] != "sensor.temp" {
+			return nil, fmt.Errorf("entity not found")
+		}
+		return map[string]interface{}{
+			"entity_id":       "sensor.temp",
+			"device_id":       "dev1",
+			"has_entity_name": true,
+			"original_name":   "Temperature",
+		}, nil
+	})
+
+	client, err := NewClient(mock.URL(), wsTestToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	entity, err := client.GetEntity("sensor.temp")
+	if err != nil {
+		t.Fatalf("GetEntity() error = %v", err)
+	}
+	if !entity.HasEntityName {
+		t.Error("entity.HasEntityName = false, want true")
+	}
+	if entity.DeviceID == nil || *entity.DeviceID != "dev1" {
+		t.Errorf("entity.DeviceID = %v, want %q", entity.DeviceID, "dev1")
+	}
+
+	if _, err := client.GetEntity("sensor.missing"); err == nil {
+		t.Error("GetEntity() expected error for missing entity")
+	}
+}
+
+func TestWSClient_ListExposedEntities(t *testing.T) {
+	mock := testutil.NewWSMock(t, wsTestToken)
+	mock.Handle("homeassistant/expose_entity/list", func(msg map[string]interface{}) (interface{}, error) {
+		return map[string]interface{}{
+			"exposed_entities": map[string]interface{}{
+				"light.kitchen": map[string]bool{"conversation": true, "cloud.alexa": false},
+			},
+		}, nil
+	})
+
+	client, err := NewClient(mock.URL(), wsTestToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	exposed, err := client.ListExposedEntities()
+	if err != nil {
+		t.Fatalf("ListExposedEntities() error = %v", err)
+	}
+	if !exposed["light.kitchen"]["conversation"] {
+		t.Error("light.kitchen conversation = false, want true")
+	}
+	if exposed["light.kitchen"]["cloud.alexa"] {
+		t.Error("light.kitchen cloud.alexa = true, want false")
+	}
+}
+
+func TestWSClient_UnknownCommand(t *testing.T) {
+	mock := testutil.NewWSMock(t, wsTestToken)
+	mock.Handle("fail", func(msg map[string]interface{}) (interface{}, error) {
+		return nil, fmt.Errorf("boom")
+	})
+
+	client, err := NewClient(mock.URL(), wsTestToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	err = client.ExposeEntities([]string{"conversation"}, []string{"light.kitchen"}, true)
+	if !IsUnknownCommand(err) {
+		t.Errorf("IsUnknownCommand(%v) = false, want true", err)
+	}
+	if err.Error() != "unknown_command: Unknown command: homeassistant/expose_entity" {
+		t.Errorf("error = %q", err.Error())
+	}
+
+	_, err = client.SendCommand("fail", nil)
+	if err == nil || IsUnknownCommand(err) {
+		t.Errorf("IsUnknownCommand(%v) = true, want false", err)
+	}
+}
+
+func TestWSClient_ListBackups(t *testing.T) {
+	mock := testutil.NewWSMock(t, wsTestToken)
+	mock.Handle("backup/info", func(msg map[string]interface{}) (interface{}, error) {
+		return map[string]interface{}{
+			"backing_up": false,
+			"backups": []map[string]interface{}{
+				{"backup_id": "abc123", "name": "Core 2025.1.0", "date": "2025-01-05T03:00:00+00:00", "size": 12.5},
+				{"slug": "old456", "name": "Core 2024.6.0", "date": "2024-06-01T03:00:00+00:00", "size": 10.0, "path": "/config/backups/old456.tar"},
+			},
+		}, nil
+	})
+
+	client, err := NewClient(mock.URL(), wsTestToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	backups, err := client.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups() error = %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("len(backups) = %d, want 2", len(backups))
+	}
+	if backups[0].BackupID != "abc123" || backups[0].Name != "Core 2025.1.0" || backups[0].Size != 12.5 {
+		t.Errorf("backups[0] = %+v", backups[0])
+	}
+	if backups[1].BackupID != "old456" {
+		t.Errorf("backups[1].BackupID = %q, want %q (from slug)", backups[1].BackupID, "old456")
+	}
+}
+
+func TestWSClient_CreateBackup(t *testing.T) {
+	tests := []struct {
+		name       string
+		backup     string
+		agents     []string
+		result     map[string]interface{}
+		wantID     string
+		wantName   interface{}
+		wantAgents string
+	}{
+		{name: "job id", backup: "Nightly", agents: []string{"backup.local", "cloud.cloud"}, result: map[string]interface{}{"backup_job_id": "job1"}, wantID: "job1", wantName: "Nightly", wantAgents: "[backup.local cloud.cloud]"},
+		{name: "legacy slug", result: map[string]interface{}{"slug": "abc123"}, wantID: "abc123", wantAgents: "<nil>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := testutil.NewWSMock(t, wsTestToken)
+			var gotName, gotAgents interface{}
+			mock.Handle("backup/generate", func(msg map[string]interface{}) (interface{}, error) {
+				gotName, gotAgents = msg["name"], msg["agent_ids"]
+				return tt.result, nil
+			})
+
+			client, err := NewClient(mock.URL(), wsTestToken, 5*time.Second)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			defer client.Close()
+
+			id, err := client.CreateBackup(tt.backup, tt.agents)
+			if err != nil {
+				t.Fatalf("CreateBackup() error = %v", err)
+			}
+			if id != tt.wantID {
+				t.Errorf("CreateBackup() = %q, want %q", id, tt.wantID)
+			}
+			if gotName != tt.wantName {
+				t.Errorf("name sent = %v, want %v", gotName, tt.wantName)
+			}
+			if fmt.Sprint(gotAgents) != tt.wantAgents {
+				t.Errorf("agent_ids sent = %v, want %s", gotAgents, tt.wantAgents)
+			}
+		})
+	}
+}
+
+func TestWSClient_ListBackupAgents(t *testing.T) {
+	mock := testutil.NewWSMock(t, wsTestToken)
+	mock.Handle("backup/agents/info", func(msg map[string]interface{}) (interface{}, error) {
+		return map[string]interface{}{
+			"agents": []map[string]interface{}{
+				{"agent_id": "backup.local", "name": "local"},
+				{"agent_id": "cloud.cloud", "name": "cloud"},
+			},
+		}, nil
+	})
+
+	client, err := NewClient(mock.URL(), wsTestToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	agents, err := client.ListBackupAgents()
+	if err != nil {
+		t.Fatalf("ListBackupAgents() error = %v", err)
+	}
+	if len(agents) != 2 || agents[0].AgentID != "backup.local" || agents[1].Name != "cloud" {
+		t.Errorf("ListBackupAgents() = %+v", agents)
+	}
+}
+
+func TestWSClient_Reconnect(t *testing.T) {
+	mock := testutil.NewWSMock(t, wsTestToken)
+	// Each connection is served by its own goroutine, so count atomically
+	var calls atomic.Int32
+	mock.Handle("ping_item", func(msg map[string]interface{}) (interface{}, error) {
+		if calls.Add(1) == 1 {
+			return nil, testutil.ErrDropConnection
+		}
+		return map[string]interface{}{"ok": true}, nil
+	})
+
+	client, err := NewClient(mock.URL(), wsTestToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	_, err = client.SendCommand("ping_item", nil)
+	if !IsConnectionError(err) {
+		t.Fatalf("IsConnectionError(%v) = false, want true", err)
+	}
+
+	if err := client.Reconnect(); err != nil {
+		t.Fatalf("Reconnect() error = %v", err)
+	}
+
+	if _, err := client.SendCommand("ping_item", nil); err != nil {
+		t.Errorf("SendCommand() after reconnect error = %v", err)
+	}
+
+	_, err = client.SendCommand("unknown", nil)
+	if IsConnectionError(err) {
+		t.Errorf("IsConnectionError(%v) = true, want false", err)
+	}
+}
+
+func TestWSClient_SubscribeConnectionError(t *testing.T) {
+	mock := testutil.NewWSMock(t, wsTestToken)
+	mock.Handle("subscribe_events", func(msg map[string]interface{}) (interface{}, error) {
+		if msg["event_type"] == "state_changed" {
+			return nil, testutil.ErrDropConnection
+		}
+		return nil, &testutil.WSError{Code: "invalid_format", Message: "bad event type"}
+	})
+
+	client, err := NewClient(mock.URL(), wsTestToken, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	_, err = client.SubscribeEvents("state_changed")
+	if !IsConnectionError(err) {
+		t.Fatalf("IsConnectionError(%v) = false, want true", err)
+	}
+
+	if err := client.Reconnect(); err != nil {
+		t.Fatalf("Reconnect() error = %v", err)
+	}
+
+	_, err = client.SubscribeEvents("bogus")
+	if err == nil || IsConnectionError(err) {
+		t.Errorf("SubscribeEvents() of a refused subscription error = %v, want a non-connection error", err)
+	}
+}
@@ -42,12 +42,17 @@ type ResultMessage struct {
 	Error   *ErrorResult    `json:"error,omitempty"`
 }
 
-// ErrorResult contains error details from a failed command.
+// ErrorResult contains error details from a failed command. SendCommand
+// returns it as the error when the server rejects a command.
 type ErrorResult struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 }
 
+func (e *ErrorResult) Error() string {
+	return e.Code + ": " + e.Message
+}
+
 // CommandMessage is a generic command sent to the server.
 type CommandMessage struct {
 	ID   int    `json:"id"`
@@ -117,6 +122,24 @@ type Area struct {
 	Picture  *string  `json:"picture"`
 }
 
+// Label represents a label from the label registry.
+type Label struct {
+	LabelID     string  `json:"label_id"`
+	Name        string  `json:"name"`
+	Color       *string `json:"color"`
+	Icon        *string `json:"icon"`
+	Description *string `json:"description"`
+}
+
+// Floor represents a floor from the floor registry.
+type Floor struct {
+	FloorID string   `json:"floor_id"`
+	Name    string   `json:"name"`
+	Level   *int     `json:"level"`
+	Icon    *string  `json:"icon"`
+	Aliases []string `json:"aliases"`
+}
+
 // Entity represents an entity from the entity registry.
 type Entity struct {
 	EntityID       string            `json:"entity_id"`
@@ -162,6 +185,10 @@ type EventMessage struct {
 	ID    int        `json:"id"`
 	Type  string     `json:"type"`
 	Event EventData  `json:"event"`
+
+	// Raw is the undecoded message as received from the server. It keeps
+	// event data that EventData does not model (non state_changed events).
+	Raw json.RawMessage `json:"-"`
 }
 
 // EventData contains the event payload.
@@ -180,6 +207,51 @@ type StateChangedData struct {
 	NewState *StateObject `json:"new_state"`
 }
 
+// StateTriggerData is the trigger variable of an event from a
+// subscribe_trigger subscription to a state trigger.
+type StateTriggerData struct {
+	Platform  string       `json:"platform"`
+	EntityID  string       `json:"entity_id"`
+	FromState *StateObject `json:"from_state"`
+	ToState   *StateObject `json:"to_state"`
+}
+
+// StateChange converts an event from a state trigger subscription into the
+// form of a state_changed event. It returns false for any other event.
+func (m *EventMessage) StateChange() (*EventData, bool) {
+	var msg struct {
+		Event struct {
+			Variables struct {
+				Trigger StateTriggerData `json:"trigger"`
+			} `json:"variables"`
+			Context EventContext `json:"context"`
+		} `json:"event"`
+	}
+	if err := json.Unmarshal(m.Raw, &msg); err != nil {
+		return nil, false
+	}
+
+	trigger := msg.Event.Variables.Trigger
+	if trigger.Platform != "state" || trigger.EntityID == "" {
+		return nil, false
+	}
+
+	event := &EventData{
+		EventType: "state_changed",
+		Data: StateChangedData{
+			EntityID: trigger.EntityID,
+			OldState: trigger.FromState,
+			NewState: trigger.ToState,
+		},
+		Context: msg.Event.Context,
+	}
+	if trigger.ToState != nil {
+		event.TimeFired = trigger.ToState.LastUpdated
+	}
+
+	return event, true
+}
+
 // StateObject represents an entity state.
 type StateObject struct {
 	EntityID    string                 `json:"entity_id"`
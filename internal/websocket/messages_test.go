@@ -1,6 +1,7 @@
 package websocket
 
 import (
+	"encoding/json"
 	"testing"
 )
 
@@ -207,6 +208,33 @@ func TestEntity_GetOriginalName(t *testing.T) {
 	}
 }
 
+func TestEventMessage_StateChange(t *testing.T) {
+	raw := `{"id":3,"type":"event","event":{"variables":{"trigger":{"id":"0","idx":"0","platform":"state","entity_id":"light.kitchen",
+		"from_state":{"entity_id":"light.kitchen","state":"off"},
+		"to_state":{"entity_id":"light.kitchen","state":"on","last_updated":"2024-01-15T12:00:00+00:00"}}},
+		"context":{"id":"ctx1"}}}`
+
+	msg := EventMessage{Raw: json.RawMessage(raw)}
+	event, ok := msg.StateChange()
+	if !ok {
+		t.Fatal("StateChange() ok = false, want true")
+	}
+	if event.EventType != "state_changed" || event.Data.EntityID != "light.kitchen" {
+		t.Errorf("event = %+v", event)
+	}
+	if event.Data.OldState == nil || event.Data.OldState.State != "off" || event.Data.NewState == nil || event.Data.NewState.State != "on" {
+		t.Errorf("states = %+v -> %+v", event.Data.OldState, event.Data.NewState)
+	}
+	if event.TimeFired != "2024-01-15T12:00:00+00:00" || event.Context.ID != "ctx1" {
+		t.Errorf("time fired = %q, context = %+v", event.TimeFired, event.Context)
+	}
+
+	msg = EventMessage{Raw: json.RawMessage(`{"id":1,"type":"event","event":{"event_type":"state_changed","data":{"entity_id":"light.kitchen"}}}`)}
+	if _, ok := msg.StateChange(); ok {
+		t.Error("StateChange() ok = true for a state_changed event, want false")
+	}
+}
+
 func strPtr(s string) *string {
 	return &s
 }
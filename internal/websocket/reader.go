@@ -0,0 +1,159 @@
+package websocket
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// eventBuffer is how many events are queued for ReadEvent before the reader
+// stops reading from the connection.
+const eventBuffer = 256
+
+// errClosed is returned to waiters when the connection was closed without a
+// read error.
+var errClosed = errors.New("connection closed")
+
+// reader owns all reads from one connection. A single goroutine reads every
+// message, hands each result to the goroutine waiting for its ID and queues
+// events for ReadEvent, so commands and event reads never compete for the
+// connection.
+type reader struct {
+	mu      sync.Mutex
+	waiters map[int]chan *ResultMessage // guarded by mu
+	err     error                       // guarded by mu; set once done is closed
+
+	lastRead atomic.Int64 // UnixNano of the last message received
+
+	events chan *EventMessage
+	done   chan struct{}
+
+	quit     chan struct{}
+	quitOnce sync.Once
+}
+
+// newReader starts reading from conn, which must already be authenticated.
+func newReader(conn *websocket.Conn) *reader {
+	r := &reader{
+		waiters: make(map[int]chan *ResultMessage),
+		events:  make(chan *EventMessage, eventBuffer),
+		done:    make(chan struct{}),
+		quit:    make(chan struct{}),
+	}
+	r.lastRead.Store(time.Now().UnixNano())
+	go r.run(conn)
+	return r
+}
+
+// run reads messages until the connection fails or is closed.
+func (r *reader) run(conn *websocket.Conn) {
+	// Authentication leaves a read deadline behind. Command timeouts are
+	// enforced by waitResult, and event streams may be idle indefinitely.
+	conn.SetReadDeadline(time.Time{})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			r.stop(err)
+			return
+		}
+		r.lastRead.Store(time.Now().UnixNano())
+
+		var base Message
+		if err := json.Unmarshal(data, &base); err != nil {
+			continue // Skip messages we can't parse
+		}
+
+		switch base.Type {
+		case "result":
+			var result ResultMessage
+			if err := json.Unmarshal(data, &result); err != nil {
+				continue
+			}
+			r.deliver(&result)
+		case "pong":
+			// Pongs carry no result; report them as a successful one
+			r.deliver(&ResultMessage{ID: base.ID, Type: base.Type, Success: true})
+		case "event":
+			var event EventMessage
+			if err := json.Unmarshal(data, &event); err != nil {
+				continue
+			}
+			event.Raw = data
+			// Block rather than drop events when nobody keeps up with them
+			select {
+			case r.events <- &event:
+			case <-r.quit:
+				r.stop(errClosed)
+				return
+			}
+		}
+	}
+}
+
+// register reserves a slot for the result of id. It must be called before
+// the command is written, or the result could arrive first and be dropped.
+func (r *reader) register(id int) (chan *ResultMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return nil, r.err
+	}
+	ch := make(chan *ResultMessage, 1)
+	r.waiters[id] = ch
+	return ch, nil
+}
+
+// cancel drops the slot for id, e.g. after its caller gave up waiting.
+func (r *reader) cancel(id int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.waiters, id)
+}
+
+// deliver hands result to its waiter. Results nobody waits for are dropped.
+func (r *reader) deliver(result *ResultMessage) {
+	r.mu.Lock()
+	ch, ok := r.waiters[result.ID]
+	delete(r.waiters, result.ID)
+	r.mu.Unlock()
+
+	if ok {
+		ch <- result
+	}
+}
+
+// stop records why reading ended and wakes everyone waiting.
+func (r *reader) stop(err error) {
+	if err == nil {
+		err = errClosed
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.err = err
+	close(r.done)
+}
+
+// close makes run return even while it is blocked queueing an event. The
+// connection must be closed separately.
+func (r *reader) close() {
+	r.quitOnce.Do(func() { close(r.quit) })
+}
+
+// idleFor returns how long ago the last message was received.
+func (r *reader) idleFor() time.Duration {
+	return time.Since(time.Unix(0, r.lastRead.Load()))
+}
+
+// readErr returns why the reader stopped. Only valid once done is closed.
+func (r *reader) readErr() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}